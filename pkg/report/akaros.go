@@ -4,6 +4,7 @@
 package report
 
 import (
+	"context"
 	"regexp"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
@@ -17,7 +18,7 @@ type akaros struct {
 }
 
 func ctorAkaros(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
 	ctx := &akaros{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
@@ -31,10 +32,34 @@ func (ctx *akaros) ContainsCrash(output []byte) bool {
 	panic("not implemented")
 }
 
+func (ctx *akaros) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	panic("not implemented")
+}
+
 func (ctx *akaros) Parse(output []byte) *Report {
 	panic("not implemented")
 }
 
+func (ctx *akaros) ParseFrom(output []byte, startPos int) *Report {
+	panic("not implemented")
+}
+
+func (ctx *akaros) Title(output []byte) string {
+	panic("not implemented")
+}
+
 func (ctx *akaros) Symbolize(rep *Report) error {
 	panic("not implemented")
 }
+
+func (ctx *akaros) SymbolizeContext(c context.Context, rep *Report) error {
+	panic("not implemented")
+}
+
+func (ctx *akaros) SymbolizeFrame(frame *StackFrame) error {
+	panic("not implemented")
+}
+
+func (ctx *akaros) Close() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/google/syzkaller/pkg/symbolizer"
+)
+
+type auto struct {
+	reporters []Reporter
+}
+
+// NewAutoReporter creates reporter that detects OS of the console output.
+// It probes the output with reporters for all OSes with implemented parsing and dispatches
+// Parse/Symbolize to the one that matches. If several OSes match,
+// the one with the earliest crash in the output is preferred.
+// Note: Symbolize requires kernelSrc/kernelObj/symbols to correspond to the actual
+// kernel, so the auto reporter is primarily intended for classification of crashes.
+func NewAutoReporter(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
+	ignores []*regexp.Regexp) (Reporter, error) {
+	ctx := &auto{}
+	for _, os := range parsingOSes {
+		reporter, err := NewReporter(os, kernelSrc, kernelObj, symbols, ignores)
+		if err != nil {
+			return nil, err
+		}
+		ctx.reporters = append(ctx.reporters, reporter)
+	}
+	return ctx, nil
+}
+
+func (ctx *auto) ContainsCrash(output []byte) bool {
+	for _, reporter := range ctx.reporters {
+		if reporter.ContainsCrash(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsCrashDetailed returns counts of the detected OS, since the same line can be suppressed
+// by several OSes. If no OS is detected, counts of the first OS (in order of preference)
+// that has crashes, suppressed or ignored lines are returned.
+func (ctx *auto) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	if reporter, _ := ctx.detect(output, 0); reporter != nil {
+		return reporter.ContainsCrashDetailed(output)
+	}
+	for _, reporter := range ctx.reporters {
+		if found, suppressed, ignored := reporter.ContainsCrashDetailed(output); found || suppressed+ignored != 0 {
+			return found, suppressed, ignored
+		}
+	}
+	return false, 0, 0
+}
+
+func (ctx *auto) Parse(output []byte) *Report {
+	return ctx.ParseFrom(output, 0)
+}
+
+func (ctx *auto) ParseFrom(output []byte, startPos int) *Report {
+	_, rep := ctx.detect(output, startPos)
+	return rep
+}
+
+// Title needs to parse output with all matching reporters to find the earliest crash,
+// so it is not cheaper than Parse.
+func (ctx *auto) Title(output []byte) string {
+	_, rep := ctx.detect(output, 0)
+	if rep == nil {
+		return ""
+	}
+	return rep.Title
+}
+
+func (ctx *auto) Symbolize(rep *Report) error {
+	return ctx.SymbolizeContext(context.Background(), rep)
+}
+
+func (ctx *auto) SymbolizeContext(c context.Context, rep *Report) error {
+	reporter, _ := ctx.detect(rep.Output, rep.StartPos)
+	if reporter == nil {
+		return fmt.Errorf("failed to detect OS of the report")
+	}
+	return reporter.SymbolizeContext(c, rep)
+}
+
+// SymbolizeFrame is not supported, since OS can't be detected from a single frame.
+func (ctx *auto) SymbolizeFrame(frame *StackFrame) error {
+	return fmt.Errorf("auto reporter can't symbolize frames")
+}
+
+func (ctx *auto) Close() error {
+	var firstErr error
+	for _, reporter := range ctx.reporters {
+		if err := reporter.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Region markers of all OSes are used, since OS of a region is not known in advance.
+func (ctx *auto) regionEnd(line []byte) bool {
+	for _, reporter := range ctx.reporters {
+		if regionMarkerOf(reporter).regionEnd(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *auto) outputLost(line []byte) bool {
+	for _, reporter := range ctx.reporters {
+		if regionMarkerOf(reporter).outputLost(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *auto) newCrash(region, line []byte) bool {
+	for _, reporter := range ctx.reporters {
+		if regionMarkerOf(reporter).newCrash(region, line) {
+			return true
+		}
+	}
+	return false
+}
+
+// Boot markers of all OSes are used as well.
+func (ctx *auto) bootMarkers() (boots, suppressed []*regexp.Regexp) {
+	for _, reporter := range ctx.reporters {
+		b, s := bootMarkersOf(reporter)
+		boots = append(boots, b...)
+		suppressed = append(suppressed, s...)
+	}
+	return boots, suppressed
+}
+
+// detect returns reporter that matches the earliest crash in output[startPos:]
+// and the report it produces, or nil if no reporter matches.
+func (ctx *auto) detect(output []byte, startPos int) (Reporter, *Report) {
+	var best Reporter
+	var bestRep *Report
+	for _, reporter := range ctx.reporters {
+		if !reporter.ContainsCrash(output[clampPos(startPos, len(output)):]) {
+			continue
+		}
+		rep := reporter.ParseFrom(output, startPos)
+		if rep == nil {
+			continue
+		}
+		if bestRep == nil || rep.StartPos < bestRep.StartPos {
+			best, bestRep = reporter, rep
+		}
+	}
+	return best, bestRep
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestAutoParse(t *testing.T) {
+	const linuxLog = `
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+[  772.919010] Call Trace:
+[  772.919010]  [<ffffffff82d4e304>] __memset+0x24/0x30
+`
+	const freebsdLog = `
+panic: ffs_write: type 0xfffff80036275000 4 (0,3)
+cpuid = 0
+KDB: stack backtrace:
+`
+	tests := []struct {
+		log   string
+		title string
+	}{
+		{linuxLog, "BUG: unable to handle kernel paging request in __memset"},
+		{freebsdLog, "panic: ffs_write: type ADDR X (Y,Z)"},
+		// When several OSes match, the earliest crash wins.
+		{freebsdLog + linuxLog, "panic: ffs_write: type ADDR X (Y,Z)"},
+		{linuxLog + freebsdLog, "BUG: unable to handle kernel paging request in __memset"},
+		{"no crashes here\n", ""},
+	}
+	reporter, err := NewAutoReporter("", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		containsCrash := reporter.ContainsCrash([]byte(test.log))
+		if containsCrash != (test.title != "") {
+			t.Fatalf("#%v: ContainsCrash returned %v", i, containsCrash)
+		}
+		rep := reporter.Parse([]byte(test.log))
+		title := ""
+		if rep != nil {
+			title = rep.Title
+		}
+		if title != test.title {
+			t.Fatalf("#%v: got title %q, want %q", i, title, test.title)
+		}
+		if title1 := reporter.Title([]byte(test.log)); title1 != title {
+			t.Fatalf("#%v: Title returned %q, want %q", i, title1, title)
+		}
+	}
+}
+
+func TestAutoContainsCrashDetailed(t *testing.T) {
+	// The executor oops is matched by all OSes, but the ignored line must be counted once.
+	const ignoredLog = "panic: executor 1: ignored failure\n"
+	const linuxLog = `
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+`
+	reporter, err := NewAutoReporter("", "", nil, []*regexp.Regexp{regexp.MustCompile("ignored failure")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		log     string
+		found   bool
+		ignored int
+	}{
+		{ignoredLog, false, 1},
+		{ignoredLog + linuxLog, true, 1},
+		{"no crashes here\n", false, 0},
+	}
+	for i, test := range tests {
+		found, suppressed, ignored := reporter.ContainsCrashDetailed([]byte(test.log))
+		if found != test.found || suppressed != 0 || ignored != test.ignored {
+			t.Errorf("#%v: got found %v, suppressed %v, ignored %v, want %v, 0, %v",
+				i, found, suppressed, ignored, test.found, test.ignored)
+		}
+	}
+}
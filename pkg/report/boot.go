@@ -0,0 +1,81 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"regexp"
+	"sort"
+)
+
+// ParseAll returns crashes from console output that can span several boots of the machine,
+// at most one report per boot. Boots are separated by the kernel boot banner
+// (e.g. "Linux version ..." line on linux), output preceding the first banner is a boot as well.
+// A crash region never extends past the end of its boot, so console output of the next boot
+// (e.g. with another crash) is not merged into the report. Boot of the returned reports
+// is set to index of the boot. StartPos and EndPos are relative to the whole output,
+// but Output of a report is truncated at the end of its boot.
+// Boots are further split at notices about lost output (e.g. "printk: N messages suppressed"
+// on linux): lots of output can be lost there, so oopses on both sides are reported separately
+// (one report per such part of a boot) rather than merged into one report.
+// For OSes without such markers (currently all except linux) the whole output is a single boot.
+func ParseAll(reporter Reporter, output []byte) []*Report {
+	var reps []*Report
+	boots, suppressed := bootMarkersOf(reporter)
+	for boot, bounds := range splitAt(output, [2]int{0, len(output)}, boots) {
+		for _, region := range splitAt(output, bounds, suppressed) {
+			rep := reporter.ParseFrom(output[:region[1]], region[0])
+			if rep == nil {
+				continue
+			}
+			rep.Boot = boot
+			reps = append(reps, rep)
+		}
+	}
+	return reps
+}
+
+// bootMarker is implemented by reporters that know how boots and lost output are marked
+// in console output.
+type bootMarker interface {
+	// bootMarkers returns regexps that match the first line of a boot
+	// and lines before which lots of output can be lost.
+	bootMarkers() (boots, suppressed []*regexp.Regexp)
+}
+
+// bootMarkersOf returns boot markers of reporter, looking through reporter wrappers.
+func bootMarkersOf(reporter Reporter) (boots, suppressed []*regexp.Regexp) {
+	switch r := reporter.(type) {
+	case bootMarker:
+		return r.bootMarkers()
+	case *corruptedDropper:
+		return bootMarkersOf(r.Reporter)
+	case *titlePrefixer:
+		return bootMarkersOf(r.Reporter)
+	}
+	return nil, nil
+}
+
+// splitAt splits [start, end) part of output at lines matching any of res,
+// the matching lines start new parts.
+func splitAt(output []byte, bounds [2]int, res []*regexp.Regexp) [][2]int {
+	var positions []int
+	for _, re := range res {
+		for _, match := range re.FindAllIndex(output[bounds[0]:bounds[1]], -1) {
+			positions = append(positions, bounds[0]+match[0])
+		}
+	}
+	sort.Ints(positions)
+	var parts [][2]int
+	start := bounds[0]
+	for _, pos := range positions {
+		if pos != start {
+			parts = append(parts, [2]int{start, pos})
+		}
+		start = pos
+	}
+	if start != bounds[1] {
+		parts = append(parts, [2]int{start, bounds[1]})
+	}
+	return parts
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseAll(t *testing.T) {
+	const (
+		boot0 = `[    0.000000] Linux version 4.15.0-rc4+ (syzkaller@ci) (gcc version 7.1.1) #1 SMP
+[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/core/dev.c:200
+[   42.320059] Kernel panic - not syncing: panic_on_warn set ...
+`
+		boot1 = `[    0.000000] Linux version 4.15.0-rc4+ (syzkaller@ci) (gcc version 7.1.1) #1 SMP
+[    5.100000] random console noise
+`
+		boot2 = `[    0.000000][    T0] Linux version 4.15.0-rc4+ (syzkaller@ci) (gcc version 7.1.1) #1 SMP
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`
+	)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := []byte(boot0 + boot1 + boot2)
+	// Without boot boundaries both crashes end up in a single region.
+	if rep := reporter.Parse(output); !bytes.Contains(rep.Report, []byte("ip6_dst_store")) {
+		t.Fatalf("the test does not check merging of crashes:\n%s", rep.Report)
+	}
+	reps := ParseAll(reporter, output)
+	if len(reps) != 2 {
+		t.Fatalf("got %v reports, want 2", len(reps))
+	}
+	tests := []struct {
+		title string
+		boot  int
+		start int
+		end   int
+	}{
+		{"WARNING in foo_bar", 0, 0, len(boot0)},
+		{"KASAN: use-after-free Read in ip6_dst_store", 2, len(boot0 + boot1), len(output)},
+	}
+	for i, test := range tests {
+		rep := reps[i]
+		if rep.Title != test.title || rep.Boot != test.boot {
+			t.Errorf("report #%v: got %q in boot %v, want %q in boot %v",
+				i, rep.Title, rep.Boot, test.title, test.boot)
+		}
+		if rep.StartPos < test.start || rep.EndPos > test.end {
+			t.Errorf("report #%v: region [%v, %v) is out of boot [%v, %v)",
+				i, rep.StartPos, rep.EndPos, test.start, test.end)
+		}
+	}
+	if bytes.Contains(reps[0].Report, []byte("random console noise")) {
+		t.Errorf("the first report contains the next boot:\n%s", reps[0].Report)
+	}
+	if reps := ParseAll(reporter, []byte(boot1)); len(reps) != 0 {
+		t.Errorf("got reports for output without crashes: %+v", reps)
+	}
+}
+
+func TestParseAllSuppressed(t *testing.T) {
+	const (
+		first = `[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/core/dev.c:200
+`
+		notice = `[   60.000001] printk: 18233 messages suppressed.
+`
+		second = `[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`
+	)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := []byte(first + notice + second)
+	// Without the boundary both crashes end up in a single region.
+	if rep := reporter.Parse(output); !bytes.Contains(rep.Report, []byte("ip6_dst_store")) {
+		t.Fatalf("the test does not check merging of crashes:\n%s", rep.Report)
+	}
+	reps := ParseAll(reporter, output)
+	if len(reps) != 2 {
+		t.Fatalf("got %v reports, want 2", len(reps))
+	}
+	if reps[0].Title != "WARNING in foo_bar" || reps[1].Title != "KASAN: use-after-free Read in ip6_dst_store" {
+		t.Fatalf("got reports %q and %q", reps[0].Title, reps[1].Title)
+	}
+	if bytes.Contains(reps[0].Report, []byte("ip6_dst_store")) {
+		t.Errorf("the first report contains the second oops:\n%s", reps[0].Report)
+	}
+	if reps[1].StartPos < len(first+notice) || reps[0].Boot != 0 || reps[1].Boot != 0 {
+		t.Errorf("bad second report: start %v, boots %v/%v", reps[1].StartPos, reps[0].Boot, reps[1].Boot)
+	}
+	var titles []string
+	if err := ParseStream(reporter, bytes.NewReader(output), func(rep *Report) {
+		titles = append(titles, rep.Title)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(titles) != 2 {
+		t.Errorf("ParseStream returned reports %q, want 2 reports", titles)
+	}
+}
+
+func TestParseAllNoBootMarkers(t *testing.T) {
+	// Linux boot banners and printk notices are regular output for other OSes.
+	output := []byte(`panic: page fault
+cpuid = 0
+Linux version 4.15.0-rc4+ (syzkaller@ci) (gcc version 7.1.1) #1 SMP
+printk: 18233 messages suppressed.
+KDB: stack backtrace:
+#0 0xffffffff80aada97 at kdb_backtrace+0x67
+`)
+	reporter, err := NewReporter("freebsd", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reps := ParseAll(reporter, output)
+	if len(reps) != 1 {
+		t.Fatalf("got %v reports, want 1", len(reps))
+	}
+	if !bytes.Equal(reps[0].Output, output) || reps[0].Boot != 0 {
+		t.Fatalf("the report is truncated at a linux marker or boot %v:\n%s", reps[0].Boot, reps[0].Output)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// CorpusResult is the result of checking a single log from a corpus (see CheckCorpus).
+type CorpusResult struct {
+	// Log is the path to the log file.
+	Log    string
+	Passed bool
+	// Title and Corrupted are what Parse returned (empty title if no crash was found).
+	Title     string
+	Corrupted bool
+	// ExpectedTitle and ExpectedCorrupted come from the expected output file.
+	ExpectedTitle     string
+	ExpectedCorrupted bool
+}
+
+func (res *CorpusResult) String() string {
+	if res.Passed {
+		return fmt.Sprintf("%v: ok", res.Log)
+	}
+	return fmt.Sprintf("%v: got title %q (corrupted %v), want %q (corrupted %v)",
+		res.Log, res.Title, res.Corrupted, res.ExpectedTitle, res.ExpectedCorrupted)
+}
+
+// CorpusExpectedSuffix is the suffix of expected output files in a corpus.
+const CorpusExpectedSuffix = ".expected"
+
+// CheckCorpus parses each console log in dir with the reporter for os and compares results
+// with the expected output. Expected output of log file NAME is in NAME.expected file:
+// the first line is the expected title (an empty file means that no crash is expected),
+// and optional "CORRUPTED" second line says that the report is expected to be corrupted.
+// Logs are only classified, so no kernel symbols are required.
+// Results are sorted by log file name. Returned error means that the corpus can't be read.
+func CheckCorpus(os, dir string) ([]CorpusResult, error) {
+	reporter, err := NewReporter(os, "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	files, err := osutil.ListDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var results []CorpusResult
+	for _, file := range files {
+		if strings.HasSuffix(file, CorpusExpectedSuffix) {
+			continue
+		}
+		logFile := filepath.Join(dir, file)
+		log, err := ioutil.ReadFile(logFile)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := ioutil.ReadFile(logFile + CorpusExpectedSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read expected output for %v: %v", logFile, err)
+		}
+		res := CorpusResult{
+			Log: logFile,
+		}
+		lines := strings.Split(strings.TrimRight(string(expected), "\r\n"), "\n")
+		res.ExpectedTitle = strings.TrimSpace(lines[0])
+		if len(lines) > 1 {
+			res.ExpectedCorrupted = strings.TrimSpace(lines[1]) == "CORRUPTED"
+		}
+		if rep := reporter.Parse(log); rep != nil {
+			res.Title = rep.Title
+			res.Corrupted = rep.Corrupted
+		}
+		res.Passed = res.Title == res.ExpectedTitle && res.Corrupted == res.ExpectedCorrupted
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckCorpus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syz-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	const kasan = `
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+[   67.392150]  udpv6_sendmsg+0x1a39/0x2b60 net/ipv6/udp.c:1341
+`
+	const corrupted = `
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+`
+	files := map[string]string{
+		"0":                    kasan,
+		"0.expected":           "KASAN: use-after-free Read in ip6_dst_store\n",
+		"1":                    corrupted,
+		"1.expected":           "KASAN: use-after-free Read in ip6_dst_store\nCORRUPTED\n",
+		"2":                    "no crash here\n",
+		"2.expected":           "",
+		"3-mismatch":           kasan,
+		"3-mismatch.expected":  "KASAN: use-after-free Write in ip6_dst_store\n",
+		"4-corrupted":          corrupted,
+		"4-corrupted.expected": "KASAN: use-after-free Read in ip6_dst_store\n",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := CheckCorpus("linux", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const title = "KASAN: use-after-free Read in ip6_dst_store"
+	want := []CorpusResult{
+		{filepath.Join(dir, "0"), true, title, false, title, false},
+		{filepath.Join(dir, "1"), true, title, true, title, true},
+		{filepath.Join(dir, "2"), true, "", false, "", false},
+		{filepath.Join(dir, "3-mismatch"), false, title, false, "KASAN: use-after-free Write in ip6_dst_store", false},
+		{filepath.Join(dir, "4-corrupted"), false, title, true, title, false},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("bad results:\n%+v\nwant:\n%+v", results, want)
+	}
+	if err := os.Remove(filepath.Join(dir, "0.expected")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckCorpus("linux", dir); err == nil {
+		t.Fatalf("no error for missing expected output")
+	}
+}
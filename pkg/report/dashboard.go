@@ -0,0 +1,57 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+)
+
+// DashboardFormatVersion is the version of the DashboardReport wire format.
+// It must be incremented on any incompatible change of the format
+// (renamed/removed fields or changed semantics of existing fields).
+const DashboardFormatVersion = 1
+
+// DashboardReport is a crash report in the dashboard wire format.
+// Title, Corrupted, Maintainers and Report correspond to the same fields of dashapi.Crash.
+// It's deliberately decoupled from Report, so that changes to Report don't affect
+// what is sent to the dashboard.
+type DashboardReport struct {
+	// Version is the format version (DashboardFormatVersion).
+	Version     int      `json:"Version"`
+	Title       string   `json:"Title"`
+	Corrupted   bool     `json:"Corrupted"`
+	Maintainers []string `json:"Maintainers"`
+	// FirstLine and LastLine are 1-based numbers of the first and the last lines of the crash
+	// in the console output (Report.StartPos/EndPos), 0 if the report has no console output.
+	FirstLine int    `json:"FirstLine"`
+	LastLine  int    `json:"LastLine"`
+	Report    []byte `json:"Report"`
+}
+
+// DashboardReport returns the report in the dashboard wire format.
+// The returned value does not share memory with the report.
+func (rep *Report) DashboardReport() *DashboardReport {
+	res := &DashboardReport{
+		Version:     DashboardFormatVersion,
+		Title:       rep.Title,
+		Corrupted:   rep.Corrupted,
+		Maintainers: append([]string{}, rep.Maintainers...),
+		Report:      append([]byte{}, rep.Report...),
+	}
+	if len(rep.Output) != 0 {
+		start := clampPos(rep.StartPos, len(rep.Output))
+		end := clampPos(rep.EndPos, len(rep.Output))
+		if end > start && rep.Output[end-1] == '\n' {
+			// EndPos points either at the new line of the last line (linux)
+			// or after it, the new line itself does not start another line.
+			end--
+		}
+		if end < start {
+			end = start
+		}
+		res.FirstLine = bytes.Count(rep.Output[:start], []byte{'\n'}) + 1
+		res.LastLine = bytes.Count(rep.Output[:end], []byte{'\n'}) + 1
+	}
+	return res
+}
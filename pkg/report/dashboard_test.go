@@ -0,0 +1,67 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDashboardReport(t *testing.T) {
+	const log = `syzkaller login: [   10.000000] random: crng init done
+[   12.345678] BUG: KASAN: use-after-free in foo+0x10/0x20
+[   12.345678] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor0/4321
+[   12.345678] Call Trace:
+[   12.345678]  foo+0x10/0x20 net/core/foo.c:10
+[   12.345678]  bar+0x10/0x20 net/core/bar.c:20
+[   13.000000] executing program
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	rep.Maintainers = []string{"foo@bar.com", "linux-kernel@vger.kernel.org"}
+	data, err := json.MarshalIndent(rep.DashboardReport(), "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The golden serialization pins the wire format, it must not be changed
+	// without incrementing DashboardFormatVersion. Report is base64-encoded as any []byte.
+	const golden = `{
+	"Version": 1,
+	"Title": "KASAN: use-after-free Read in foo",
+	"Corrupted": false,
+	"Maintainers": [
+		"foo@bar.com",
+		"linux-kernel@vger.kernel.org"
+	],
+	"FirstLine": 2,
+	"LastLine": 2,
+	"Report": "cmFuZG9tOiBjcm5nIGluaXQgZG9uZQpCVUc6IEtBU0FOOiB1c2UtYWZ0ZXItZnJlZSBpbiBmb28rMHgxMC8weDIwClJlYWQgb2Ygc2l6ZSA4IGF0IGFkZHIgZmZmZjg4MDFjNmExYTA4MCBieSB0YXNrIHN5ei1leGVjdXRvcjAvNDMyMQpDYWxsIFRyYWNlOgogZm9vKzB4MTAvMHgyMCBuZXQvY29yZS9mb28uYzoxMAogYmFyKzB4MTAvMHgyMCBuZXQvY29yZS9iYXIuYzoyMApleGVjdXRpbmcgcHJvZ3JhbQo="
+}`
+	if string(data) != golden {
+		t.Fatalf("bad serialization:\n%s\nwant:\n%s", data, golden)
+	}
+	// The wire report does not share memory with the report.
+	dash := rep.DashboardReport()
+	dash.Maintainers[0] = ""
+	dash.Report[0] = 0
+	if rep.Maintainers[0] == "" || rep.Report[0] == 0 {
+		t.Fatalf("the dashboard report shares memory with the report")
+	}
+	if dash := (&Report{Title: "foo"}).DashboardReport(); dash.FirstLine != 0 || dash.LastLine != 0 {
+		t.Fatalf("got lines %v-%v for a report without output", dash.FirstLine, dash.LastLine)
+	}
+	// EndPos may point either at the new line of the last line or after it.
+	for _, end := range []int{3, 4} {
+		rep := &Report{Output: []byte("a\nb\nc\n"), StartPos: 2, EndPos: end}
+		if dash := rep.DashboardReport(); dash.FirstLine != 2 || dash.LastLine != 2 {
+			t.Fatalf("EndPos %v: got lines %v-%v, want 2-2", end, dash.FirstLine, dash.LastLine)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package report
 
 import (
 	"bytes"
+	"context"
 	"regexp"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
@@ -15,37 +16,66 @@ type freebsd struct {
 	kernelObj string
 	symbols   map[string][]symbolizer.Symbol
 	ignores   []*regexp.Regexp
+	oopses    []*oops
+	opts      Options
 }
 
 func ctorFreebsd(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
+	oopses, err := disableFormats(freebsdOopses, opts.DisabledFormats)
+	if err != nil {
+		return nil, err
+	}
+	if oopses, err = onlyFamilies(oopses, opts.OnlyFamilies); err != nil {
+		return nil, err
+	}
 	ctx := &freebsd{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
 		symbols:   symbols,
 		ignores:   ignores,
+		opts:      opts,
+		oopses:    oopses,
 	}
 	return ctx, nil
 }
 
 func (ctx *freebsd) ContainsCrash(output []byte) bool {
-	return containsCrash(output, freebsdOopses, ctx.ignores)
+	return containsCrash(output, ctx.oopses, ctx.ignores, ctx.opts.maxLineLen(), ctx.opts.TolerantHeaders)
+}
+
+func (ctx *freebsd) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	return containsCrashDetailed(output, ctx.oopses, ctx.ignores, ctx.opts.maxLineLen(),
+		ctx.opts.TolerantHeaders)
 }
 
 func (ctx *freebsd) Parse(output []byte) *Report {
+	return ctx.ParseFrom(output, 0)
+}
+
+func (ctx *freebsd) ParseFrom(output []byte, startPos int) *Report {
+	rep := ctx.parse(output, startPos)
+	metricsParsed(rep)
+	return rep
+}
+
+func (ctx *freebsd) parse(output []byte, startPos int) *Report {
+	startPos = clampPos(startPos, len(output))
+	output = redact(output, ctx.opts.Redactors)
 	rep := &Report{
 		Output: output,
 	}
 	var oops *oops
-	for pos := 0; pos < len(output); {
+	for pos := startPos; pos < len(output); {
 		next := bytes.IndexByte(output[pos:], '\n')
 		if next != -1 {
 			next += pos
 		} else {
 			next = len(output)
 		}
-		for _, oops1 := range freebsdOopses {
-			match := matchOops(output[pos:next], oops1, ctx.ignores)
+		for _, oops1 := range ctx.oopses {
+			line := capLine(output[pos:next], ctx.opts.maxLineLen())
+			match := matchOops(line, oops1, ctx.ignores, ctx.opts.TolerantHeaders)
 			if match == -1 {
 				continue
 			}
@@ -71,19 +101,49 @@ func (ctx *freebsd) Parse(output []byte) *Report {
 	if oops == nil {
 		return nil
 	}
-	rep.Title, _, _ = extractDescription(output[rep.StartPos:], oops)
+	title, _, format, confidence := extractDescription(output[rep.StartPos:], oops, ctx.opts.FullFuncNames,
+		ctx.opts.TolerantHeaders, nil)
+	rep.Title = title
+	rep.MatchedFormat = format.name
+	rep.Confidence = confidence
+	rep.ExecutorCrash = format.executor
+	rep.Severity = ctx.opts.severity("freebsd", oops, format, title)
 	return rep
 }
 
+func (ctx *freebsd) Title(output []byte) string {
+	rep := ctx.parse(output, 0)
+	if rep == nil {
+		return ""
+	}
+	return rep.Title
+}
+
 func (ctx *freebsd) Symbolize(rep *Report) error {
+	return ctx.SymbolizeContext(context.Background(), rep)
+}
+
+func (ctx *freebsd) SymbolizeContext(c context.Context, rep *Report) error {
+	metricsSymbolized(metricsStart(), nil)
+	return nil
+}
+
+func (ctx *freebsd) SymbolizeFrame(frame *StackFrame) error {
+	return nil
+}
+
+func (ctx *freebsd) Close() error {
 	return nil
 }
 
 var freebsdOopses = []*oops{
+	// Must go before the generic "panic:" oops, which would match executor failures as well.
+	executorOops,
 	&oops{
 		[]byte("Fatal trap"),
 		[]oopsFormat{
 			{
+				name: "fatal-trap",
 				title: compile("Fatal trap (.+?)\\r?\\n(?:.*\\n)+?" +
 					"KDB: stack backtrace:\\r?\\n" +
 					"(?:#[0-9]+ {{ADDR}} at (?:kdb_backtrace|vpanic|panic|trap_fatal|" +
@@ -98,6 +158,7 @@ var freebsdOopses = []*oops{
 		[]byte("panic:"),
 		[]oopsFormat{
 			{
+				name:  "ffs-write",
 				title: compile("panic: ffs_write: type {{ADDR}} [0-9]+ \\([0-9]+,[0-9]+\\)"),
 				fmt:   "panic: ffs_write: type ADDR X (Y,Z)",
 			},
@@ -9,6 +9,7 @@ import (
 
 func TestFreebsdParse(t *testing.T) {
 	testParse(t, "freebsd", freebsdTests)
+	testTitle(t, "freebsd", freebsdTests)
 }
 
 var freebsdTests = []ParseTest{
@@ -125,5 +126,12 @@ KDB: stack backtrace:
 #10 0xffffffff80ec392b at Xfast_syscall+0xfb
 `, `panic: ffs_write: type ADDR X (Y,Z)`,
 		false,
+	}, {
+		`
+panic: executor 0: failed: pthread_create failed (errno 35)
+
+goroutine 13 [running]:
+`, `executor crash: failed: pthread_create failed (errno 35)`,
+		false,
 	},
 }
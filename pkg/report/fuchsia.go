@@ -4,6 +4,7 @@
 package report
 
 import (
+	"context"
 	"regexp"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
@@ -17,7 +18,7 @@ type fuchsia struct {
 }
 
 func ctorFuchsia(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
 	ctx := &fuchsia{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
@@ -31,10 +32,34 @@ func (ctx *fuchsia) ContainsCrash(output []byte) bool {
 	panic("not implemented")
 }
 
+func (ctx *fuchsia) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	panic("not implemented")
+}
+
 func (ctx *fuchsia) Parse(output []byte) *Report {
 	panic("not implemented")
 }
 
+func (ctx *fuchsia) ParseFrom(output []byte, startPos int) *Report {
+	panic("not implemented")
+}
+
+func (ctx *fuchsia) Title(output []byte) string {
+	panic("not implemented")
+}
+
 func (ctx *fuchsia) Symbolize(rep *Report) error {
 	panic("not implemented")
 }
+
+func (ctx *fuchsia) SymbolizeContext(c context.Context, rep *Report) error {
+	panic("not implemented")
+}
+
+func (ctx *fuchsia) SymbolizeFrame(frame *StackFrame) error {
+	panic("not implemented")
+}
+
+func (ctx *fuchsia) Close() error {
+	return nil
+}
@@ -6,12 +6,16 @@ package report
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/mail"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/syzkaller/pkg/osutil"
@@ -28,10 +32,20 @@ type linux struct {
 	questionableRe      *regexp.Regexp
 	guiltyFileBlacklist []*regexp.Regexp
 	eoi                 []byte
+	oopses              []*oops
+	opts                Options
+	// State of SymbolizeFrame.
+	frameMu    sync.Mutex
+	frameSymb  Symbolizer
+	frameCache map[uint64][]symbolizer.Frame
+	frameStrip string
+	// Source files read for StackFrame.SourceLine, nil for files that are not present.
+	sourceMu    sync.Mutex
+	sourceCache map[string][]string
 }
 
 func ctorLinux(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
 	vmlinux := ""
 	if kernelObj != "" {
 		vmlinux = filepath.Join(kernelObj, "vmlinux")
@@ -43,14 +57,36 @@ func ctorLinux(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symb
 			}
 		}
 	}
+	if opts.Arch != "" && linuxArch(opts.Arch).name != opts.Arch {
+		return nil, fmt.Errorf("unknown arch %q", opts.Arch)
+	}
+	disabled := opts.DisabledFormats
+	if !opts.StackTraceDumps {
+		disabled = append(append([]string{}, disabled...), linuxStackTraceFormats...)
+	}
+	oopses, err := disableFormats(linuxOopses, disabled)
+	if err != nil {
+		return nil, err
+	}
+	if oopses, err = onlyFamilies(oopses, opts.OnlyFamilies); err != nil {
+		return nil, err
+	}
+	if opts.SuppressInfraLimits {
+		oopses = suppressFormats(oopses, func(f *oopsFormat) bool { return f.infra })
+	}
+	if opts.SuppressInformational {
+		oopses = suppressFormats(oopses, func(f *oopsFormat) bool { return f.informational })
+	}
 	ctx := &linux{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
 		vmlinux:   vmlinux,
 		symbols:   symbols,
 		ignores:   ignores,
+		opts:      opts,
+		oopses:    oopses,
 	}
-	ctx.consoleOutputRe = regexp.MustCompile(`^(?:\*\* [0-9]+ printk messages dropped \*\* )?(?:.* login: )?(?:\<[0-9]+\>)?\[ *[0-9]+\.[0-9]+\] `)
+	ctx.consoleOutputRe = regexp.MustCompile(`^(?:\*\* (?:[0-9]+ printk messages dropped|replaying previous printk message) \*\* )*(?:.* login: )?(?:\<[0-9]+\>)?\[ *[0-9]+\.[0-9]+\] `)
 	ctx.questionableRe = regexp.MustCompile(`(?:\[\<[0-9a-f]+\>\])? \? +[a-zA-Z0-9_.]+\+0x[0-9a-f]+/[0-9a-f]+`)
 	ctx.eoi = []byte("<EOI>")
 	ctx.guiltyFileBlacklist = []*regexp.Regexp{
@@ -76,244 +112,1748 @@ func ctorLinux(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symb
 }
 
 func (ctx *linux) ContainsCrash(output []byte) bool {
-	return containsCrash(output, linuxOopses, ctx.ignores)
+	return containsCrash(output, ctx.oopses, ctx.ignores, ctx.opts.maxLineLen(), ctx.opts.TolerantHeaders)
+}
+
+func (ctx *linux) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	return containsCrashDetailed(output, ctx.oopses, ctx.ignores, ctx.opts.maxLineLen(),
+		ctx.opts.TolerantHeaders)
 }
 
 func (ctx *linux) Parse(output []byte) *Report {
+	return ctx.ParseFrom(output, 0)
+}
+
+func (ctx *linux) ParseFrom(output []byte, startPos int) *Report {
+	rep := ctx.parse(output, startPos)
+	metricsParsed(rep)
+	return rep
+}
+
+func (ctx *linux) parse(output []byte, startPos int) *Report {
+	startPos = clampPos(startPos, len(output))
+	return ctx.parseScan(output, startPos, startPos)
+}
+
+func (ctx *linux) regionEnd(line []byte) bool {
+	return linuxOopsEndRe.Match(line)
+}
+
+func (ctx *linux) outputLost(line []byte) bool {
+	return linuxSuppressedRe.Match(line)
+}
+
+func (ctx *linux) bootMarkers() (boots, suppressed []*regexp.Regexp) {
+	return []*regexp.Regexp{linuxBootRe}, []*regexp.Regexp{linuxSuppressedRe}
+}
+
+// newCrash returns whether line with an oops header starts a new crash rather than continues
+// the crash in region. Usual consequences of a crash are: the oops counter line
+// ("Oops: 0000 [#1]" after "BUG: unable to handle kernel paging request"), the final kernel panic,
+// more oopses of the same kind (e.g. other hung tasks), linuxConsequences (e.g. RCU stall
+// for a soft lockup, see resolveHang), and any oopses that are printed before the stack trace of the first oops
+// (e.g. "WARNING: ... at lib/list_debug.c" before "kernel BUG at lib/list_debug.c").
+// Informational messages don't mask crashes that follow them, so a crash after them is not split.
+func (ctx *linux) newCrash(region, line []byte) bool {
+	oops1 := ctx.lineOops(line)
+	if oops1 == nil || oopsCountRe.Match(line) || bytes.HasPrefix(oops1.header, []byte("Kernel panic")) {
+		return false
+	}
+	var header *oops
+	var headerLine []byte
+	for _, ln := range bytes.Split(ctx.extractConsoleOutput(region), []byte{'\n'}) {
+		if header == nil {
+			if header = ctx.lineOops(ln); header != nil {
+				headerLine = ln
+			}
+			continue
+		}
+		if linuxStackTitleRe.Match(ln) {
+			return header != oops1 && !isInformational(header) && !linuxConsequence(headerLine, line)
+		}
+	}
+	return false
+}
+
+// linuxConsequence returns whether oops header line next is a known consequence
+// of the oops with header line first.
+func linuxConsequence(first, next []byte) bool {
+	for _, c := range linuxConsequences {
+		if c[0].Match(first) && c[1].Match(next) {
+			return true
+		}
+	}
+	return false
+}
+
+// linuxConsequences are pairs of oops header regexps, where an oops matching the second regexp
+// is printed as a consequence of an oops matching the first one.
+var linuxConsequences = [][2]*regexp.Regexp{
+	{linuxSoftLockupRe, linuxRCUStallRe},
+	{linuxRCUStallRe, linuxSoftLockupRe},
+	{regexp.MustCompile(`sysfs: cannot create duplicate filename`), regexp.MustCompile(`kobject_add_internal failed`)},
+}
+
+// lineOops returns the first oops with a header in line, or nil.
+func (ctx *linux) lineOops(line []byte) *oops {
+	line = capLine(line, ctx.opts.maxLineLen())
+	for _, oops := range ctx.oopses {
+		if matchOops(line, oops, ctx.ignores, ctx.opts.TolerantHeaders) != -1 {
+			return oops
+		}
+	}
+	return nil
+}
+
+// findOopsLine is like findOops, but uses precomputed lines of the output.
+// Returns index of the first line with an oops header, or -1.
+func (ctx *linux) findOopsLine(lines *outputLines) int {
+	for i := 0; i < lines.count(); i++ {
+		start, end := lines.bounds(i)
+		line := capLine(lines.output[start:end], ctx.opts.maxLineLen())
+		for _, oops := range ctx.oopses {
+			if matchOops(line, oops, ctx.ignores, ctx.opts.TolerantHeaders) != -1 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseLines is Parse for precomputed lines of the output, where oopsLine is the result
+// of findOopsLine. Lines before the oops matter only as the report prefix,
+// so scanning starts at the 5th console line preceding the oops.
+func (ctx *linux) parseLines(lines *outputLines, oopsLine int) *Report {
+	if len(ctx.opts.Redactors) != 0 || ctx.opts.ContextWindow != 0 {
+		// Redaction can change oops matching, and the time window context
+		// can include more than 5 preceding lines.
+		return ctx.ParseFrom(lines.output, 0)
+	}
+	scanPos := len(lines.output)
+	if oopsLine != -1 {
+		i := oopsLine
+		for prefix := 0; i > 0 && prefix < 5; {
+			i--
+			start, end := lines.bounds(i)
+			if ctx.isConsoleLine(lines.output[start:end]) {
+				prefix++
+			}
+		}
+		scanPos, _ = lines.bounds(i)
+	}
+	rep := ctx.parseScan(lines.output, 0, scanPos)
+	metricsParsed(rep)
+	return rep
+}
+
+// parseScan parses output starting at startPos, but starts scanning lines at scanPos
+// (scanPos >= startPos). Lines in between must not contain oops headers.
+func (ctx *linux) parseScan(output []byte, startPos, scanPos int) *Report {
+	output = redact(output, ctx.opts.Redactors)
 	rep := &Report{
 		Output: output,
 	}
 	var oops *oops
-	var textPrefix [][]byte
+	var textPrefix []prefixLine
+	contextPos := -1
 	textLines := 0
 	skipText := false
-	for pos := 0; pos < len(output); {
+	budget := newParseBudget(ctx.opts.ParseBudget)
+	for pos, lines := scanPos, 1; pos < len(output); lines++ {
+		if lines%256 == 0 && budget.exceeded() {
+			break
+		}
 		next := bytes.IndexByte(output[pos:], '\n')
 		if next != -1 {
 			next += pos
 		} else {
 			next = len(output)
 		}
-		for _, oops1 := range linuxOopses {
-			match := matchOops(output[pos:next], oops1, ctx.ignores)
+		for _, oops1 := range ctx.oopses {
+			line := capLine(output[pos:next], ctx.opts.maxLineLen())
+			match := matchOops(line, oops1, ctx.ignores, ctx.opts.TolerantHeaders)
 			if match == -1 {
 				continue
 			}
-			if oops == nil {
+			// Informational messages don't mask crashes that follow them.
+			if oops == nil || isInformational(oops) && !isInformational(oops1) {
 				oops = oops1
 				rep.StartPos = pos
 				rep.Title = string(output[pos+match : next])
 			}
 			rep.EndPos = next
 		}
-		if ctx.consoleOutputRe.Match(output[pos:next]) &&
-			(!ctx.questionableRe.Match(output[pos:next]) ||
-				bytes.Index(output[pos:next], ctx.eoi) != -1) {
-			lineStart := bytes.Index(output[pos:next], []byte("] ")) + pos + 2
-			lineEnd := next
-			if lineEnd != 0 && output[lineEnd-1] == '\r' {
-				lineEnd--
+		if ctx.isConsoleLine(output[pos:next]) {
+			lineStart, lineEnd := ctx.consoleLine(output, pos, next)
+			ts, hasTS := ctx.contextTimestamp(output[pos:lineStart])
+			if oops == nil {
+				textPrefix = append(textPrefix, prefixLine{
+					text:  append([]byte{}, output[lineStart:lineEnd]...),
+					pos:   pos,
+					ts:    ts,
+					hasTS: hasTS,
+				})
+				textPrefix = ctx.trimPrefix(textPrefix, ts, hasTS)
+			} else {
+				// Prepend 5 lines (or lines within Options.ContextWindow) preceding start
+				// of the report, they can contain additional info related to the report.
+				textPrefix = ctx.trimPrefix(textPrefix, ts, hasTS)
+				if ctx.opts.ContextWindow != 0 && len(textPrefix) != 0 {
+					contextPos = textPrefix[0].pos
+				}
+				for _, prefix := range textPrefix {
+					rep.Report = append(rep.Report, prefix.text...)
+					rep.Report = append(rep.Report, '\n')
+				}
+				textPrefix = nil
+				textLines++
+				ln := output[lineStart:lineEnd]
+				skipLine := skipText
+				if bytes.Contains(ln, []byte("Disabling lock debugging due to kernel taint")) {
+					skipLine = true
+				} else if textLines > 40 && bytes.Contains(ln, []byte("Kernel panic - not syncing")) {
+					// If panic_on_warn set, then we frequently have 2 stacks:
+					// one for the actual report (or maybe even more than one),
+					// and then one for panic caused by panic_on_warn. This makes
+					// reports unnecessary long and the panic (current) stack
+					// is always present in the actual report. So we strip the
+					// panic message. However, we check that we have enough lines
+					// before the panic, because sometimes we have, for example,
+					// a single WARNING line without a stack and then the panic
+					// with the stack.
+					skipText = true
+					skipLine = true
+				}
+				if !skipLine {
+					rep.Report = append(rep.Report, ln...)
+					rep.Report = append(rep.Report, '\n')
+				}
+			}
+		}
+		pos = next + 1
+	}
+	if oops == nil {
+		if budget.exceeded() || !ctx.opts.TruncatedHeads {
+			return nil
+		}
+		return ctx.parseTruncatedHead(rep, startPos)
+	}
+	if budget.exceeded() {
+		return ctx.budgetExceeded(rep, oops, rep.Title)
+	}
+	oops, titlePos := ctx.resolveHang(output, oops, rep.StartPos)
+	desc := ctx.describe(output, oops, titlePos, budget)
+	if budget.exceeded() {
+		return ctx.budgetExceeded(rep, oops, desc.title)
+	}
+	consoleOutput, title, report, format := desc.consoleOutput, desc.title, desc.report, desc.format
+	corruptedReason := desc.corruptedReason
+	if corruptedReason != "" && len(rep.Report) == 0 {
+		rep.Report = report
+	}
+	rep.Title = title
+	rep.MatchedFormat = format.name
+	rep.Confidence = desc.confidence
+	rep.Unclassified = desc.confidence == ConfidenceHeader
+	rep.ExecFault = format.execFault
+	rep.UserAccessFault = format.userAccessFault
+	rep.InfraLimit = format.infra
+	rep.Informational = format.informational
+	if format.executor {
+		rep.ExecutorCrash = true
+		rep.Report = report
+	}
+	rep.CorruptedReason = ctx.isCorrupted(title, report, format)
+	if rep.CorruptedReason == "" {
+		rep.CorruptedReason = corruptedReason
+	}
+	rep.Corrupted = rep.CorruptedReason != ""
+	if rep.Corrupted {
+		rep.Confidence = ConfidenceCorrupted
+	}
+	// Later oopses are usually consequences of the first one, so the title is
+	// always taken from the first oops, but we note how many of them we've seen.
+	rep.OopsCount = extractOopsCount(consoleOutput)
+	// The message is printed after the oops that the task hit on exit, it's not an oops on its own.
+	rep.Fatal = bytes.Contains(consoleOutput, linuxRecursiveFault)
+	rep.Recursive = rep.OopsCount > 1 || rep.Fatal
+	rep.Comm, rep.Taint, rep.KernelVersion = extractTaskInfo(consoleOutput)
+	rep.Arch = ctx.arch(consoleOutput)
+	// Fault codes have different encodings on different architectures.
+	switch {
+	case isX86(rep.Arch):
+		extractPageFault(rep, consoleOutput)
+		extractFaultPC(rep)
+	case isArm(rep.Arch):
+		rep.ESR, rep.HasESR = extractESR(consoleOutput)
+	}
+	rep.CodeBytes, rep.CodeFault = extractCodeBytes(rep.Arch, consoleOutput)
+	extractNonCanonical(rep, consoleOutput)
+	extractFaultAddr(rep, consoleOutput)
+	extractRCUProtection(rep, format)
+	rep.HeldLocks = parseHeldLocks(rep.Report)
+	parseLinuxStacks(rep)
+	if !format.noStackTrace {
+		rep.stackText = linuxStackText
+	}
+	rep.excerpt = ctx.excerpt
+	rep.GuiltyFrame = -1
+	if !rep.Unclassified || !ctx.opts.RawUnclassifiedTitles {
+		rep.Title = buildLinuxTitle(rep.Arch, rep.Title, report, format, &ctx.opts)
+	}
+	rep.Severity = ctx.opts.severity("linux", oops, format, rep.Title)
+	if contextPos != -1 {
+		rep.StartPos = contextPos
+	}
+	return rep
+}
+
+// prefixLine is a console line preceding start of a report.
+type prefixLine struct {
+	text  []byte
+	pos   int
+	ts    time.Duration
+	hasTS bool
+}
+
+// trimPrefix drops report prefix lines that are out of context of a line printed at ts:
+// lines printed more than Options.ContextWindow before ts (or after ts, e.g. in the previous boot).
+// If the window is not set or the line has no timestamp, only the last 5 lines are kept.
+func (ctx *linux) trimPrefix(prefix []prefixLine, ts time.Duration, hasTS bool) []prefixLine {
+	if ctx.opts.ContextWindow == 0 || !hasTS {
+		if len(prefix) > 5 {
+			prefix = prefix[len(prefix)-5:]
+		}
+		return prefix
+	}
+	for len(prefix) != 0 && (!prefix[0].hasTS || prefix[0].ts < ts-ctx.opts.ContextWindow || prefix[0].ts > ts) {
+		prefix = prefix[1:]
+	}
+	return prefix
+}
+
+// contextTimestamp parses console timestamp (e.g. "[   42.262162] ") in prefix of a console line.
+// Timestamps are needed only for Options.ContextWindow, so they are not parsed if it's not set.
+func (ctx *linux) contextTimestamp(prefix []byte) (time.Duration, bool) {
+	if ctx.opts.ContextWindow == 0 {
+		return 0, false
+	}
+	match := linuxTimestampRe.FindSubmatch(prefix)
+	if match == nil {
+		return 0, false
+	}
+	sec, err := strconv.ParseFloat(string(match[1]), 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(sec * float64(time.Second)), true
+}
+
+// Title returns the same title as Parse would return for output (empty if there is no crash),
+// but skips the rest of the analysis (report text, corruption checks, stacks, etc).
+func (ctx *linux) Title(output []byte) string {
+	output = redact(output, ctx.opts.Redactors)
+	oops, startPos := ctx.findOops(output)
+	if oops == nil {
+		if !ctx.opts.TruncatedHeads {
+			return ""
+		}
+		title, _, _, _ := ctx.truncatedHead(output, 0)
+		return title
+	}
+	oops, startPos = ctx.resolveHang(output, oops, startPos)
+	desc := ctx.describe(output, oops, startPos, nil)
+	if desc.confidence == ConfidenceHeader && ctx.opts.RawUnclassifiedTitles {
+		return desc.title
+	}
+	return buildLinuxTitle(ctx.arch(desc.consoleOutput), desc.title, desc.report, desc.format, &ctx.opts)
+}
+
+// budgetExceeded finishes rep when Options.ParseBudget is exceeded: title is the best title
+// found so far (the oops header line if formats were not matched), the rest of the analysis is skipped.
+func (ctx *linux) budgetExceeded(rep *Report, oops *oops, title string) *Report {
+	title = strings.TrimSuffix(title, "\r")
+	if len(title) > maxDescLen {
+		title = title[:maxDescLen]
+	}
+	rep.Title = buildLinuxTitle("", title, nil, oopsFormat{}, &ctx.opts)
+	rep.Corrupted = true
+	rep.CorruptedReason = budgetExceededReason
+	rep.Confidence = ConfidenceCorrupted
+	rep.GuiltyFrame = -1
+	rep.Severity = ctx.opts.severity("linux", oops, oopsFormat{}, rep.Title)
+	return rep
+}
+
+// resolveHang handles soft lockup and RCU stall reports for the same hang: both watchdogs
+// fire at about the same time, and the first banner is not necessarily the most informative one
+// (e.g. RCU stall is detected on an idle CPU, while the soft lockup stack shows the stuck task).
+// If oops0 at pos is one of them and a banner of the other kind follows later in output,
+// the banner whose stack has more non-idle frames (not counting the interrupt part) is selected.
+// On ties the first banner wins, so titles don't change when both stacks are equally specific.
+// Returns the selected oops and position of its banner line.
+func (ctx *linux) resolveHang(output []byte, oops0 *oops, pos int) (*oops, int) {
+	lineEnd := bytes.IndexByte(output[pos:], '\n')
+	if lineEnd == -1 {
+		return oops0, pos
+	}
+	lineEnd += pos
+	line := output[pos:lineEnd]
+	var other *regexp.Regexp
+	switch {
+	case linuxSoftLockupRe.Match(line):
+		other = linuxRCUStallRe
+	case linuxRCUStallRe.Match(line):
+		other = linuxSoftLockupRe
+	default:
+		return oops0, pos
+	}
+	match := other.FindIndex(output[lineEnd:])
+	if match == nil {
+		return oops0, pos
+	}
+	otherPos := bytes.LastIndexByte(output[:lineEnd+match[0]], '\n') + 1
+	otherEnd := len(output)
+	if next := bytes.IndexByte(output[otherPos:], '\n'); next != -1 {
+		otherEnd = otherPos + next
+	}
+	var otherOops *oops
+	for _, oops1 := range ctx.oopses {
+		line := capLine(output[otherPos:otherEnd], ctx.opts.maxLineLen())
+		if matchOops(line, oops1, ctx.ignores, ctx.opts.TolerantHeaders) != -1 {
+			otherOops = oops1
+			break
+		}
+	}
+	if otherOops == nil {
+		return oops0, pos
+	}
+	first := ctx.extractConsoleOutput(output[pos:otherPos])
+	second := ctx.extractConsoleOutput(output[otherPos:])
+	if linuxHangScore(ctx.arch(first), first) >= linuxHangScore(ctx.arch(second), second) {
+		return oops0, pos
+	}
+	return otherOops, otherPos
+}
+
+// linuxHangScore returns number of non-idle frames in the main stack of the hang report,
+// frames in the interrupt part of the stack (before "</IRQ>") are not counted
+// since they belong to the watchdog itself.
+func linuxHangScore(arch string, report []byte) int {
+	stack := linuxStackText(arch, report)
+	if pos := bytes.LastIndex(stack, []byte("</IRQ>")); pos != -1 {
+		stack = stack[pos:]
+	}
+	score := 0
+	for _, ln := range bytes.Split(stack, []byte{'\n'}) {
+		if frame, ok := parseLinuxFrame(ln); ok && !frame.Unreliable && !linuxIdleFrameRe.MatchString(frame.Func) {
+			score++
+		}
+	}
+	return score
+}
+
+// arch returns architecture of the oops in console output: Options.Arch if set,
+// otherwise it's detected from the output (see detectLinuxArch).
+func (ctx *linux) arch(output []byte) string {
+	if ctx.opts.Arch != "" {
+		return ctx.opts.Arch
+	}
+	return detectLinuxArch(output)
+}
+
+// detectLinuxArch detects architecture by register dump and stack trace lines that are specific
+// to architectures. Returns "amd64" if none of them is found.
+func detectLinuxArch(output []byte) string {
+	for _, arch := range linuxArchs {
+		if arch.detect.Match(output) {
+			return arch.name
+		}
+	}
+	return "amd64"
+}
+
+func isX86(arch string) bool {
+	return arch == "amd64" || arch == "386"
+}
+
+func isArm(arch string) bool {
+	return arch == "arm64" || arch == "arm"
+}
+
+// findOops returns the first oops that matches a line in output and the line start position
+// (the first informational oops if there are no other oops). It must match the same oops
+// as the main loop in parse.
+func (ctx *linux) findOops(output []byte) (*oops, int) {
+	var info *oops
+	infoPos := 0
+	for pos := 0; pos < len(output); {
+		next := bytes.IndexByte(output[pos:], '\n')
+		if next != -1 {
+			next += pos
+		} else {
+			next = len(output)
+		}
+		for _, oops := range ctx.oopses {
+			line := capLine(output[pos:next], ctx.opts.maxLineLen())
+			if matchOops(line, oops, ctx.ignores, ctx.opts.TolerantHeaders) == -1 {
+				continue
+			}
+			if !isInformational(oops) {
+				return oops, pos
+			}
+			if info == nil {
+				info, infoPos = oops, pos
+			}
+		}
+		pos = next + 1
+	}
+	return info, infoPos
+}
+
+// linuxDescription is the part of a report extracted by describe.
+type linuxDescription struct {
+	consoleOutput []byte
+	title         string
+	report        []byte
+	format        oopsFormat
+	confidence    float64
+	// corruptedReason is set if the title was extracted from raw output.
+	corruptedReason string
+}
+
+// describe extracts the raw title and report text of the oops that starts at startPos in output.
+// Formats are not matched after budget is exceeded.
+func (ctx *linux) describe(output []byte, oops *oops, startPos int, budget *parseBudget) linuxDescription {
+	var desc linuxDescription
+	desc.consoleOutput = ctx.extractConsoleOutput(output[startPos:])
+	if oops == executorOops {
+		// Executor failures are printed by syz-fuzzer, not by kernel,
+		// so they are not part of console output.
+		desc.consoleOutput = output[startPos:]
+	}
+	desc.title, desc.report, desc.format, desc.confidence = extractDescription(desc.consoleOutput, oops,
+		ctx.opts.FullFuncNames, ctx.opts.TolerantHeaders, budget)
+	if desc.title == "" {
+		// The oops line matched, but is not part of console output
+		// (e.g. it was printed without console prefix). The raw output is not
+		// trustworthy since it interleaves with other output, so mark the report as corrupted.
+		desc.title, desc.report, desc.format, desc.confidence = extractDescription(output[startPos:], oops,
+			ctx.opts.FullFuncNames, ctx.opts.TolerantHeaders, budget)
+		desc.corruptedReason = "oops is not in console output"
+	}
+	return desc
+}
+
+// buildLinuxTitle produces the final report title from the title extracted with the format.
+func buildLinuxTitle(arch, title string, report []byte, format oopsFormat, opts *Options) string {
+	if format.hungTask {
+		title = extractHungTaskTitle(arch, title, report, format, opts.FullFuncNames)
+	}
+	if format.message && opts.WarningMessages {
+		// The message allows to distinguish different WARNINGs in the same function.
+		if msg := extractMessage(report); msg != "" {
+			title += ": " + msg
+		}
+	}
+	// Executor PIDs are not interesting.
+	title = executorRe.ReplaceAllLiteralString(title, "syz-executor")
+	// Replace that everything looks like an address with "ADDR",
+	// addresses in descriptions can't be good regardless of the oops regexps.
+	title = addrRe.ReplaceAllString(title, "${1}ADDR")
+	// Replace that everything looks like a decimal number with "NUM".
+	title = decNumRe.ReplaceAllString(title, "${1}NUM")
+	// Replace that everything looks like a file line number with "LINE".
+	title = lineNumRe.ReplaceAllLiteralString(title, ":LINE")
+	// Replace all raw references to runctions (e.g. "ip6_fragment+0x1052/0x2d80")
+	// with just function name ("ip6_fragment"). Offsets and sizes are not stable.
+	title = funcRe.ReplaceAllString(title, "$1")
+	// Compiler-generated function name suffixes (e.g. "foo.isra.0", "foo.cold") depend on
+	// the compiler and config, so they are stripped as well. Frames keep the full names.
+	if !opts.FullFuncNames {
+		title = funcSuffixRe.ReplaceAllString(title, "$1")
+	}
+	// CPU numbers are not interesting.
+	title = cpuRe.ReplaceAllLiteralString(title, "CPU")
+	return title
+}
+
+// parseTruncatedHead handles output that starts in the middle of an oops
+// (e.g. the beginning was lost due to console ring buffer overflow), so there is no oops header.
+// The report is extracted only if the first console line is a part of an oops
+// (a stack frame or a register dump) and an oops end marker is present.
+// The title is extracted from the oops body and the report is marked as corrupted.
+// Used only with Options.TruncatedHeads since ContainsCrash does not detect such reports.
+func (ctx *linux) parseTruncatedHead(rep *Report, startPos int) *Report {
+	title, output, format, endPos := ctx.truncatedHead(rep.Output, startPos)
+	if title == "" {
+		return nil
+	}
+	rep.StartPos = startPos
+	rep.EndPos = endPos
+	rep.Title = title
+	rep.MatchedFormat = format.name
+	rep.Severity = ctx.opts.severity("linux", linuxTruncatedOops, format, title)
+	rep.Report = output
+	rep.Arch = ctx.arch(output)
+	rep.Corrupted = true
+	rep.CorruptedReason = "truncated head"
+	rep.Confidence = ConfidenceCorrupted
+	parseLinuxStacks(rep)
+	rep.stackText = linuxStackText
+	rep.excerpt = ctx.excerpt
+	rep.GuiltyFrame = -1
+	return rep
+}
+
+// truncatedHead returns title of an oops without header that starts at startPos (see parseTruncatedHead),
+// its console output, matched format and end position of the oops end marker line.
+// The title is empty if there is no such oops.
+func (ctx *linux) truncatedHead(output []byte, startPos int) (string, []byte, oopsFormat, int) {
+	console := ctx.extractConsoleOutput(output[startPos:])
+	first := console
+	if pos := bytes.IndexByte(console, '\n'); pos != -1 {
+		first = console[:pos]
+	}
+	if !linuxFrameRe.Match(first) && !linuxArch(ctx.arch(console)).regs.Match(first) {
+		return "", nil, oopsFormat{}, 0
+	}
+	end := linuxOopsEndRe.FindIndex(output[startPos:])
+	if end == nil {
+		return "", nil, oopsFormat{}, 0
+	}
+	matched := false
+	for _, f := range linuxTruncatedOops.formats {
+		matched = matched || f.title.Match(console)
+	}
+	if !matched {
+		return "", nil, oopsFormat{}, 0
+	}
+	endPos := len(output)
+	if next := bytes.IndexByte(output[startPos+end[1]:], '\n'); next != -1 {
+		endPos = startPos + end[1] + next
+	}
+	title, _, format, _ := extractDescription(console, linuxTruncatedOops, ctx.opts.FullFuncNames, false, nil)
+	return title, console, format, endPos
+}
+
+func (ctx *linux) Symbolize(rep *Report) error {
+	return ctx.SymbolizeContext(context.Background(), rep)
+}
+
+func (ctx *linux) SymbolizeContext(c context.Context, rep *Report) error {
+	start := metricsStart()
+	err := ctx.symbolizeReport(c, rep)
+	metricsSymbolized(start, err)
+	return err
+}
+
+func (ctx *linux) symbolizeReport(c context.Context, rep *Report) error {
+	var symbolizeErr error
+	if ctx.vmlinux != "" {
+		// On cancellation the text is partially symbolized,
+		// so we still use it and fill in frames/guilty file.
+		symbolized, tooDeep, err := ctx.symbolize(c, rep.Report)
+		symbolizeErr = err
+		rep.Report = symbolized
+		if tooDeep {
+			rep.Corrupted = true
+			rep.CorruptedReason = "stack too deep"
+			rep.Confidence = ConfidenceCorrupted
+		}
+	}
+	parseLinuxStacks(rep)
+	if ctx.opts.CollapseFrames {
+		rep.Frames = collapseFrames(rep.Frames)
+		for i := range rep.AuxStacks {
+			rep.AuxStacks[i].Frames = collapseFrames(rep.AuxStacks[i].Frames)
+		}
+	}
+	refineSuspiciousRCUTitle(rep)
+	// Files of the stuck CPU stack go first, since it is the main stack of the report.
+	files := append(ctx.extractFiles(rep.stuckStack), ctx.extractFiles(rep.Report)...)
+	if isX86(rep.Arch) {
+		// Re-extract after symbolization to get the source location.
+		extractFaultPC(rep)
+		if rep.HasFaultPC && rep.FaultPC.File != "" {
+			files = append([]string{rep.FaultPC.File}, files...)
+		}
+	}
+	// The page allocation stack points to the code that owns the corrupted page.
+	files = append(pageOwnerFiles(rep), files...)
+	ctx.attachSourceLines(rep)
+	guiltyFiles := ctx.selectGuiltyFiles(files, ctx.opts.MaintainerFiles)
+	rep.GuiltyFile = ""
+	if len(guiltyFiles) != 0 {
+		rep.GuiltyFile = guiltyFiles[0]
+	}
+	rep.GuiltyFrame = guiltyFrame(rep.Frames, rep.GuiltyFile)
+	if symbolizeErr != nil {
+		return symbolizeErr
+	}
+	if len(guiltyFiles) != 0 && ctx.vmlinux != "" {
+		var err error
+		rep.Maintainers, err = ctx.getFilesMaintainers(guiltyFiles)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refineSuspiciousRCUTitle replaces source file in "suspicious RCU usage at file:LINE" title
+// with the function that contains the offending dereference, i.e. the first frame in the file
+// (after symbolization the frame may be inlined). The title is not changed if there is no such frame.
+func refineSuspiciousRCUTitle(rep *Report) {
+	if rep.MatchedFormat != "warning-suspicious-rcu" && rep.MatchedFormat != "info-suspicious-rcu" {
+		return
+	}
+	const prefix = "suspicious RCU usage at "
+	if !strings.HasPrefix(rep.Title, prefix) {
+		return
+	}
+	file := rep.Title[len(prefix):]
+	if pos := strings.IndexByte(file, ':'); pos != -1 {
+		file = file[:pos]
+	}
+	file = strings.TrimPrefix(file, "./")
+	for _, frame := range rep.Frames {
+		if frame.File != file {
+			continue
+		}
+		rep.Title = "suspicious RCU usage in " + baseFuncName(frame.Func)
+		return
+	}
+}
+
+// extractRCUProtection parses the "file:line message!" line of suspicious RCU usage reports
+// into Report.RCUProtection and Report.RCULock. The title is not affected.
+func extractRCUProtection(rep *Report, format oopsFormat) {
+	if format.name != "warning-suspicious-rcu" && format.name != "info-suspicious-rcu" {
+		return
+	}
+	match := rcuProtectionRe.FindSubmatch(rep.Report)
+	if match == nil {
+		return
+	}
+	rep.RCUProtection = string(match[1])
+	if match := rcuIllegalLockRe.FindStringSubmatch(rep.RCUProtection); match != nil {
+		rep.RCULock = rcuIllegalLocks[match[1]]
+		return
+	}
+	for _, lock := range rcuProtectionLocks {
+		if strings.Contains(rep.RCUProtection, lock.message) {
+			rep.RCULock = lock.lock
+			return
+		}
+	}
+}
+
+// rcuIllegalLocks maps functions reported as "used illegally while idle" to the lock.
+// Unlock is reported for the same idle check as the corresponding lock.
+var rcuIllegalLocks = map[string]string{
+	"rcu_read_lock":         "rcu_read_lock",
+	"rcu_read_unlock":       "rcu_read_lock",
+	"rcu_read_lock_bh":      "rcu_read_lock_bh",
+	"rcu_read_unlock_bh":    "rcu_read_lock_bh",
+	"rcu_read_lock_sched":   "rcu_read_lock_sched",
+	"rcu_read_unlock_sched": "rcu_read_lock_sched",
+	"srcu_read_lock":        "srcu_read_lock",
+	"srcu_read_unlock":      "srcu_read_lock",
+}
+
+// rcuProtectionLocks maps RCU checking messages to the lock that should have been held
+// (or, for illegal context switches, that was held while sleeping).
+// rcu_dereference_bh/sched_check() print the same message as rcu_dereference_check().
+var rcuProtectionLocks = []struct {
+	message string
+	lock    string
+}{
+	{"rcu_dereference_check()", "rcu_read_lock"},
+	{"RCU-list traversed in non-reader section", "rcu_read_lock"},
+	{"Illegal context switch in RCU read-side", "rcu_read_lock"},
+	{"Illegal context switch in RCU-bh read-side", "rcu_read_lock_bh"},
+	{"Illegal context switch in RCU-sched read-side", "rcu_read_lock_sched"},
+}
+
+// symbolize symbolizes text, the returned bool denotes that the text contains
+// more than MaxFrames frames and the rest were not symbolized.
+// If c is cancelled, it aborts the current symbolizer request
+// and returns c.Err() along with the partially symbolized text.
+func (ctx *linux) symbolize(c context.Context, text []byte) ([]byte, bool, error) {
+	symb := ctx.opts.newSymbolizer()
+	defer symb.Close()
+	if c.Done() != nil {
+		done := make(chan bool)
+		defer close(done)
+		go func() {
+			select {
+			case <-c.Done():
+				// Kills the symbolizer, so that the current request fails.
+				symb.Close()
+			case <-done:
+			}
+		}()
+	}
+	symbFunc := symbolizeWithContext(c, symb.Symbolize)
+	strip := ctx.stripPrefix(symbFunc)
+	symbolized, tooDeep := symbolizeLines(symbFunc, ctx.symbols, ctx.vmlinux, strip, text, ctx.opts.MaxFrames)
+	return symbolized, tooDeep, c.Err()
+}
+
+// stripPrefix returns vmlinux build location that is stripped from all file paths.
+func (ctx *linux) stripPrefix(symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error)) string {
+	strip, _ := filepath.Abs(ctx.vmlinux)
+	strip = filepath.Dir(strip) + string(filepath.Separator)
+	// Vmlinux may have been moved, so check if we can find debug info
+	// for __sanitizer_cov_trace_pc. We know where it is located,
+	// so we can infer correct strip prefix from it.
+	if covSymbols := ctx.symbols["__sanitizer_cov_trace_pc"]; len(covSymbols) != 0 {
+		for _, covSymb := range covSymbols {
+			frames, _ := symbFunc(ctx.vmlinux, covSymb.Addr)
+			if len(frames) > 0 {
+				file := frames[len(frames)-1].File
+				if idx := strings.Index(file, "kernel/kcov.c"); idx != -1 {
+					return file[:idx]
+				}
+			}
+		}
+	}
+	return strip
+}
+
+// SymbolizeFrame resolves source location of a single frame parsed from an unsymbolized report.
+// The frame must have Offset/Size (i.e. come from "func+0xOFF/0xSIZE" notation),
+// it gets File/Line of the function itself (inlined callees are not reported).
+// Frames that already have File are left intact. Results are cached and the symbolizer
+// is kept for the lifetime of the reporter, so this is cheap to call
+// for each frame separately. It's safe to call concurrently.
+func (ctx *linux) SymbolizeFrame(frame *StackFrame) error {
+	if ctx.vmlinux == "" || frame.File != "" {
+		return nil
+	}
+	ctx.frameMu.Lock()
+	defer ctx.frameMu.Unlock()
+	if ctx.frameSymb == nil {
+		ctx.frameSymb = ctx.opts.newSymbolizer()
+	}
+	if err := ctx.symbolizeFrame(frame, ctx.frameSymb.Symbolize); err != nil {
+		return err
+	}
+	ctx.sourceMu.Lock()
+	defer ctx.sourceMu.Unlock()
+	frame.SourceLine = ctx.sourceLine(frame.File, frame.Line)
+	return nil
+}
+
+// Close shuts down the symbolizer kept by SymbolizeFrame and drops the frame and source caches.
+func (ctx *linux) Close() error {
+	ctx.frameMu.Lock()
+	defer ctx.frameMu.Unlock()
+	if ctx.frameSymb != nil {
+		ctx.frameSymb.Close()
+		ctx.frameSymb = nil
+	}
+	ctx.frameCache = nil
+	ctx.sourceMu.Lock()
+	defer ctx.sourceMu.Unlock()
+	ctx.sourceCache = nil
+	return nil
+}
+
+// attachSourceLines sets SourceLine of all frames of rep that have source location.
+func (ctx *linux) attachSourceLines(rep *Report) {
+	if ctx.kernelSrc == "" {
+		return
+	}
+	ctx.sourceMu.Lock()
+	defer ctx.sourceMu.Unlock()
+	attach := func(frames []StackFrame) {
+		for i := range frames {
+			frames[i].SourceLine = ctx.sourceLine(frames[i].File, frames[i].Line)
+		}
+	}
+	attach(rep.Frames)
+	for _, aux := range rep.AuxStacks {
+		attach(aux.Frames)
+	}
+	if rep.HasFaultPC {
+		rep.FaultPC.SourceLine = ctx.sourceLine(rep.FaultPC.File, rep.FaultPC.Line)
+	}
+}
+
+// sourceLine returns text of the line of file in kernel sources, or "" if it's not available.
+// Files are read on first use and cached, ctx.sourceMu must be held.
+func (ctx *linux) sourceLine(file string, line int) string {
+	if ctx.kernelSrc == "" || file == "" || line <= 0 {
+		return ""
+	}
+	lines, ok := ctx.sourceCache[file]
+	if !ok {
+		// Don't read files outside of the source tree.
+		path := filepath.Clean(filepath.FromSlash(file))
+		if !filepath.IsAbs(path) && path != ".." && !strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			if data, err := ioutil.ReadFile(filepath.Join(ctx.kernelSrc, path)); err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+		}
+		if ctx.sourceCache == nil {
+			ctx.sourceCache = make(map[string][]string)
+		}
+		ctx.sourceCache[file] = lines
+	}
+	if line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// symbolizeFrame implements SymbolizeFrame using symbFunc, ctx.frameMu must be held.
+func (ctx *linux) symbolizeFrame(frame *StackFrame,
+	symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error)) error {
+	start, ok := funcStart(ctx.symbols, frame.Func, frame.Size)
+	if !ok {
+		return fmt.Errorf("unknown function %v", frame.Func)
+	}
+	if ctx.frameCache == nil {
+		ctx.frameCache = make(map[uint64][]symbolizer.Frame)
+		ctx.frameStrip = ctx.stripPrefix(symbFunc)
+	}
+	pc := start + frame.Offset - 1
+	frames, ok := ctx.frameCache[pc]
+	if !ok {
+		var err error
+		frames, err = symbFunc(ctx.vmlinux, pc)
+		if err != nil {
+			return err
+		}
+		ctx.frameCache[pc] = frames
+	}
+	if len(frames) == 0 {
+		return fmt.Errorf("no debug info for %v", frame.Func)
+	}
+	last := frames[len(frames)-1]
+	frame.File = stripFile(last.File, ctx.frameStrip)
+	frame.Line = last.Line
+	return nil
+}
+
+// funcStart returns start address of function fn with the given size.
+// If there are several functions with the same name, the one with matching size is preferred.
+func funcStart(symbols map[string][]symbolizer.Symbol, fn string, size uint64) (uint64, bool) {
+	symb := symbols[fn]
+	if len(symb) == 0 {
+		return 0, false
+	}
+	var start uint64
+	for _, s := range symb {
+		if start == 0 || int(size) == s.Size {
+			start = s.Addr
+		}
+	}
+	return start, true
+}
+
+func stripFile(file, strip string) string {
+	if strings.HasPrefix(file, strip) {
+		file = file[len(strip):]
+	}
+	if strings.HasPrefix(file, "./") {
+		file = file[2:]
+	}
+	return file
+}
+
+// symbolizeWithContext returns symbFunc that fails after c is cancelled.
+func symbolizeWithContext(c context.Context, symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error)) func(
+	bin string, pc uint64) ([]symbolizer.Frame, error) {
+	return func(bin string, pc uint64) ([]symbolizer.Frame, error) {
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		return symbFunc(bin, pc)
+	}
+}
+
+func symbolizeLines(symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error),
+	symbols map[string][]symbolizer.Symbol, vmlinux, strip string, text []byte, maxFrames int) ([]byte, bool) {
+	var symbolized []byte
+	frames := 0
+	s := bufio.NewScanner(bytes.NewReader(text))
+	for s.Scan() {
+		line := append([]byte{}, s.Bytes()...)
+		line = append(line, '\n')
+		if linuxSymbolizeRe.Match(line) {
+			frames++
+		}
+		if frames <= maxFrames {
+			line = symbolizeLine(symbFunc, symbols, vmlinux, strip, line)
+		}
+		symbolized = append(symbolized, line...)
+	}
+	return symbolized, frames > maxFrames
+}
+
+func symbolizeLine(symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error),
+	symbols map[string][]symbolizer.Symbol, vmlinux, strip string, line []byte) []byte {
+	match := linuxSymbolizeRe.FindSubmatchIndex(line)
+	if match == nil {
+		return line
+	}
+	fn := line[match[2]:match[3]]
+	off, err := strconv.ParseUint(string(line[match[4]:match[5]]), 16, 64)
+	if err != nil {
+		return line
+	}
+	size, err := strconv.ParseUint(string(line[match[6]:match[7]]), 16, 64)
+	if err != nil {
+		return line
+	}
+	start, ok := funcStart(symbols, string(fn), size)
+	if !ok {
+		return line
+	}
+	// Stack frames contain return addresses, so we symbolize the previous instruction.
+	// But riscv "epc : func+0x24/0x30" line contains the faulting instruction.
+	pc := start + off - 1
+	if riscvEPCRe.Match(line[:match[0]]) {
+		pc = start + off
+	}
+	frames, err := symbFunc(vmlinux, pc)
+	if err != nil || len(frames) == 0 {
+		return line
+	}
+	var symbolized []byte
+	for _, frame := range frames {
+		info := fmt.Sprintf(" %v:%v", stripFile(frame.File, strip), frame.Line)
+		modified := append([]byte{}, line...)
+		modified = replace(modified, match[7], match[7], []byte(info))
+		if frame.Inline {
+			end := match[7] + len(info)
+			modified = replace(modified, end, end, []byte(" [inline]"))
+			modified = replace(modified, match[2], match[7], []byte(frame.Func))
+		}
+		symbolized = append(symbolized, modified...)
+	}
+	return symbolized
+}
+
+// isConsoleLine says if line is kernel console output (and not a questionable frame).
+// Only the first Options.MaxLineLen bytes of the line are checked.
+func (ctx *linux) isConsoleLine(line []byte) bool {
+	line = capLine(line, ctx.opts.maxLineLen())
+	return ctx.consoleOutputRe.Match(line) &&
+		(!ctx.questionableRe.Match(line) || bytes.Contains(line, ctx.eoi))
+}
+
+// consoleLine returns bounds of text of console line output[pos:next] without the timestamp prefix
+// and trailing \r. Text beyond Options.MaxLineLen is dropped.
+func (ctx *linux) consoleLine(output []byte, pos, next int) (int, int) {
+	lineStart := bytes.Index(output[pos:next], []byte("] ")) + pos + 2
+	lineEnd := next
+	if max := ctx.opts.maxLineLen(); max > 0 && lineEnd-pos > max {
+		lineEnd = pos + max
+	}
+	if lineEnd != 0 && output[lineEnd-1] == '\r' {
+		lineEnd--
+	}
+	return lineStart, lineEnd
+}
+
+func (ctx *linux) extractConsoleOutput(output []byte) (result []byte) {
+	for pos := 0; pos < len(output); {
+		next := bytes.IndexByte(output[pos:], '\n')
+		if next != -1 {
+			next += pos
+		} else {
+			next = len(output)
+		}
+		if ctx.isConsoleLine(output[pos:next]) {
+			lineStart, lineEnd := ctx.consoleLine(output, pos, next)
+			result = append(result, output[lineStart:lineEnd]...)
+			result = append(result, '\n')
+		}
+		pos = next + 1
+	}
+	return
+}
+
+// extractOopsCount returns the largest "[#N]" oops counter in output.
+func extractOopsCount(output []byte) int {
+	count := 0
+	for _, match := range oopsCountRe.FindAllSubmatch(output, -1) {
+		n, err := strconv.Atoi(string(match[1]))
+		if err == nil && n > count {
+			count = n
+		}
+	}
+	return count
+}
+
+// extractPageFault decodes x86 page fault error code from "Oops: 0002 [#1] SMP KASAN" line.
+func extractPageFault(rep *Report, output []byte) {
+	match := pageFaultRe.FindSubmatch(output)
+	if match == nil || !bytes.Contains(output, []byte("RIP: ")) && !bytes.Contains(output, []byte("EIP: ")) {
+		return
+	}
+	code, err := strconv.ParseUint(string(match[1]), 16, 64)
+	if err != nil {
+		return
+	}
+	rep.PageFault = true
+	rep.FaultProtection = code&(1<<0) != 0
+	rep.FaultWrite = code&(1<<1) != 0
+	rep.FaultUser = code&(1<<2) != 0
+	rep.FaultReserved = code&(1<<3) != 0
+	rep.FaultInstruction = code&(1<<4) != 0
+}
+
+// extractFaultAddr parses address from the page fault header, which is printed as:
+//
+//	BUG: unable to handle kernel paging request at ffff88002bde1e40
+//	BUG: unable to handle kernel NULL pointer dereference at 0000000000000008
+//	BUG: unable to handle page fault for address: ffffffffffffffd0
+//	BUG: kernel NULL pointer dereference, address: 0000000000000008
+//
+// The last two forms are printed since 5.2. arm/arm64 and riscv print:
+//
+//	Unable to handle kernel paging request at virtual address ffff800000000008
+//	Unable to handle kernel NULL pointer dereference at virtual address 0000000000000008
+func extractFaultAddr(rep *Report, output []byte) {
+	match := faultAddrRe.FindSubmatch(output)
+	if match == nil {
+		return
+	}
+	val := match[1]
+	if len(val) == 0 {
+		val = match[2]
+	}
+	addr, err := strconv.ParseUint(string(val), 16, 64)
+	if err != nil {
+		return
+	}
+	rep.FaultAddr = addr
+	rep.HasFaultAddr = true
+	if addr < 4096 {
+		rep.NullPtrDeref = true
+	}
+}
+
+// extractFaultPC parses the faulting function from the x86 RIP line in the report text.
+// Older kernels print the address before the function:
+//
+//	RIP: 0010:[<ffffffff8121b9a5>]  [<ffffffff8121b9a5>] __lock_acquire+0x6f5/0x4a80 kernel/locking/lockdep.c:3363
+//
+// newer kernels print only the function:
+//
+//	RIP: 0010:__lock_acquire+0x6f5/0x4a80 kernel/locking/lockdep.c:3363
+func extractFaultPC(rep *Report) {
+	match := faultPCRe.FindSubmatch(rep.Report)
+	if match == nil {
+		rep.FaultPC, rep.HasFaultPC = StackFrame{}, false
+		return
+	}
+	pc := StackFrame{
+		Func: string(match[1]),
+		File: string(match[4]),
+	}
+	pc.Offset, _ = strconv.ParseUint(string(match[2]), 16, 64)
+	pc.Size, _ = strconv.ParseUint(string(match[3]), 16, 64)
+	pc.Line, _ = strconv.Atoi(string(match[5]))
+	rep.FaultPC, rep.HasFaultPC = pc, true
+}
+
+// extractESR returns arm64 exception syndrome register value printed in the die() line
+// ("Internal error: Oops: 96000006 [#1] PREEMPT SMP") or in "Mem abort info:" section ("ESR = 0x96000006").
+func extractESR(output []byte) (uint64, bool) {
+	match := esrRe.FindSubmatch(output)
+	if match == nil {
+		return 0, false
+	}
+	val := match[1]
+	if len(val) == 0 {
+		val = match[2]
+	}
+	esr, err := strconv.ParseUint(string(val), 16, 64)
+	if err != nil {
+		return 0, false
+	}
+	return esr, true
+}
+
+// extractNonCanonical parses address of GPFs on non-canonical addresses (printed since 5.5):
+//
+//	general protection fault, probably for non-canonical address 0xdffffc0000000001: 0000 [#1] SMP KASAN
+//	KASAN: null-ptr-deref in range [0x0000000000000008-0x000000000000000f]
+//
+// With KASAN inline instrumentation a NULL deref faults on a non-canonical shadow address,
+// and KASAN prints the original address range after that.
+func extractNonCanonical(rep *Report, output []byte) {
+	match := nonCanonicalRe.FindSubmatch(output)
+	if match == nil {
+		return
+	}
+	addr, err := strconv.ParseUint(string(match[1]), 16, 64)
+	if err != nil {
+		return
+	}
+	rep.NonCanonical = true
+	rep.NonCanonicalAddr = addr
+	rep.NullPtrDeref = bytes.Contains(output, []byte("KASAN: null-ptr-deref in range"))
+}
+
+// extractCodeBytes parses the first "Code:" line in output. On x86 the line contains bytes
+// with the faulting byte marked with <>:
+//
+//	Code: 48 89 fa 48 c1 ea 03 <80> 3c 02 00 0f 85 cd 00 00 00 48 8b 5b 10
+//
+// Other architectures print instruction words (4 bytes on arm64, 2 or 4 bytes on arm and riscv)
+// with the faulting instruction in ():
+//
+//	Code: f9400282 b4000482 d1002042 f9400443 (f9400444)
+//
+// The words are converted to bytes in little-endian order.
+// Returns the bytes and index of the faulting byte (-1 if not marked).
+// "(bad)" tokens (bytes that could not be read) are skipped.
+func extractCodeBytes(arch string, output []byte) ([]byte, int) {
+	match := codeRe.FindSubmatch(output)
+	if match == nil {
+		return nil, 0
+	}
+	x86 := isX86(arch)
+	var code []byte
+	fault := -1
+	for _, tok := range strings.Fields(string(match[1])) {
+		if tok == "(bad)" {
+			continue
+		}
+		if x86 && len(tok) == 4 && tok[0] == '<' && tok[3] == '>' ||
+			!x86 && len(tok) > 2 && tok[0] == '(' && tok[len(tok)-1] == ')' {
+			tok = tok[1 : len(tok)-1]
+			fault = len(code)
+		}
+		if x86 && len(tok) != 2 || !x86 && len(tok) != 4 && len(tok) != 8 {
+			return nil, 0
+		}
+		v, err := strconv.ParseUint(tok, 16, 32)
+		if err != nil {
+			return nil, 0
+		}
+		for i := 0; i < len(tok)/2; i++ {
+			code = append(code, byte(v>>(8*uint(i))))
+		}
+	}
+	if len(code) == 0 {
+		return nil, 0
+	}
+	return code, fault
+}
+
+// extractHungTaskTitle handles reports with several blocked tasks (e.g. a cascade of tasks
+// waiting for each other). The first listed task is not necessarily the root cause, so we select
+// the task that is deepest in a lock acquisition path (has the most lock acquisition frames
+// in its stack) and extract title from its section. On ties the first task wins.
+func extractHungTaskTitle(arch, title string, report []byte, format oopsFormat, fullFuncs bool) string {
+	starts := hungTaskRe.FindAllIndex(report, -1)
+	if len(starts) < 2 {
+		return title
+	}
+	best, bestScore := 0, -1
+	for i, start := range starts {
+		end := len(report)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		score := 0
+		for _, frame := range parseLinuxFrames(arch, report[start[0]:end]) {
+			if linuxLockFrameRe.MatchString(frame.Func) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best == 0 {
+		return title
+	}
+	match := format.title.FindSubmatch(report[starts[best][0]:])
+	if match == nil {
+		return title
+	}
+	var groups []string
+	for _, group := range match[1:] {
+		groups = append(groups, string(group))
+	}
+	return fmt.Sprintf(format.fmt, titleArgs(format.title, groups, fullFuncs)...)
+}
+
+// parseHeldLocks parses lockdep "Showing all locks held in the system:" section:
+//
+//	2 locks held by syz-executor0/4213:
+//	 #0:  (&mm->mmap_sem){++++}, at: [<ffffffff8187ad02>] vm_mmap_pgoff+0x162/0x200 mm/util.c:331
+//	 #1:  (&sb->s_type->i_mutex_key#11){+.+.}, at: [<ffffffff81a1c6e9>] inode_lock include/linux/fs.h:713 [inline]
+//
+// If several lines are printed for the same lock (for inlined frames), the first one is used.
+func parseHeldLocks(report []byte) []HeldLock {
+	pos := bytes.Index(report, []byte("Showing all locks held in the system:"))
+	if pos == -1 {
+		return nil
+	}
+	locks := []HeldLock{}
+	task, pid, last := "", 0, -1
+	for _, line := range bytes.Split(report[pos:], []byte{'\n'})[1:] {
+		if bytes.HasPrefix(line, []byte("====")) {
+			break
+		}
+		if match := heldByRe.FindSubmatch(line); match != nil {
+			task = string(match[1])
+			pid, _ = strconv.Atoi(string(match[2]))
+			last = -1
+			continue
+		}
+		match := heldLockRe.FindSubmatch(line)
+		if match == nil || task == "" {
+			continue
+		}
+		idx, _ := strconv.Atoi(string(match[1]))
+		if idx == last {
+			continue
+		}
+		last = idx
+		locks = append(locks, HeldLock{
+			Task: task,
+			PID:  pid,
+			Lock: string(match[2]),
+			Func: string(match[3]),
+		})
+	}
+	return locks
+}
+
+// extractMessage returns descriptive message printed on the line after the first line of report
+// (e.g. "Trying to vfree() bad address (ffff8800b3254fc0)" after a WARNING line),
+// or "" if the next line is a usual part of the report (modules, registers, stack, etc).
+// All numbers in the message are replaced with "NUM" since they are usually not stable.
+func extractMessage(report []byte) string {
+	lines := bytes.SplitN(report, []byte{'\n'}, 3)
+	if len(lines) < 2 {
+		return ""
+	}
+	msg := bytes.TrimSpace(lines[1])
+	if len(msg) == 0 || linuxNonMessageRe.Match(msg) || linuxSymbolizeRe.Match(msg) {
+		return ""
+	}
+	for _, oops := range linuxOopses {
+		if bytes.Contains(msg, oops.header) {
+			return ""
+		}
+	}
+	msg = linuxMessageNumRe.ReplaceAll(msg, []byte("${1}NUM"))
+	const maxMessageLen = 100
+	if len(msg) > maxMessageLen {
+		msg = msg[:maxMessageLen]
+	}
+	return string(msg)
+}
+
+// extractTaskInfo returns comm, taint flags and kernel version from the first
+// "CPU: 0 PID: 123 Comm: syz-executor Not tainted 4.15.0+ #1" line in output.
+func extractTaskInfo(output []byte) (comm, taint, version string) {
+	match := taskInfoRe.FindSubmatch(output)
+	if match == nil {
+		return "", "", ""
+	}
+	// Taint flags are printed in fixed positions with spaces for unset flags.
+	taint = strings.Replace(string(match[2]), " ", "", -1)
+	return string(match[1]), taint, string(match[3])
+}
+
+// parseLinuxStacks fills in rep.Frames and rep.AuxStacks from rep.Report.
+func parseLinuxStacks(rep *Report) {
+	rep.Frames = parseLinuxFrames(rep.Arch, rep.Report)
+	rep.AuxStacks = nil
+	// Backtraces of other CPUs (printed by e.g. RCU stall detector).
+	var cpus []int
+	var texts [][]byte
+	rep.stuckStack = nil
+	sections := nmiBacktraceRe.FindAllSubmatchIndex(rep.Report, -1)
+	for i, match := range sections {
+		end := len(rep.Report)
+		if i+1 < len(sections) {
+			end = sections[i+1][0]
+		}
+		frames := parseLinuxFrames(rep.Arch, rep.Report[match[0]:end])
+		if len(frames) == 0 {
+			continue
+		}
+		cpu, _ := strconv.Atoi(string(rep.Report[match[2]:match[3]]))
+		cpus = append(cpus, cpu)
+		texts = append(texts, rep.Report[match[0]:end])
+		rep.AuxStacks = append(rep.AuxStacks, AuxStack{
+			Title:  string(rep.Report[match[0]:match[1]]),
+			Frames: frames,
+		})
+	}
+	// RCU stall reports list the stalled CPUs and then dump their stacks.
+	// The first stalled CPU stack is more representative than the detecting CPU stack,
+	// which is mostly RCU internals. Similarly, hard lockups can be detected by another CPU
+	// (buddy detector), which then dumps the stuck CPU stack after its own stack.
+	stuck := make(map[int]bool)
+	if bytes.Contains(rep.Report, []byte("detected stall")) {
+		for _, match := range rcuStalledCPURe.FindAllSubmatch(rep.Report, -1) {
+			cpu, _ := strconv.Atoi(string(match[1]))
+			stuck[cpu] = true
+		}
+	} else if match := hardLockupCPURe.FindSubmatch(rep.Report); match != nil {
+		cpu, _ := strconv.Atoi(string(match[1]))
+		stuck[cpu] = true
+	}
+	for i, stack := range rep.AuxStacks {
+		if stuck[cpus[i]] {
+			rep.Frames = stack.Frames
+			rep.stuckStack = texts[i]
+			break
+		}
+	}
+	// KASAN reports contain stacks where the accessed object was allocated and freed.
+	rep.AllocFunc, rep.FreeFunc = "", ""
+	for _, match := range kasanTrackRe.FindAllSubmatchIndex(rep.Report, -1) {
+		frames, _ := scanLinuxFrames(rep.Report[match[0]:])
+		if len(frames) == 0 {
+			continue
+		}
+		rep.AuxStacks = append(rep.AuxStacks, AuxStack{
+			Title:  string(rep.Report[match[0]:match[1]]),
+			Frames: frames,
+		})
+		fn := &rep.FreeFunc
+		if string(rep.Report[match[2]:match[3]]) == "Allocated" {
+			fn = &rep.AllocFunc
+		}
+		if *fn == "" {
+			*fn = kasanTrackFunc(frames)
+		}
+	}
+	// With PAGE_OWNER page dumps (e.g. in bad page reports) contain the page allocation stack.
+	// It ends with the free stack, the next page dump or the rest of the report.
+	// The allocation order is not included into the stack title.
+	for _, match := range pageOwnerRe.FindAllSubmatchIndex(rep.Report, -1) {
+		text := rep.Report[match[0]:]
+		if next := bytes.IndexByte(text, '\n'); next != -1 {
+			if end := pageOwnerEndRe.FindIndex(text[next+1:]); end != nil {
+				text = text[:next+1+end[0]]
+			}
+		}
+		frames, _ := scanLinuxFrames(text)
+		if len(frames) == 0 {
+			continue
+		}
+		rep.AuxStacks = append(rep.AuxStacks, AuxStack{
+			Title:  string(rep.Report[match[2]:match[3]]),
+			Frames: frames,
+		})
+	}
+	rep.Syscall = linuxSyscall(rep.Frames)
+}
+
+// pageOwnerFiles returns files of the page allocation stack of page corruption reports (see parseLinuxStacks)
+// starting from the first frame that is not a part of the page allocator, nil for other reports.
+func pageOwnerFiles(rep *Report) []string {
+	if rep.MatchedFormat != "bad-page-state" && rep.MatchedFormat != "bad-page-map" {
+		return nil
+	}
+	for _, stack := range rep.AuxStacks {
+		if !strings.HasSuffix(stack.Title, "allocated via") {
+			continue
+		}
+		var files []string
+		for _, frame := range stack.Frames {
+			if fn := baseFuncName(frame.Func); len(files) == 0 &&
+				(linuxAllocFrameRe.MatchString(fn) || linuxPageAllocFrameRe.MatchString(fn)) {
+				continue
 			}
-			if oops == nil {
-				textPrefix = append(textPrefix, append([]byte{}, output[lineStart:lineEnd]...))
-				if len(textPrefix) > 5 {
-					textPrefix = textPrefix[1:]
-				}
-			} else {
-				// Prepend 5 lines preceding start of the report,
-				// they can contain additional info related to the report.
-				for _, prefix := range textPrefix {
-					rep.Report = append(rep.Report, prefix...)
-					rep.Report = append(rep.Report, '\n')
-				}
-				textPrefix = nil
-				textLines++
-				ln := output[lineStart:lineEnd]
-				skipLine := skipText
-				if bytes.Contains(ln, []byte("Disabling lock debugging due to kernel taint")) {
-					skipLine = true
-				} else if textLines > 40 && bytes.Contains(ln, []byte("Kernel panic - not syncing")) {
-					// If panic_on_warn set, then we frequently have 2 stacks:
-					// one for the actual report (or maybe even more than one),
-					// and then one for panic caused by panic_on_warn. This makes
-					// reports unnecessary long and the panic (current) stack
-					// is always present in the actual report. So we strip the
-					// panic message. However, we check that we have enough lines
-					// before the panic, because sometimes we have, for example,
-					// a single WARNING line without a stack and then the panic
-					// with the stack.
-					skipText = true
-					skipLine = true
-				}
-				if !skipLine {
-					rep.Report = append(rep.Report, ln...)
-					rep.Report = append(rep.Report, '\n')
-				}
+			if frame.File != "" {
+				files = append(files, frame.File)
 			}
 		}
-		pos = next + 1
+		return files
 	}
-	if oops == nil {
-		return nil
+	return nil
+}
+
+// kasanTrackFunc returns the first function in KASAN alloc/free stack that is not
+// a part of KASAN or memory allocator, i.e. the function that allocated/freed the object.
+func kasanTrackFunc(frames []StackFrame) string {
+	for _, frame := range frames {
+		if fn := baseFuncName(frame.Func); !linuxAllocFrameRe.MatchString(fn) {
+			return fn
+		}
 	}
-	title, report, format := extractDescription(ctx.extractConsoleOutput(output[rep.StartPos:]), oops)
-	rep.Title = title
-	rep.Corrupted = ctx.isCorrupted(title, report, format)
-	// Executor PIDs are not interesting.
-	rep.Title = executorRe.ReplaceAllLiteralString(rep.Title, "syz-executor")
-	// Replace that everything looks like an address with "ADDR",
-	// addresses in descriptions can't be good regardless of the oops regexps.
-	rep.Title = addrRe.ReplaceAllString(rep.Title, "${1}ADDR")
-	// Replace that everything looks like a decimal number with "NUM".
-	rep.Title = decNumRe.ReplaceAllString(rep.Title, "${1}NUM")
-	// Replace that everything looks like a file line number with "LINE".
-	rep.Title = lineNumRe.ReplaceAllLiteralString(rep.Title, ":LINE")
-	// Replace all raw references to runctions (e.g. "ip6_fragment+0x1052/0x2d80")
-	// with just function name ("ip6_fragment"). Offsets and sizes are not stable.
-	rep.Title = funcRe.ReplaceAllString(rep.Title, "$1")
-	// CPU numbers are not interesting.
-	rep.Title = cpuRe.ReplaceAllLiteralString(rep.Title, "CPU")
-	return rep
+	return ""
 }
 
-func (ctx *linux) Symbolize(rep *Report) error {
-	if ctx.vmlinux == "" {
+// linuxSyscall returns name of the syscall from syscall entry frames (e.g. "__x64_sys_ioctl").
+// Frames are checked from the outermost one, since functions with sys_ prefix
+// (e.g. fbdev sys_imageblit) can also be called deeper in the stack.
+func linuxSyscall(frames []StackFrame) string {
+	for i := len(frames) - 1; i >= 0; i-- {
+		if match := linuxSyscallRe.FindStringSubmatch(baseFuncName(frames[i].Func)); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// parseLinuxFrames extracts frames of the main stack trace from the report text.
+// The stack starts after "Call Trace:" (or "<TASK>" marker if there is no "Call Trace:",
+// or "backtrace:" if there are neither) and ends on "</TASK>" marker (printed since 5.18),
+// an empty line or a line that starts a different report section.
+// arm64 stacks start after "Call trace:" (see arm64PCFrame).
+// Older riscv kernels print frames right after "status: ... badaddr: ... cause: ..." registers line
+// without "Call Trace:" header.
+func parseLinuxFrames(arch string, report []byte) []StackFrame {
+	start := linuxStackStart(arch, report)
+	if start == -1 {
 		return nil
 	}
-	symbolized, err := ctx.symbolize(rep.Report)
-	if err != nil {
-		return err
+	frames, _ := scanLinuxFrames(report[start:])
+	if arch == "arm64" && bytes.HasPrefix(report[start:], []byte("Call trace:")) {
+		frames = arm64PCFrame(frames, report[:start])
 	}
-	rep.Report = symbolized
-	guiltyFile := ctx.extractGuiltyFile(rep.Report)
-	if guiltyFile != "" {
-		rep.Maintainers, err = ctx.getMaintainers(guiltyFile)
-		if err != nil {
-			return err
-		}
+	return frames
+}
+
+// linuxStackText returns text of the main stack trace (see parseLinuxFrames) without the header line:
+// from the first line after the header to the end of the last frame line. Returns nil if there are no frames.
+func linuxStackText(arch string, report []byte) []byte {
+	start := linuxStackStart(arch, report)
+	if start == -1 {
+		return nil
 	}
-	return nil
+	_, text := scanLinuxFrames(report[start:])
+	return text
 }
 
-func (ctx *linux) symbolize(text []byte) ([]byte, error) {
-	symb := symbolizer.NewSymbolizer()
-	defer symb.Close()
-	// Strip vmlinux location from all paths.
-	strip, _ := filepath.Abs(ctx.vmlinux)
-	strip = filepath.Dir(strip) + string(filepath.Separator)
-	// Vmlinux may have been moved, so check if we can find debug info
-	// for __sanitizer_cov_trace_pc. We know where it is located,
-	// so we can infer correct strip prefix from it.
-	if covSymbols := ctx.symbols["__sanitizer_cov_trace_pc"]; len(covSymbols) != 0 {
-		for _, covSymb := range covSymbols {
-			frames, _ := symb.Symbolize(ctx.vmlinux, covSymb.Addr)
-			if len(frames) > 0 {
-				file := frames[len(frames)-1].File
-				if idx := strings.Index(file, "kernel/kcov.c"); idx != -1 {
-					strip = file[:idx]
+// excerpt implements Report.Excerpt: it drops lines preceding the first oops header,
+// register and memory dumps, and sections that describe memory state (e.g. KASAN shadow memory).
+// A section is skipped up to an empty line, a separator line or a start of a stack.
+func (ctx *linux) excerpt(report []byte) []byte {
+	var res []byte
+	started, skipSection := false, false
+	for _, line := range bytes.SplitAfter(report, []byte{'\n'}) {
+		ln := bytes.TrimRight(line, "\r\n")
+		if !started {
+			for _, oops := range ctx.oopses {
+				if matchOops(capLine(ln, ctx.opts.maxLineLen()), oops, ctx.ignores, ctx.opts.TolerantHeaders) != -1 {
+					started = true
 					break
 				}
 			}
+			if !started {
+				continue
+			}
 		}
+		if skipSection {
+			if len(bytes.TrimSpace(ln)) != 0 && !bytes.HasPrefix(ln, []byte("==")) && !linuxStackTitleRe.Match(ln) {
+				continue
+			}
+			skipSection = false
+		}
+		if linuxExcerptSectionRe.Match(ln) {
+			skipSection = true
+			continue
+		}
+		if linuxExcerptDumpRe.Match(ln) {
+			continue
+		}
+		res = append(res, line...)
 	}
-	var symbolized []byte
-	s := bufio.NewScanner(bytes.NewReader(text))
-	for s.Scan() {
-		line := append([]byte{}, s.Bytes()...)
-		line = append(line, '\n')
-		line = symbolizeLine(symb.Symbolize, ctx.symbols, ctx.vmlinux, strip, line)
-		symbolized = append(symbolized, line...)
+	if !started || ctx.Title(res) != ctx.Title(report) {
+		return append([]byte{}, report...)
 	}
-	return symbolized, nil
+	return res
 }
 
-func symbolizeLine(symbFunc func(bin string, pc uint64) ([]symbolizer.Frame, error),
-	symbols map[string][]symbolizer.Symbol, vmlinux, strip string, line []byte) []byte {
-	match := linuxSymbolizeRe.FindSubmatchIndex(line)
-	if match == nil {
-		return line
+// linuxStackStart returns position of the main stack header line in report, or -1.
+func linuxStackStart(arch string, report []byte) int {
+	switch arch {
+	case "arm64":
+		if start := bytes.Index(report, []byte("Call trace:")); start != -1 {
+			return start
+		}
+	case "riscv64":
+		if !bytes.Contains(report, []byte("Call Trace:")) {
+			if match := riscvStatusRe.FindIndex(report); match != nil {
+				return match[0]
+			}
+		}
 	}
-	fn := line[match[2]:match[3]]
-	off, err := strconv.ParseUint(string(line[match[4]:match[5]]), 16, 64)
-	if err != nil {
-		return line
+	start := bytes.Index(report, []byte("Call Trace:"))
+	if start == -1 {
+		start = bytes.Index(report, []byte("<TASK>"))
 	}
-	size, err := strconv.ParseUint(string(line[match[6]:match[7]]), 16, 64)
-	if err != nil {
-		return line
+	if start == -1 {
+		start = bytes.Index(report, []byte("backtrace:"))
 	}
-	symb := symbols[string(fn)]
-	if len(symb) == 0 {
-		return line
+	return start
+}
+
+// arm64PCFrame handles the faulting function printed in the "pc : func+0x38/0x1a8" register line
+// before the stack. Normally the stack starts with the same function, but if it does not
+// (e.g. the first frames were lost), the pc frame is prepended.
+func arm64PCFrame(frames []StackFrame, regs []byte) []StackFrame {
+	match := arm64PCRe.FindSubmatch(regs)
+	if match == nil {
+		return frames
 	}
-	var funcStart uint64
-	for _, s := range symb {
-		if funcStart == 0 || int(size) == s.Size {
-			funcStart = s.Addr
-		}
+	pc := StackFrame{
+		Func: string(match[1]),
 	}
-	frames, err := symbFunc(vmlinux, funcStart+off-1)
-	if err != nil || len(frames) == 0 {
-		return line
+	pc.Offset, _ = strconv.ParseUint(string(match[2]), 16, 64)
+	pc.Size, _ = strconv.ParseUint(string(match[3]), 16, 64)
+	if len(frames) != 0 && frames[0].Func == pc.Func {
+		return frames
 	}
-	var symbolized []byte
-	for _, frame := range frames {
-		file := frame.File
-		if strings.HasPrefix(file, strip) {
-			file = file[len(strip):]
+	return append([]StackFrame{pc}, frames...)
+}
+
+// scanLinuxFrames parses frames of the stack that starts on the first line of report.
+// It also returns text of the stack lines from the line after the header to the last frame line
+// (nil if there are no frames).
+func scanLinuxFrames(report []byte) ([]StackFrame, []byte) {
+	pos := bytes.IndexByte(report, '\n')
+	if pos == -1 {
+		return nil, nil
+	}
+	pos++ // skip the stack header line
+	textStart, textEnd := pos, pos
+	var frames []StackFrame
+	for pos < len(report) {
+		next := len(report)
+		if i := bytes.IndexByte(report[pos:], '\n'); i != -1 {
+			next = pos + i + 1
 		}
-		if strings.HasPrefix(file, "./") {
-			file = file[2:]
+		ln := bytes.TrimRight(report[pos:next], "\r\n")
+		pos = next
+		if frame, ok := parseLinuxFrame(ln); ok {
+			frames = append(frames, frame)
+			textEnd = next
+			continue
 		}
-		info := fmt.Sprintf(" %v:%v", file, frame.Line)
-		modified := append([]byte{}, line...)
-		modified = replace(modified, match[7], match[7], []byte(info))
-		if frame.Inline {
-			end := match[7] + len(info)
-			modified = replace(modified, end, end, []byte(" [inline]"))
-			modified = replace(modified, match[2], match[7], []byte(frame.Func))
+		if len(bytes.TrimSpace(ln)) == 0 || linuxStackEndRe.Match(ln) ||
+			bytes.Equal(bytes.TrimSpace(ln), []byte("</TASK>")) {
+			break
 		}
-		symbolized = append(symbolized, modified...)
+		// Stack markers like <IRQ> and <TASK> and interleaved unrelated lines are skipped.
 	}
-	return symbolized
+	if len(frames) == 0 {
+		return nil, nil
+	}
+	return frames, report[textStart:textEnd]
 }
 
-func (ctx *linux) extractConsoleOutput(output []byte) (result []byte) {
-	for pos := 0; pos < len(output); {
-		next := bytes.IndexByte(output[pos:], '\n')
-		if next != -1 {
-			next += pos
-		} else {
-			next = len(output)
+func parseLinuxFrame(ln []byte) (StackFrame, bool) {
+	match := linuxFrameRe.FindSubmatch(ln)
+	if match == nil {
+		return StackFrame{}, false
+	}
+	frame := StackFrame{
+		Func:       string(match[2]),
+		Unreliable: len(match[1]) != 0,
+	}
+	if len(match[3]) != 0 {
+		frame.Offset, _ = strconv.ParseUint(string(match[3]), 16, 64)
+		frame.Size, _ = strconv.ParseUint(string(match[4]), 16, 64)
+		frame.File = string(match[5])
+		frame.Line, _ = strconv.Atoi(string(match[6]))
+	} else {
+		frame.File = string(match[7])
+		frame.Line, _ = strconv.Atoi(string(match[8]))
+		frame.Inline = true
+	}
+	return frame, true
+}
+
+// guiltyFrame returns index of the first frame in the guilty file, or -1.
+// Reliable frames are preferred over unreliable ("? func") ones.
+func guiltyFrame(frames []StackFrame, guiltyFile string) int {
+	if guiltyFile == "" {
+		return -1
+	}
+	res := -1
+	for i, frame := range frames {
+		if frame.File != guiltyFile {
+			continue
 		}
-		if ctx.consoleOutputRe.Match(output[pos:next]) &&
-			(!ctx.questionableRe.Match(output[pos:next]) ||
-				bytes.Index(output[pos:next], ctx.eoi) != -1) {
-			lineStart := bytes.Index(output[pos:next], []byte("] ")) + pos + 2
-			lineEnd := next
-			if lineEnd != 0 && output[lineEnd-1] == '\r' {
-				lineEnd--
-			}
-			result = append(result, output[lineStart:lineEnd]...)
-			result = append(result, '\n')
+		if !frame.Unreliable {
+			return i
+		}
+		if res == -1 {
+			res = i
 		}
-		pos = next + 1
 	}
-	return
+	return res
 }
 
 func (ctx *linux) extractGuiltyFile(report []byte) string {
-	files := ctx.extractFiles(report)
+	if files := ctx.extractGuiltyFiles(report, 1); len(files) != 0 {
+		return files[0]
+	}
+	return ""
+}
+
+// extractGuiltyFiles returns up to n (1 if n <= 0) distinct guilty file candidates
+// in the order of preference, the first one is the guilty file.
+func (ctx *linux) extractGuiltyFiles(report []byte, n int) []string {
+	return ctx.selectGuiltyFiles(ctx.extractFiles(report), n)
+}
+
+// selectGuiltyFiles returns up to n (1 if n <= 0) distinct non-blacklisted files
+// in the order of preference.
+func (ctx *linux) selectGuiltyFiles(files []string, n int) []string {
+	if n <= 0 {
+		n = 1
+	}
+	var guilty []string
+	dedup := make(map[string]bool)
 nextFile:
 	for _, file := range files {
+		if dedup[file] {
+			continue
+		}
 		for _, re := range ctx.guiltyFileBlacklist {
 			if re.MatchString(file) {
 				continue nextFile
 			}
 		}
-		return file
+		dedup[file] = true
+		guilty = append(guilty, file)
+		if len(guilty) == n {
+			break
+		}
 	}
-	return ""
+	return guilty
+}
+
+// getFilesMaintainers returns the union of maintainers of all files.
+func (ctx *linux) getFilesMaintainers(files []string) ([]string, error) {
+	var mtrs []string
+	dedup := make(map[string]bool)
+	for _, file := range files {
+		mtrs1, err := ctx.getMaintainers(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, mtr := range mtrs1 {
+			if !dedup[mtr] {
+				dedup[mtr] = true
+				mtrs = append(mtrs, mtr)
+			}
+		}
+	}
+	sortMaintainers(mtrs)
+	return mtrs, nil
 }
 
 func (ctx *linux) getMaintainers(file string) ([]string, error) {
@@ -327,9 +1867,33 @@ func (ctx *linux) getMaintainers(file string) ([]string, error) {
 			return nil, err
 		}
 	}
+	sortMaintainers(mtrs)
 	return mtrs, nil
 }
 
+// sortMaintainers sorts maintainers in a stable order that does not depend on get_maintainer.pl
+// output order: individuals go before mailing lists, and each group is sorted lexicographically.
+func sortMaintainers(mtrs []string) {
+	sort.Slice(mtrs, func(i, j int) bool {
+		list1, list2 := isMailingList(mtrs[i]), isMailingList(mtrs[j])
+		if list1 != list2 {
+			return !list1
+		}
+		return mtrs[i] < mtrs[j]
+	})
+}
+
+// isMailingList says if the email address belongs to a mailing list rather than to a person.
+func isMailingList(addr string) bool {
+	pos := strings.LastIndexByte(addr, '@')
+	if pos == -1 {
+		return false
+	}
+	domain := strings.ToLower(addr[pos+1:])
+	return domain == "vger.kernel.org" || domain == "googlegroups.com" ||
+		strings.HasPrefix(domain, "lists.") || strings.Contains(domain, ".lists.")
+}
+
 func (ctx *linux) getMaintainersImpl(file string, blame bool) ([]string, error) {
 	args := []string{"--no-n", "--no-rolestats"}
 	if blame {
@@ -352,32 +1916,47 @@ func (ctx *linux) getMaintainersImpl(file string, blame bool) ([]string, error)
 	return mtrs, nil
 }
 
+// extractFiles returns source files mentioned in the report in the order of appearance,
+// except that files of unreliable ("? func") frames go after all other files,
+// so that they are used for guilty file selection only if there is nothing better.
 func (ctx *linux) extractFiles(report []byte) []string {
-	matches := filenameRe.FindAll(report, -1)
-	var files []string
-	for _, match := range matches {
-		files = append(files, string(bytes.Split(match, []byte{':'})[0]))
+	var files, unreliable []string
+	for _, ln := range bytes.Split(report, []byte{'\n'}) {
+		matches := filenameRe.FindAll(ln, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		dst := &files
+		if frame, ok := parseLinuxFrame(ln); ok && frame.Unreliable {
+			dst = &unreliable
+		}
+		for _, match := range matches {
+			*dst = append(*dst, string(bytes.Split(match, []byte{':'})[0]))
+		}
 	}
-	return files
+	return append(files, unreliable...)
 }
 
-func (ctx *linux) isCorrupted(title string, report []byte, format oopsFormat) bool {
+// isCorrupted returns reason why the report is corrupted, or "" if it is not corrupted.
+func (ctx *linux) isCorrupted(title string, report []byte, format oopsFormat) string {
 	// Check if this crash format is marked as corrupted.
 	if format.corrupted {
-		return true
+		return "corrupted format"
 	}
 	// Check that the report matches report regexp.
 	if format.report != nil && !format.report.Match(report) {
-		return true
+		return "report does not match report regexp"
 	}
 	// Check if the report contains stack trace.
-	if !format.noStackTrace && !bytes.Contains(report, []byte("Call Trace")) && !bytes.Contains(report, []byte("backtrace")) {
-		return true
+	if !format.noStackTrace && !bytes.Contains(report, []byte("Call Trace")) &&
+		!bytes.Contains(report, []byte("Call trace")) && !bytes.Contains(report, []byte("<TASK>")) &&
+		!bytes.Contains(report, []byte("backtrace")) && !riscvStatusRe.Match(report) {
+		return "no stack trace"
 	}
 	// Check for common title corruptions.
 	for _, re := range linuxCorruptedTitles {
 		if re.MatchString(title) {
-			return true
+			return "corrupted title"
 		}
 	}
 	// When a report contains 'Call Trace', 'backtrace', 'Allocated' or 'Freed' keywords,
@@ -389,22 +1968,22 @@ func (ctx *linux) isCorrupted(title string, report []byte, format oopsFormat) bo
 		}
 		frames := bytes.Split(report[match[0]:], []byte{'\n'})
 		if len(frames) < 4 {
-			return true
+			return "missing stack frames"
 		}
 		frames = frames[1:]
 		corrupted := true
 		// Check that at least one of the next 10 lines contains a frame.
-		for i := 0; i < 10; i++ {
+		for i := 0; i < 10 && i < len(frames); i++ {
 			if bytes.Contains(frames[i], []byte("(stack is not available)")) || linuxSymbolizeRe.Match(frames[i]) {
 				corrupted = false
 				break
 			}
 		}
 		if corrupted {
-			return true
+			return "missing stack frames"
 		}
 	}
-	return false
+	return ""
 }
 
 var (
@@ -413,132 +1992,490 @@ var (
 	lineNumRe        = regexp.MustCompile(`(:[0-9]+)+`)
 	addrRe           = regexp.MustCompile(`([^a-zA-Z])(?:0x)?[0-9a-f]{8,}`)
 	decNumRe         = regexp.MustCompile(`([^a-zA-Z])[0-9]{5,}`)
+	funcSuffixRe     = regexp.MustCompile(`\b([a-zA-Z_][a-zA-Z0-9_]*)(?:\.(?:isra|constprop|part|cold|llvm|lto_priv)(?:\.[0-9]+)*)+\b`)
 	funcRe           = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9_.]+)\+0x[0-9a-z]+/0x[0-9a-z]+`)
 	cpuRe            = regexp.MustCompile(`CPU#[0-9]+`)
 	executorRe       = regexp.MustCompile(`syz-executor[0-9]+((/|:)[0-9]+)?`)
+	oopsCountRe      = regexp.MustCompile(`: [0-9a-f]+ \[#([0-9]+)\]`)
+	nonCanonicalRe   = regexp.MustCompile(`general protection fault, probably for non-canonical address 0x([0-9a-f]+)`)
+	codeRe           = regexp.MustCompile(`(?m)^Code: ([^\r\n]*)`)
+	// On arm64 this line looks like "Internal error: Oops: 96000004 [#1]" and contains ESR instead.
+	pageFaultRe       = regexp.MustCompile(`(?m)^Oops: ([0-9a-f]{4}) \[#[0-9]+\]`)
+	faultPCRe         = regexp.MustCompile(`(?m)^[ \t]*RIP: [0-9a-f]{4}:(?:\[\<[0-9a-f]+\>\][ \t]+)*([a-zA-Z0-9_.]+)\+0x([0-9a-f]+)/0x([0-9a-f]+)(?:[ \t]+([a-zA-Z0-9_\-./]+\.[a-zA-Z]+):([0-9]+))?`)
+	arm64PCRe         = regexp.MustCompile(`(?m)^pc : ([a-zA-Z0-9_.]+)\+0x([0-9a-f]+)/0x([0-9a-f]+)`)
+	riscvStatusRe     = regexp.MustCompile(`(?m)^ *status: [0-9a-f]+ badaddr: `)
+	riscvEPCRe        = regexp.MustCompile(`(?:^|[ \]])s?epc ?:$`)
+	esrRe             = regexp.MustCompile(`Internal error: [^\n]*?: ([0-9a-f]+) \[#[0-9]+\]|ESR = 0x([0-9a-f]+)`)
+	linuxOopsEndRe    = regexp.MustCompile(`---\[ end trace [0-9a-f]+ \]---|Kernel Offset: `)
+	linuxTimestampRe  = regexp.MustCompile(`\[ *([0-9]+\.[0-9]+)\] `)
+	linuxBootRe       = regexp.MustCompile(`(?m)^(?:\[.*\] *)?Linux version [0-9]+\.[0-9]+`)
+	linuxSuppressedRe = regexp.MustCompile(`(?m)^(?:\[.*\] *)?printk: (?:[^ ]+: )?[0-9]+ ` +
+		`(?:messages|output lines) suppressed`)
+	hungTaskRe       = regexp.MustCompile(`INFO: task .* blocked for more than [0-9]+ seconds`)
+	linuxLockFrameRe = regexp.MustCompile(`^_*(?:mutex_lock|down_read|down_write|down_killable|` +
+		`down_interruptible|down_timeout|rwsem_down|percpu_down|ldsem_down|lock_sock|rtnl_lock|` +
+		`lock_page|raw_spin_lock|raw_read_lock|raw_write_lock)`)
+	heldByRe   = regexp.MustCompile(`^[0-9]+ locks? held by (.+)/([0-9]+):`)
+	heldLockRe = regexp.MustCompile(`^ *#([0-9]+): +(?:[0-9a-f]+ )?\((.+?)\)\{[^}]*\}(?:-\{[^}]*\})?, at: ` +
+		`(?:\[\<[0-9a-f]+\>\] )?([a-zA-Z0-9_.]+)`)
+	linuxNonMessageRe = regexp.MustCompile(`^(?:Modules linked in|Kernel panic|CPU: |Hardware name|` +
+		`Dumping ftrace|\(ftrace buffer|-+\[ |RIP: |Call [Tt]race|irq event stamp|hardirqs |softirqs |` +
+		`Code: |task: |Kernel Offset|Disabling lock debugging|Workqueue: )`)
+	faultAddrRe = regexp.MustCompile(`BUG: (?:unable to handle kernel (?:paging request|NULL pointer dereference) at|` +
+		`unable to handle page fault for address:|kernel NULL pointer dereference, address:) +(?:0x)?([0-9a-f]+)|` +
+		`Unable to handle kernel [a-zA-Z ]+ at virtual address ([0-9a-f]+)`)
+	linuxMessageNumRe = regexp.MustCompile(`(^|[^a-zA-Z0-9_])(?:0x[0-9a-fA-F]+|[0-9]+)\b`)
+	taskInfoRe        = regexp.MustCompile(`Comm: (.+?) (?:Not tainted|Tainted: ([A-Z ]+?)) +([0-9][^ \r\n]*)`)
+	linuxFrameRe      = regexp.MustCompile(`^[ \t]*(?:\[\<[0-9a-f]+\>\][ \t]+)?(\? +)?(?:[0-9]+:)?([a-zA-Z0-9_.]+)` +
+		`(?:\+0x([0-9a-f]+)/0x([0-9a-f]+)(?: ([a-zA-Z0-9_\-\./]+\.[a-zA-Z]+):([0-9]+))?` +
+		`| ([a-zA-Z0-9_\-\./]+\.[a-zA-Z]+):([0-9]+) \[inline\])`)
+	linuxStackEndRe = regexp.MustCompile(`^(?:Code: |Allocated by |Freed by |The buggy address |Memory state |---\[ end trace|Kernel Offset|={10,})`)
+	// linuxStackTitleRe matches lines that start stacks (see Report.Excerpt).
+	linuxStackTitleRe = regexp.MustCompile(`^(?:Call [Tt]race:|(?:Allocated|Freed) by task |NMI backtrace for cpu |` +
+		`Last potentially related work creation:|Second to last potentially related work creation:)`)
+	// linuxExcerptSectionRe matches the first lines of report sections that are dropped from Report.Excerpt.
+	linuxExcerptSectionRe = regexp.MustCompile(`^(?:Memory state around the buggy address|` +
+		`The buggy address (?:belongs to|is located)|Showing all locks held in the system|Mem-Info:)`)
+	// linuxExcerptDumpRe matches register and memory dump lines that are dropped from Report.Excerpt
+	// (the faulting PC lines like "RIP: ..." and "pc : ..." are preserved).
+	linuxExcerptDumpRe = regexp.MustCompile(`^(?:Code: |(?:R[A-HJ-Z0-9][A-Z0-9]{0,2}|E[A-HJ-Z][A-Z]|FS|GS|CS|CR[0-9]|DR[0-9]): |` +
+		`(?:x[0-9]{1,2}|sp) ?: |pstate: |raw: |page:|flags: |page dumped because|` +
+		` *>?[0-9a-f]{8,16}: (?:[0-9a-f]{2,16}[ >\[\]]*)+$|(?: *[0-9a-f]{16})+ *$| *\^$)`)
+	// Syscall wrappers look like __x64_sys_foo, __arm64_compat_sys_foo, __se_sys_foo, __do_sys_foo
+	// on newer kernels and SyS_foo, SYSC_foo, C_SYSC_foo, compat_SyS_foo on older kernels.
+	linuxSyscallRe = regexp.MustCompile(`^(?:__(?:x64|x32|ia32|arm64|s390x?|riscv|powerpc)_(?:compat_)?sys|` +
+		`__(?:se|do)_(?:compat_)?sys|(?:compat_)?SyS|(?:C_)?SYSC)_([a-zA-Z0-9_]+)$`)
+	linuxSoftLockupRe = regexp.MustCompile(`BUG: soft lockup`)
+	linuxRCUStallRe   = regexp.MustCompile(`INFO: rcu_(?:preempt|sched|bh)(?:_state)? (?:self-)?detected (?:expedited )?stall`)
+	linuxIdleFrameRe  = regexp.MustCompile(`^(?:default_idle|arch_cpu_idle|cpu_idle|do_idle|cpu_startup_entry|` +
+		`cpuidle_|native_safe_halt|safe_halt|mwait_idle|intel_idle|acpi_idle|poll_idle|rcu_idle_|rcu_eqs_|` +
+		`start_secondary|secondary_startup|rest_init|start_kernel|x86_64_start)`)
+	nmiBacktraceRe  = regexp.MustCompile(`NMI backtrace for cpu ([0-9]+)`)
+	hardLockupCPURe = regexp.MustCompile(`Watchdog detected hard LOCKUP on cpu ([0-9]+)`)
+	kasanTrackRe    = regexp.MustCompile(`(?m)^(Allocated|Freed) by task [0-9]+`)
+	// linuxRecursiveFault is printed if a task oopses while exiting after a previous oops.
+	linuxRecursiveFault = []byte("Fixing recursive fault but reboot is needed!")
+	// linuxAllocFrameRe matches KASAN and memory allocator functions in alloc/free stacks.
+	linuxAllocFrameRe = regexp.MustCompile(`^_*(?:kasan|save_stack|stack_trace|stack_depot|set_track|` +
+		`set_alloc_info|set_free_info|kmalloc|kzalloc|kcalloc|kvmalloc|kvzalloc|krealloc|kmemdup|kstrdup|` +
+		`kmem_cache|kfree|kvfree|kzfree|slab|cache_free|do_slab_free|do_kmalloc|vmalloc|vzalloc|vfree|` +
+		`alloc_pages|free_pages|kmem_|kmemleak)`)
+	// linuxPageAllocFrameRe matches page allocator functions that are not matched by linuxAllocFrameRe.
+	linuxPageAllocFrameRe = regexp.MustCompile(`^_*(?:post_alloc_hook|prep_new_page|get_page_from_freelist|` +
+		`alloc_page|folio_alloc|vma_alloc_folio|filemap_alloc_folio|page_cache_alloc|get_free_pages|` +
+		`get_zeroed_page|alloc_slab_page|allocate_slab|new_slab)`)
+	pageOwnerRe     = regexp.MustCompile(`(?m)^(page (?:last )?allocated via) order [0-9]+`)
+	pageOwnerEndRe  = regexp.MustCompile(`(?m)^(?:page |Modules linked in|CPU: |$)`)
+	rcuStalledCPURe = regexp.MustCompile(`(?m)^\s+([0-9]+)-[^:\n]*: \(`)
+	// rcuProtectionRe matches the message line of suspicious RCU usage reports.
+	rcuProtectionRe  = regexp.MustCompile(`(?m)^(?:\./)?[a-zA-Z0-9-_/.]+\.[a-z]+:[0-9]+ (.+?)!*$`)
+	rcuIllegalLockRe = regexp.MustCompile(`^([a-z_]+)\(\) used illegally`)
 )
 
+// linuxArchDesc describes architecture-specific parts of reports.
+type linuxArchDesc struct {
+	name string
+	// detect matches lines that are printed only on this architecture (see detectLinuxArch).
+	detect *regexp.Regexp
+	// regs matches the first line of a register dump or a stack (used to detect oopses with truncated head).
+	regs *regexp.Regexp
+}
+
+// linuxArchs lists supported values of Options.Arch in order of detection.
+// Names match the target architecture names.
+var linuxArchs = []linuxArchDesc{
+	{
+		name:   "amd64",
+		detect: regexp.MustCompile(`(?m)^RIP: `),
+		regs:   linuxX86RegsRe,
+	},
+	{
+		name:   "386",
+		detect: regexp.MustCompile(`(?m)^EIP: `),
+		regs:   linuxX86RegsRe,
+	},
+	{
+		// 32-bit arm prints "pc : [<c01751ac>]    lr : [<c025a42c>]    psr: 80000013".
+		name:   "arm",
+		detect: regexp.MustCompile(`(?m)^pc : \[<[0-9a-f]+>\] +lr : \[<[0-9a-f]+>\] +psr: `),
+		regs:   regexp.MustCompile(`^(?:(?:r[0-9]{1,2}|pc|lr|sp|ip|fp) ?: |Flags: |Backtrace:|Code: )`),
+	},
+	{
+		name:   "arm64",
+		detect: regexp.MustCompile(`(?m)^(?:pstate: |Call trace:|Mem abort info:|Internal error: |PC is at )|\] pstate: `),
+		regs:   regexp.MustCompile(`^(?:(?:x[0-9]{1,2}|pc|lr|sp) ?: |pstate: |Call trace:|Code: )`),
+	},
+	{
+		// Registers are printed as " epc : ffffffff8000405c ra : ffffffff80004a5c sp : ffffffe0006b3d30"
+		// ("sepc: " on older kernels).
+		name:   "riscv64",
+		detect: regexp.MustCompile(`(?m)^ *(?:s?epc ?: [0-9a-f]+ ra ?: |status: [0-9a-f]+ badaddr: )`),
+		regs: regexp.MustCompile(`^ *(?:(?:s?epc|ra|sp|gp|tp|t[0-6]|s[0-9]{1,2}|a[0-7]) ?: |status: |` +
+			`Call Trace:|Code: )`),
+	},
+}
+
+var linuxX86RegsRe = regexp.MustCompile(`^(?:(?:R[A-Z0-9]{1,3}|E[A-Z]{2}|FS|GS|CS|CR[0-9]|DR[0-9]): |` +
+	`Call Trace:|Code: |</?(?:IRQ|TASK)>)`)
+
+// linuxArch returns description of the architecture, amd64 for unknown architectures.
+func linuxArch(name string) *linuxArchDesc {
+	for i := range linuxArchs {
+		if linuxArchs[i].name == name {
+			return &linuxArchs[i]
+		}
+	}
+	return &linuxArchs[0]
+}
+
 var linuxCorruptedTitles = []*regexp.Regexp{
 	// Sometimes timestamps get merged into the middle of report description.
 	regexp.MustCompile(`\[ *[0-9]+\.[0-9]+\]`),
 }
 
 var linuxStackKeywords = []*regexp.Regexp{
-	regexp.MustCompile(`Call Trace`),
+	regexp.MustCompile(`Call [Tt]race`),
 	regexp.MustCompile(`Allocated`),
 	regexp.MustCompile(`Freed`),
 	// Match 'backtrace:', but exclude 'stack backtrace:'
 	regexp.MustCompile(`[^k] backtrace:`),
 }
 
+// linuxTruncatedOops is used to extract title of an oops without header (see parseTruncatedHead).
+var linuxTruncatedOops = &oops{
+	[]byte("---[ end trace"),
+	[]oopsFormat{
+		{
+			name:  "truncated-rip",
+			title: compile("RIP: [0-9]+:(?:{{PC}} +{{PC}} +)?{{FUNC}}"),
+			fmt:   "truncated oops in %[1]v",
+		},
+		{
+			name:  "truncated-pc",
+			title: compile("(?:^|\\n)pc : {{FUNC}}"),
+			fmt:   "truncated oops in %[1]v",
+		},
+		{
+			name:  "truncated-frame",
+			title: compile("(?:^|\\n)[ \\t]*(?:{{PC}} +)?{{FUNC}}0x[0-9a-f]+/0x[0-9a-f]+"),
+			fmt:   "truncated oops in %[1]v",
+		},
+	},
+	[]*regexp.Regexp{},
+}
+
 var linuxOopses = []*oops{
+	&oops{
+		// CONFIG_DEBUG_OBJECTS reports. Newer kernels print the message before
+		// "WARNING: ... at lib/debugobjects.c", older kernels print it after (see warning-odebug).
+		// Debug objects, timer/work init and memory freeing frames are skipped in the stack,
+		// so that the title names the function that owns the object.
+		// The object pointer and the hint are not included in the title.
+		// This oops goes before "BUG:", because the header contains it.
+		[]byte("ODEBUG:"),
+		[]oopsFormat{
+			{
+				name:  "odebug",
+				title: compile("ODEBUG: ((?:init|activate|deactivate|destroy|free|assert_init) [a-z ]+?) \\(active state [0-9]+\\)(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:debug_|kfree|kvfree|vfree|kmem_cache_free|slab_free|free_pages|init_timer|hrtimer_init|init_work)|dump_stack|show_stack|__warn|warn_slowpath|report_bug|fixup_bug|handle_bug|do_error_trap|do_invalid_op|exc_invalid_op|asm_exc_invalid_op|invalid_op|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "ODEBUG: %[1]v in %[2]v",
+			},
+			{
+				name:  "odebug-on-stack",
+				title: compile("ODEBUG: object .* is on stack .*, but NOT annotated(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:debug_|kfree|kvfree|vfree|kmem_cache_free|slab_free|free_pages|init_timer|hrtimer_init|init_work)|dump_stack|show_stack|__warn|warn_slowpath|report_bug|fixup_bug|handle_bug|do_error_trap|do_invalid_op|exc_invalid_op|asm_exc_invalid_op|invalid_op|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "ODEBUG: object is on stack, but not annotated in %[1]v",
+			},
+			{
+				name:      "odebug-nofunc",
+				title:     compile("ODEBUG: ((?:init|activate|deactivate|destroy|free|assert_init) [a-z ]+?) \\(active state [0-9]+\\)"),
+				fmt:       "ODEBUG: %[1]v",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// Execute-protection faults are printed before "BUG: unable to handle kernel paging request".
+		// The title names the function from the RIP/IP line. The kernel usually tries to execute
+		// an address that is not a function (e.g. heap or stack memory), then the RIP line contains
+		// only the raw address and the title names the first function in the stack (the caller).
+		[]byte("kernel tried to execute NX-protected page"),
+		[]oopsFormat{
+			{
+				name:      "exec-nx",
+				title:     compile("kernel tried to execute NX-protected page(?:.*\\n)+?.*(?:RIP: [0-9a-f]{4}:|IP: )(?:{{PC}} +)*{{FUNC}}"),
+				fmt:       "BUG: kernel tried to execute NX-protected page in %[1]v",
+				execFault: true,
+			},
+			{
+				name:      "exec-nx-caller",
+				title:     compile("kernel tried to execute NX-protected page(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:       "BUG: kernel tried to execute NX-protected page in %[1]v",
+				execFault: true,
+			},
+			{
+				name:      "exec-nx-nofunc",
+				title:     compile("kernel tried to execute NX-protected page"),
+				fmt:       "BUG: kernel tried to execute NX-protected page",
+				corrupted: true,
+				execFault: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		[]byte("unable to execute userspace code"),
+		[]oopsFormat{
+			{
+				name:      "exec-user",
+				title:     compile("unable to execute userspace code(?:.*\\n)+?.*(?:RIP: [0-9a-f]{4}:|IP: )(?:{{PC}} +)*{{FUNC}}"),
+				fmt:       "BUG: unable to execute userspace code in %[1]v",
+				execFault: true,
+			},
+			{
+				name:      "exec-user-caller",
+				title:     compile("unable to execute userspace code(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:       "BUG: unable to execute userspace code in %[1]v",
+				execFault: true,
+			},
+			{
+				name:      "exec-user-nofunc",
+				title:     compile("unable to execute userspace code"),
+				fmt:       "BUG: unable to execute userspace code",
+				corrupted: true,
+				execFault: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	executorOops,
 	&oops{
 		[]byte("BUG:"),
 		[]oopsFormat{
 			{
-				title: compile("BUG: KASAN: ([a-z\\-]+) in {{FUNC}}(?:.*\\n)+?.*(Read|Write) of size ([0-9]+)"),
-				fmt:   "KASAN: %[1]v %[3]v in %[2]v",
+				// Some kernels print the bug type on a separate line after "BUG: KASAN:",
+				// so all KASAN formats allow a line break after the header.
+				name:  "kasan",
+				title: compile("BUG: KASAN:[ \\n]+([a-z\\-]+) in {{FUNC}}(?:.*\\n)+?.*(Read|Write) of size ([0-9]+)"),
+				fmt:   "KASAN: %[1]v %[3]v in %[2]v",
+			},
+			{
+				name:  "kasan-address",
+				title: compile("BUG: KASAN:[ \\n]+([a-z\\-]+) on address(?:.*\\n)+?.*(Read|Write) of size ([0-9]+)"),
+				fmt:   "KASAN: %[1]v %[2]v",
+			},
+			{
+				name:  "kasan-generic",
+				title: compile("BUG: KASAN:[ \\n]+(.*)"),
+				fmt:   "KASAN: %[1]v",
+			},
+			// KCSAN reports ASSERT_EXCLUSIVE_* violations as "assert: race", these must go before
+			// the data-race formats, so that they don't end up in the data-race family.
+			// KCSAN stacks don't have "Call Trace:" header.
+			{
+				name:         "kcsan-assert",
+				title:        compile("BUG: KCSAN: assert: race in ([a-zA-Z0-9_.]+) / ([a-zA-Z0-9_.]+)"),
+				fmt:          "KCSAN: assert: race in %[1]v / %[2]v",
+				noStackTrace: true,
+			},
+			{
+				name:         "kcsan-assert-single",
+				title:        compile("BUG: KCSAN: assert: race in ([a-zA-Z0-9_.]+)"),
+				fmt:          "KCSAN: assert: race in %[1]v",
+				noStackTrace: true,
 			},
 			{
-				title: compile("BUG: KASAN: ([a-z\\-]+) on address(?:.*\\n)+?.*(Read|Write) of size ([0-9]+)"),
-				fmt:   "KASAN: %[1]v %[2]v",
+				name:         "kcsan-data-race",
+				title:        compile("BUG: KCSAN: data-race in ([a-zA-Z0-9_.]+) / ([a-zA-Z0-9_.]+)"),
+				fmt:          "KCSAN: data-race in %[1]v / %[2]v",
+				noStackTrace: true,
 			},
 			{
-				title: compile("BUG: KASAN: (.*)"),
-				fmt:   "KASAN: %[1]v",
+				name:         "kcsan-data-race-single",
+				title:        compile("BUG: KCSAN: data-race in ([a-zA-Z0-9_.]+)"),
+				fmt:          "KCSAN: data-race in %[1]v",
+				noStackTrace: true,
 			},
 			{
+				// "Code: Bad RIP value." (printed instead of code bytes) means that RIP is not
+				// a kernel text address, i.e. the kernel jumped to a bad address. Such faults are attributed
+				// to the caller from the stack.
+				name: "exec-bad-rip",
+				title: compile("BUG: (?:unable to handle (?:kernel paging request|kernel NULL pointer dereference|page fault for address)|" +
+					"kernel NULL pointer dereference, address)(?:.*\\n)+?.*Code: Bad RIP value\\.(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:       "BUG: bad RIP value in %[1]v",
+				execFault: true,
+			},
+			{
+				name:  "paging-request",
 				title: compile("BUG: unable to handle kernel paging request(?:.*\\n)+?.*IP: (?:{{PC}} +)?{{FUNC}}"),
 				fmt:   "BUG: unable to handle kernel paging request in %[1]v",
 			},
 			{
+				name:  "paging-request-nofunc",
 				title: compile("BUG: unable to handle kernel paging request"),
 				fmt:   "BUG: unable to handle kernel paging request",
 			},
 			{
+				name:  "null-deref",
 				title: compile("BUG: unable to handle kernel NULL pointer dereference(?:.*\\n)+?.*IP: (?:{{PC}} +)?{{FUNC}}"),
 				fmt:   "BUG: unable to handle kernel NULL pointer dereference in %[1]v",
 			},
 			{
 				// Happens when the kernel tries to execute code at NULL.
+				name:  "null-deref-nofunc",
 				title: compile("BUG: unable to handle kernel NULL pointer dereference"),
 				fmt:   "BUG: unable to handle kernel NULL pointer dereference",
 			},
+			// Since 5.2 page faults are reported with different wording and without "IP:" line,
+			// but they get the same titles as the old wording.
+			{
+				name:  "page-fault",
+				title: compile("BUG: unable to handle page fault for address(?:.*\\n)+?.*RIP: [0-9]+:(?:{{PC}} +{{PC}} +)?{{FUNC}}"),
+				fmt:   "BUG: unable to handle kernel paging request in %[1]v",
+			},
+			{
+				name:  "page-fault-nofunc",
+				title: compile("BUG: unable to handle page fault for address"),
+				fmt:   "BUG: unable to handle kernel paging request",
+			},
+			{
+				name:  "null-deref-address",
+				title: compile("BUG: kernel NULL pointer dereference, address(?:.*\\n)+?.*RIP: [0-9]+:(?:{{PC}} +{{PC}} +)?{{FUNC}}"),
+				fmt:   "BUG: unable to handle kernel NULL pointer dereference in %[1]v",
+			},
+			{
+				name:  "null-deref-address-nofunc",
+				title: compile("BUG: kernel NULL pointer dereference, address"),
+				fmt:   "BUG: unable to handle kernel NULL pointer dereference",
+			},
 			{
 				// Sometimes with such BUG failures, the second part of the header doesn't get printed
 				// or gets corrupted, because kernel prints it as two separate printk() calls.
+				name:      "unable-to-handle",
 				title:     compile("BUG: unable to handle kernel"),
 				fmt:       "BUG: unable to handle kernel",
 				corrupted: true,
 			},
 			{
+				// Spinlock debugging reports are attributed to the function that takes the lock,
+				// lock implementation frames are skipped. CPU and owner are not included in the title.
+				name: "spinlock-caller",
+				title: compile("BUG: spinlock (lockup suspected|already unlocked|recursion|bad magic|wrong owner|wrong CPU) on CPU#" +
+					"(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:raw_|do_raw_|queued_|debug_spin_)[a-z_]*|_*(?:spin|read|write)_(?:lock|unlock|trylock|dump|bug)[a-z_]*|rwlock_bug|dump_stack[a-z_]*|lock_acquire|lock_release)(?:\\.|\\+| ).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "BUG: spinlock %[1]v in %[2]v",
+			},
+			{
+				name:  "spinlock",
 				title: compile("BUG: spinlock (lockup suspected|already unlocked|recursion|bad magic|wrong owner|wrong CPU)"),
 				fmt:   "BUG: spinlock %[1]v",
 			},
 			{
+				name: "rwlock-caller",
+				title: compile("BUG: rwlock (lockup suspected|already unlocked|recursion|bad magic|wrong owner|wrong CPU) on CPU#" +
+					"(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:raw_|do_raw_|queued_|debug_spin_)[a-z_]*|_*(?:spin|read|write)_(?:lock|unlock|trylock|dump|bug)[a-z_]*|rwlock_bug|dump_stack[a-z_]*|lock_acquire|lock_release)(?:\\.|\\+| ).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "BUG: rwlock %[1]v in %[2]v",
+			},
+			{
+				name:  "rwlock",
+				title: compile("BUG: rwlock (lockup suspected|already unlocked|recursion|bad magic|wrong owner|wrong CPU)"),
+				fmt:   "BUG: rwlock %[1]v",
+			},
+			{
+				name:  "soft-lockup",
 				title: compile("BUG: soft lockup"),
 				fmt:   "BUG: soft lockup",
 			},
 			{
+				name:  "locks-held",
 				title: compile("BUG: .*still has locks held!(?:.*\\n)+?.*{{PC}} +{{FUNC}}"),
 				fmt:   "BUG: still has locks held in %[1]v",
 			},
 			{
+				name:   "bad-unlock-balance",
 				title:  compile("BUG: bad unlock balance detected!(?:.*\\n)+?.*{{PC}} +{{FUNC}}"),
 				report: compile("BUG: bad unlock balance detected!(?:.*\\n){0,5}?.*is trying to release lock"),
 				fmt:    "BUG: bad unlock balance in %[1]v",
 			},
 			{
 				// If we failed to extract function name where the fault happened, the report is most likely truncated.
+				name:      "bad-unlock-balance-nofunc",
 				title:     compile("BUG: bad unlock balance detected!"),
 				fmt:       "BUG: bad unlock balance",
 				corrupted: true,
 			},
 			{
+				name:  "held-lock-freed",
 				title: compile("BUG: held lock freed!(?:.*\\n)+?.*{{PC}} +{{FUNC}}"),
 				fmt:   "BUG: held lock freed in %[1]v",
 			},
 			{
+				name:         "bad-rss-counter",
 				title:        compile("BUG: Bad rss-counter state"),
 				fmt:          "BUG: Bad rss-counter state",
 				noStackTrace: true,
 			},
 			{
+				name:         "nr-ptes",
 				title:        compile("BUG: non-zero nr_ptes on freeing mm"),
 				fmt:          "BUG: non-zero nr_ptes on freeing mm",
 				noStackTrace: true,
 			},
 			{
+				name:         "nr-pmds",
 				title:        compile("BUG: non-zero nr_pmds on freeing mm"),
 				fmt:          "BUG: non-zero nr_pmds on freeing mm",
 				noStackTrace: true,
 			},
 			{
-				title: compile("BUG: Dentry .* still in use \\([0-9]+\\) \\[unmount of ([^\\]]+)\\]"),
+				// Printed as "[unmount of FSTYPE DEVICE]", the device name is not included in the title.
+				name:  "dentry-in-use",
+				title: compile("BUG: Dentry .* still in use \\([0-9]+\\) \\[unmount of ([^ \\]]+)[^\\]]*\\]"),
 				fmt:   "BUG: Dentry still in use [unmount of %[1]v]",
 			},
 			{
+				name:  "bad-page-state",
 				title: compile("BUG: Bad page state.*"),
 				fmt:   "BUG: Bad page state",
 			},
 			{
+				name:  "bad-page-map",
 				title: compile("BUG: Bad page map.*"),
 				fmt:   "BUG: Bad page map",
 			},
 			{
-				title: compile("BUG: spinlock bad magic.*"),
-				fmt:   "BUG: spinlock bad magic",
-			},
-			{
+				name:         "workqueue-lockup",
 				title:        compile("BUG: workqueue lockup.*"),
 				fmt:          "BUG: workqueue lockup",
 				noStackTrace: true,
 			},
 			{
+				name:  "sleeping-in-invalid-context",
 				title: compile("BUG: sleeping function called from invalid context (.*)"),
 				fmt:   "BUG: sleeping function called from invalid context %[1]v",
 			},
 			{
+				// Lockdep is out of its static limits and turns itself off,
+				// this is not a kernel bug (see Options.SuppressInfraLimits).
+				name:         "lockdep-limit",
+				title:        compile("BUG: (MAX_[A-Z_]+) too low!"),
+				fmt:          "BUG: %[1]v too low!",
+				noStackTrace: true,
+				infra:        true,
+			},
+			{
+				name: "smp-processor-id-preemptible",
+				title: compile("BUG: using smp_processor_id\\(\\) in preemptible(?:.*\\n)+?.*Call Trace:\\n" +
+					"(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*dump_stack|check_preemption_disabled|" +
+					"debug_smp_processor_id|__this_cpu_preempt_check).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "BUG: using smp_processor_id() in preemptible code in %[1]v",
+			},
+			{
+				name:  "smp-processor-id-preemptible-nofunc",
+				title: compile("BUG: using smp_processor_id\\(\\) in preemptible"),
+				fmt:   "BUG: using smp_processor_id() in preemptible code",
+			},
+			{
+				name:  "this-cpu-add-preemptible",
 				title: compile("BUG: using __this_cpu_add\\(\\) in preemptible (.*)"),
 				fmt:   "BUG: using __this_cpu_add() in preemptible %[1]v",
 			},
@@ -552,60 +2489,137 @@ var linuxOopses = []*oops{
 		[]byte("WARNING:"),
 		[]oopsFormat{
 			{
-				title: compile("WARNING: .* at {{SRC}} {{FUNC}}"),
-				fmt:   "WARNING in %[2]v",
+				name: "warning-list-corruption",
+				title: compile("WARNING: .* at lib/list_debug\\.c:[0-9]+(?:.*\\n)+?.*list_(?:add|del) corruption" +
+					"(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*h?list_(?:add|del|move|replace|splice)|dump_stack|__warn|warn_slowpath|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "list corruption in %[1]v",
+			},
+			{
+				name: "warning-odebug",
+				title: compile("WARNING: .* at lib/debugobjects\\.c:[0-9]+ debug_print_object(?:.*\\n)+?.*ODEBUG: ((?:init|activate|deactivate|destroy|free|assert_init) [a-z ]+?) \\(active state [0-9]+\\)" +
+					"(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:debug_|kfree|kvfree|vfree|kmem_cache_free|slab_free|free_pages|init_timer|hrtimer_init|init_work)|dump_stack|show_stack|__warn|warn_slowpath|report_bug|fixup_bug|handle_bug|do_error_trap|do_invalid_op|exc_invalid_op|asm_exc_invalid_op|invalid_op|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "ODEBUG: %[1]v in %[2]v",
+			},
+			{
+				name: "warning-sysfs-duplicate",
+				title: compile("WARNING: .* at fs/sysfs/dir\\.c:[0-9]+ sysfs_warn_dup(?:.*\\n)+?.*Call Trace:\\n" +
+					"(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:sysfs|kernfs|kobject|kset|device|driver|bus|class)_|dump_stack|show_stack|__warn|warn_slowpath|report_bug|panic|netdev_register_kobject|register_netdevice).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "sysfs: cannot create duplicate filename in %[1]v",
+			},
+			{
+				name: "warning-kobject-add",
+				title: compile("WARNING: .* at lib/kobject\\.c:[0-9]+ kobject_add_internal(?:.*\\n)+?.*Call Trace:\\n" +
+					"(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:sysfs|kernfs|kobject|kset|device|driver|bus|class)_|dump_stack|show_stack|__warn|warn_slowpath|report_bug|panic|netdev_register_kobject|register_netdevice).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "kobject_add_internal failed in %[1]v",
+			},
+			{
+				name:    "warning",
+				title:   compile("WARNING: .* at {{SRC}} {{FUNC}}"),
+				fmt:     "WARNING in %[2]v",
+				message: true,
 			},
 			{
+				name:  "warning-circular-locking",
 				title: compile("WARNING: possible circular locking dependency detected(?:.*\\n)+?.*is trying to acquire lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "warning-circular-locking-nofunc",
 				title: compile("WARNING: possible circular locking dependency detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "warning-irq-lock-inversion",
 				title: compile("WARNING: possible irq lock inversion dependency detected(?:.*\\n)+?.*just changed the state of lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "warning-irq-lock-inversion-nofunc",
 				title: compile("WARNING: possible irq lock inversion dependency detected"),
 				fmt:   "possible deadlock",
 			},
 			{
-				title: compile("WARNING: SOFTIRQ-safe -> SOFTIRQ-unsafe lock order detected(?:.*\\n)+?.*is trying to acquire(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
+				name:  "warning-irq-lock-order",
+				title: compile("WARNING: (?:SOFT|HARD)IRQ-safe -> (?:SOFT|HARD)IRQ-unsafe lock order detected(?:.*\\n)+?.*is trying to acquire(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
-				title: compile("WARNING: SOFTIRQ-safe -> SOFTIRQ-unsafe lock order detected"),
+				name:  "warning-irq-lock-order-nofunc",
+				title: compile("WARNING: (?:SOFT|HARD)IRQ-safe -> (?:SOFT|HARD)IRQ-unsafe lock order detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "warning-recursive-locking",
 				title: compile("WARNING: possible recursive locking detected(?:.*\\n)+?.*is trying to acquire lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "warning-recursive-locking-nofunc",
 				title: compile("WARNING: possible recursive locking detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "warning-inconsistent-lock-state",
 				title: compile("WARNING: inconsistent lock state(?:.*\\n)+?.*takes(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "inconsistent lock state in %[1]v",
 			},
 			{
+				name:  "warning-held-lock-freed",
+				title: compile("WARNING: held lock freed!(?:.*\\n)+?.*with a lock still held there!(?:.*\\n)+?.*at: (?:{{PC}} +)?{{FUNC}}"),
+				fmt:   "BUG: held lock freed in %[1]v",
+			},
+			{
+				name:      "warning-held-lock-freed-nofunc",
+				title:     compile("WARNING: held lock freed!"),
+				fmt:       "BUG: held lock freed",
+				corrupted: true,
+			},
+			{
+				// The function that released the lock is printed after "is trying to release lock (...) at:".
+				name: "warning-bad-unlock-balance",
+				title: compile("WARNING: bad unlock balance detected!(?:.*\\n)+?.*is trying to release lock.*at:\\n" +
+					"(?:.*\\] )? *(?:{{PC}} +)?{{FUNC}}"),
+				fmt: "BUG: bad unlock balance in %[1]v",
+			},
+			{
+				name:      "warning-bad-unlock-balance-nofunc",
+				title:     compile("WARNING: bad unlock balance detected!"),
+				fmt:       "BUG: bad unlock balance",
+				corrupted: true,
+			},
+			{
+				// Printed on syscall exit, there is no stack, only the list of held locks.
+				name: "warning-lock-held-returning",
+				title: compile("WARNING: lock held when returning to user space!(?:.*\\n)+?.*leaving the kernel with locks still held!" +
+					"(?:.*\\n)+?.*at: (?:{{PC}} +)?{{FUNC}}"),
+				fmt:          "WARNING: lock held when returning to user space in %[1]v",
+				noStackTrace: true,
+			},
+			{
+				name:      "warning-lock-held-returning-nofunc",
+				title:     compile("WARNING: lock held when returning to user space!"),
+				fmt:       "WARNING: lock held when returning to user space",
+				corrupted: true,
+			},
+			{
+				name:  "warning-suspicious-rcu",
 				title: compile("WARNING: suspicious RCU usage(?:.*\n)+?.*?{{SRC}}"),
 				fmt:   "suspicious RCU usage at %[1]v",
 			},
 			{
+				name:      "warning-suspicious-rcu-nosrc",
 				title:     compile("WARNING: suspicious RCU usage"),
 				fmt:       "suspicious RCU usage",
 				corrupted: true,
 			},
 			{
+				name:         "warning-stack-regs",
 				title:        compile("WARNING: kernel stack regs at [0-9a-f]+ in [^ ]* has bad '([^']+)' value"),
 				fmt:          "WARNING: kernel stack regs has bad '%[1]v' value",
 				noStackTrace: true,
 			},
 			{
+				name:         "warning-stack-frame-pointer",
 				title:        compile("WARNING: kernel stack frame pointer at [0-9a-f]+ in [^ ]* has bad value"),
 				fmt:          "WARNING: kernel stack frame pointer has bad value",
 				noStackTrace: true,
@@ -619,103 +2633,149 @@ var linuxOopses = []*oops{
 		[]byte("INFO:"),
 		[]oopsFormat{
 			{
+				name:  "info-circular-locking",
 				title: compile("INFO: possible circular locking dependency detected \\](?:.*\\n)+?.*is trying to acquire lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "info-circular-locking-nofunc",
 				title: compile("INFO: possible circular locking dependency detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "info-irq-lock-inversion",
 				title: compile("INFO: possible irq lock inversion dependency detected \\](?:.*\\n)+?.*just changed the state of lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "info-irq-lock-inversion-nofunc",
 				title: compile("INFO: possible irq lock inversion dependency detected"),
 				fmt:   "possible deadlock",
 			},
 			{
-				title: compile("INFO: SOFTIRQ-safe -> SOFTIRQ-unsafe lock order detected \\](?:.*\\n)+?.*is trying to acquire(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
+				name:  "info-irq-lock-order",
+				title: compile("INFO: (?:SOFT|HARD)IRQ-safe -> (?:SOFT|HARD)IRQ-unsafe lock order detected \\](?:.*\\n)+?.*is trying to acquire(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
-				title: compile("INFO: SOFTIRQ-safe -> SOFTIRQ-unsafe lock order detected"),
+				name:  "info-irq-lock-order-nofunc",
+				title: compile("INFO: (?:SOFT|HARD)IRQ-safe -> (?:SOFT|HARD)IRQ-unsafe lock order detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "info-recursive-locking",
 				title: compile("INFO: possible recursive locking detected \\](?:.*\\n)+?.*is trying to acquire lock(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "possible deadlock in %[1]v",
 			},
 			{
+				name:  "info-recursive-locking-nofunc",
 				title: compile("INFO: possible recursive locking detected"),
 				fmt:   "possible deadlock",
 			},
 			{
+				name:  "info-inconsistent-lock-state",
 				title: compile("INFO: inconsistent lock state \\](?:.*\\n)+?.*takes(?:.*\\n)+?.*at: {{PC}} +{{FUNC}}"),
 				fmt:   "inconsistent lock state in %[1]v",
 			},
 			{
-				title: compile("INFO: rcu_preempt detected stalls(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
+				// RCU stall banners differ across kernel versions: newer kernels prefix them with "rcu: ",
+				// older kernels print "rcu_sched_state" and "detected stall on CPU", and there are
+				// expedited and self-detected stalls. All of them are titled "INFO: rcu detected stall".
+				name:  "rcu-preempt-stall",
+				title: compile("INFO: rcu_preempt detected(?: expedited)? stalls?(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
 				fmt:   "INFO: rcu detected stall in %[1]v",
 			},
 			{
-				title: compile("INFO: rcu_preempt detected stalls"),
+				name:  "rcu-preempt-stall-nofunc",
+				title: compile("INFO: rcu_preempt detected(?: expedited)? stalls?"),
 				fmt:   "INFO: rcu detected stall",
 			},
 			{
-				title: compile("INFO: rcu_sched detected(?: expedited)? stalls(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
+				name:  "rcu-sched-stall",
+				title: compile("INFO: rcu_sched(?:_state)? detected(?: expedited)? stalls?(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
 				fmt:   "INFO: rcu detected stall in %[1]v",
 			},
 			{
-				title: compile("INFO: rcu_sched detected(?: expedited)? stalls"),
+				name:  "rcu-sched-stall-nofunc",
+				title: compile("INFO: rcu_sched(?:_state)? detected(?: expedited)? stalls?"),
 				fmt:   "INFO: rcu detected stall",
 			},
 			{
+				name:  "rcu-preempt-self-stall",
 				title: compile("INFO: rcu_preempt self-detected stall on CPU(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
 				fmt:   "INFO: rcu detected stall in %[1]v",
 			},
 			{
+				name:  "rcu-preempt-self-stall-nofunc",
 				title: compile("INFO: rcu_preempt self-detected stall on CPU"),
 				fmt:   "INFO: rcu detected stall",
 			},
 			{
+				name:  "rcu-sched-self-stall",
 				title: compile("INFO: rcu_sched self-detected stall on CPU(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
 				fmt:   "INFO: rcu detected stall in %[1]v",
 			},
 			{
+				name:  "rcu-sched-self-stall-nofunc",
 				title: compile("INFO: rcu_sched self-detected stall on CPU"),
 				fmt:   "INFO: rcu detected stall",
 			},
 			{
-				title: compile("INFO: rcu_bh detected stalls on CPU"),
+				name:  "rcu-bh-stall",
+				title: compile("INFO: rcu_bh(?:_state)? (?:self-)?detected(?: expedited)? stalls?(?:.*\\n)+?.*</IRQ>.*\n(?:.*rcu.*\\n)+? {{FUNC}}"),
+				fmt:   "INFO: rcu detected stall in %[1]v",
+			},
+			{
+				name:  "rcu-bh-stall-nofunc",
+				title: compile("INFO: rcu_bh(?:_state)? (?:self-)?detected(?: expedited)? stalls?"),
 				fmt:   "INFO: rcu detected stall",
 			},
 			{
-				title: compile("INFO: trying to register non-static key(?:.*\\n){0,10}Call Trace:\\n(?:(?:.*stack.*\\n)|(?:.*lock.*\\n)|(?:.*IRQ.*\\n))+ {{FUNC}}"),
+				name:  "non-static-key",
+				title: compile("INFO: trying to register non-static key(?:.*\\n){0,10}Call Trace:\\n(?:(?:.*stack.*\\n)|(?:.*lock.*\\n)|(?:.*IRQ.*\\n)|(?:.*TASK>.*\\n))+ {{FUNC}}"),
 				fmt:   "INFO: trying to register non-static key in %[1]v",
 			},
 			{
+				name:  "non-static-key-nofunc",
 				title: compile("INFO: trying to register non-static key"),
 				fmt:   "INFO: trying to register non-static key",
 			},
 			{
+				name:  "info-suspicious-rcu",
 				title: compile("INFO: suspicious RCU usage(?:.*\n)+?.*?{{SRC}}"),
 				fmt:   "suspicious RCU usage at %[1]v",
 			},
 			{
+				name:      "info-suspicious-rcu-nosrc",
 				title:     compile("INFO: suspicious RCU usage"),
 				fmt:       "suspicious RCU usage",
 				corrupted: true,
 			},
 			{
-				title: compile("INFO: task .* blocked for more than [0-9]+ seconds(?:.*\\n){0,10}Call Trace:\\n(?:.*(?:sched|_lock|completion|kthread).*\\n)* (?:{{PC}} )?{{FUNC}}"),
-				fmt:   "INFO: task hung in %[1]v",
+				name: "info-bad-unlock-balance",
+				title: compile("INFO: bad unlock balance detected!(?:.*\\n)+?.*is trying to release lock.*at:\\n" +
+					"(?:.*\\] )? *(?:{{PC}} +)?{{FUNC}}"),
+				fmt: "BUG: bad unlock balance in %[1]v",
+			},
+			{
+				name:      "info-bad-unlock-balance-nofunc",
+				title:     compile("INFO: bad unlock balance detected!"),
+				fmt:       "BUG: bad unlock balance",
+				corrupted: true,
+			},
+			{
+				name:     "task-hung",
+				title:    compile("INFO: task .* blocked for more than [0-9]+ seconds(?:.*\\n){0,10}Call [Tt]race:\\n(?:.*(?:sched|_lock|completion|kthread|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:      "INFO: task hung in %[1]v",
+				hungTask: true,
 			},
 			{
+				name:  "task-hung-nofunc",
 				title: compile("INFO: task .* blocked for more than [0-9]+ seconds"),
 				fmt:   "INFO: task hung",
 			},
 			{
+				name:         "readonly-recovery",
 				title:        compile("INFO: recovery required on readonly filesystem"),
 				fmt:          "INFO: recovery required on readonly filesystem",
 				noStackTrace: true,
@@ -729,28 +2789,139 @@ var linuxOopses = []*oops{
 		},
 	},
 	&oops{
-		[]byte("Unable to handle kernel paging request"),
+		// arm/arm64 and riscv page faults. Old arm kernels print the faulting function as
+		// "PC is at func+0x38/0x1a8", newer arm64 kernels print it in the registers dump
+		// as "pc : func+0x38/0x1a8", and riscv as "epc : func+0x38/0x1a8".
+		// arm64 also prints "Unable to handle kernel access to user memory outside uaccess routines"
+		// for PAN violations and riscv "... without uaccess routines" for user memory accesses
+		// with SUM bit cleared, which is the same as x86 SMAP violation.
+		[]byte("Unable to handle kernel "),
 		[]oopsFormat{
 			{
-				title: compile("Unable to handle kernel paging request(?:.*\\n)+?.*PC is at {{FUNC}}"),
+				name: "arm-user-access",
+				title: compile("Unable to handle kernel (?:read |write )?access to user memory (?:outside|without) uaccess routines" +
+					"(?:.*\\n)+?(?:.*PC is at|pc :|epc :) {{FUNC}}"),
+				fmt:             "unable to handle kernel access to user memory in %[1]v",
+				userAccessFault: true,
+			},
+			{
+				name:            "arm-user-access-nofunc",
+				title:           compile("Unable to handle kernel (?:read |write )?access to user memory (?:outside|without) uaccess routines"),
+				fmt:             "unable to handle kernel access to user memory",
+				corrupted:       true,
+				userAccessFault: true,
+			},
+			{
+				name:  "arm-paging-request",
+				title: compile("Unable to handle kernel paging request(?:.*\\n)+?(?:.*PC is at|pc :|epc :) {{FUNC}}"),
 				fmt:   "unable to handle kernel paging request in %[1]v",
 			},
+			{
+				name:      "arm-paging-request-nofunc",
+				title:     compile("Unable to handle kernel paging request"),
+				fmt:       "unable to handle kernel paging request",
+				corrupted: true,
+			},
+			{
+				name:  "arm-null-ptr-deref",
+				title: compile("Unable to handle kernel NULL pointer dereference(?:.*\\n)+?(?:.*PC is at|pc :|epc :) {{FUNC}}"),
+				fmt:   "unable to handle kernel NULL pointer dereference in %[1]v",
+			},
+			{
+				name:      "arm-null-ptr-deref-nofunc",
+				title:     compile("Unable to handle kernel NULL pointer dereference"),
+				fmt:       "unable to handle kernel NULL pointer dereference",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// arm/arm64 die() line, e.g. "Internal error: Oops: 96000006 [#1] PREEMPT SMP".
+		// Page faults normally have "Unable to handle kernel ..." line before this one,
+		// so this catches other exceptions and page faults with lost first line.
+		[]byte("Internal error: "),
+		[]oopsFormat{
+			{
+				name:  "arm-undefined-instruction",
+				title: compile("Internal error: Oops - undefined instruction:(?:.*\\n)+?(?:.*PC is at|pc :) {{FUNC}}"),
+				fmt:   "invalid opcode in %[1]v",
+			},
+			{
+				name:  "arm-internal-error",
+				title: compile("Internal error: (?:.*\\n)+?(?:.*PC is at|pc :) {{FUNC}}"),
+				fmt:   "internal error in %[1]v",
+			},
+			{
+				name:      "arm-internal-error-nofunc",
+				title:     compile("Internal error: "),
+				fmt:       "internal error",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// riscv die() line for exceptions other than page faults, e.g. "Oops - illegal instruction [#1]".
+		[]byte("Oops - "),
+		[]oopsFormat{
+			{
+				name:  "riscv-illegal-instruction",
+				title: compile("Oops - illegal instruction \\[#[0-9]+\\](?:.*\\n)+?epc : {{FUNC}}"),
+				fmt:   "invalid opcode in %[1]v",
+			},
+			{
+				name:  "riscv-oops",
+				title: compile("Oops - ([a-zA-Z][a-zA-Z ]*?) \\[#[0-9]+\\](?:.*\\n)+?epc : {{FUNC}}"),
+				fmt:   "%[1]v in %[2]v",
+			},
+			{
+				name:      "riscv-oops-nofunc",
+				title:     compile("Oops - ([a-zA-Z][a-zA-Z ]*?) \\[#[0-9]+\\]"),
+				fmt:       "%[1]v",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// riscv die() line for page faults. It normally follows "Unable to handle kernel ..." line,
+		// so this catches page faults with lost first line.
+		[]byte("Oops [#"),
+		[]oopsFormat{
+			{
+				name:  "riscv-page-fault",
+				title: compile("Oops \\[#[0-9]+\\](?:.*\\n)+?epc : {{FUNC}}"),
+				fmt:   "Oops in %[1]v",
+			},
+			{
+				name:      "riscv-page-fault-nofunc",
+				title:     compile("Oops \\[#[0-9]+\\]"),
+				fmt:       "Oops",
+				corrupted: true,
+			},
 		},
 		[]*regexp.Regexp{},
 	},
 	&oops{
-		[]byte("general protection fault:"),
+		// Newer kernels print "general protection fault, probably for non-canonical address 0x...: 0000 [#1]".
+		[]byte("general protection fault"),
 		[]oopsFormat{
 			{
-				title: compile("general protection fault:(?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
-				fmt:   "general protection fault in %[1]v",
+				name: "gpf-pc",
+				title: compile("general protection fault(?:, probably for non-canonical address {{ADDR}})?:" +
+					"(?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
+				fmt: "general protection fault in %[1]v",
 			},
 			{
-				title: compile("general protection fault:(?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
-				fmt:   "general protection fault in %[1]v",
+				name: "gpf",
+				title: compile("general protection fault(?:, probably for non-canonical address {{ADDR}})?:" +
+					"(?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
+				fmt: "general protection fault in %[1]v",
 			},
 			{
 				// If we failed to extract function name where the fault happened, the report is most likely truncated.
+				name:      "gpf-nofunc",
 				title:     compile("general protection fault"),
 				fmt:       "general protection fault",
 				corrupted: true,
@@ -762,10 +2933,12 @@ var linuxOopses = []*oops{
 		[]byte("Kernel panic"),
 		[]oopsFormat{
 			{
+				name:  "panic-kill-init",
 				title: compile("Kernel panic - not syncing: Attempted to kill init!"),
 				fmt:   "kernel panic: Attempted to kill init!",
 			},
 			{
+				name:  "panic-n-tty",
 				title: compile("Kernel panic - not syncing: Couldn't open N_TTY ldisc for [^ ]+ --- error -[0-9]+"),
 				fmt:   "kernel panic: Couldn't open N_TTY ldisc",
 			},
@@ -773,27 +2946,219 @@ var linuxOopses = []*oops{
 				// 'kernel panic: Fatal exception' is usually printed after BUG,
 				// so if we captured it as a report description, that means the
 				// report got truncated and we missed the actual BUG header.
+				name:      "panic-fatal-exception",
 				title:     compile("Kernel panic - not syncing: Fatal exception"),
 				fmt:       "kernel panic: Fatal exception",
 				corrupted: true,
 			},
 			{
 				// Same, but for WARNINGs and KASAN reports.
+				name:      "panic-on-warn",
 				title:     compile("Kernel panic - not syncing: panic_on_warn set"),
 				fmt:       "kernel panic: panic_on_warn set",
 				corrupted: true,
 			},
 			{
+				// The whole system is out of memory and the OOM killer can't help
+				// (or panic_on_oom is set). OOM kills are reported separately (see oom-kill).
+				name:  "panic-oom",
+				title: compile("Kernel panic - not syncing: (?:Out of memory|System is deadlocked on memory)"),
+				fmt:   "out of memory",
+			},
+			{
+				name:  "panic",
 				title: compile("Kernel panic - not syncing: (.*)"),
 				fmt:   "kernel panic: %[1]v",
 			},
 		},
 		[]*regexp.Regexp{},
 	},
+	&oops{
+		// Inodes are leaked after unmount, e.g. "VFS: Busy inodes after unmount of loop0 (vfat)"
+		// or "VFS: Busy inodes after unmount of sda. Self-destruct in 5 seconds.  Have a nice day...".
+		// The title contains the filesystem type if it's printed, DEV placeholder otherwise.
+		[]byte("VFS: Busy inodes after unmount of"),
+		[]oopsFormat{
+			{
+				name:         "vfs-busy-inodes",
+				title:        compile("VFS: Busy inodes after unmount of [^ ]+ \\(([^ )]+)\\)"),
+				fmt:          "VFS: Busy inodes after unmount of %[1]v",
+				noStackTrace: true,
+			},
+			{
+				// Older kernels don't print the filesystem type, and device names are not stable.
+				name:         "vfs-busy-inodes-dev",
+				title:        compile("VFS: Busy inodes after unmount of [^ .]+"),
+				fmt:          "VFS: Busy inodes after unmount of DEV",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// Timekeeping watchdog messages are common on virtualized machines
+		// and are just informational (see Options.SuppressInformational).
+		[]byte("clocksource: timekeeping watchdog"),
+		[]oopsFormat{
+			{
+				name:          "clocksource-watchdog",
+				title:         compile("clocksource: timekeeping watchdog on CPU[0-9]+: Marking clocksource '([^']+)' as unstable"),
+				fmt:           "clocksource: %[1]v marked unstable by watchdog",
+				noStackTrace:  true,
+				informational: true,
+			},
+			{
+				name:          "clocksource-watchdog-noname",
+				title:         compile("clocksource: timekeeping watchdog"),
+				fmt:           "clocksource: timekeeping watchdog",
+				noStackTrace:  true,
+				informational: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// Older kernels print "Clocksource tsc unstable (delta = 4398046511104 ns)".
+		[]byte("unstable (delta = "),
+		[]oopsFormat{
+			{
+				name:          "clocksource-unstable",
+				title:         compile("Clocksource ([^ ]+) unstable \\(delta = "),
+				fmt:           "clocksource: %[1]v unstable",
+				noStackTrace:  true,
+				informational: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// The stuck CPU stack is the main stack of the report (see parseLinuxStacks).
+		// Hard lockups (the CPU doesn't handle interrupts) are titled differently from soft lockups
+		// (the CPU doesn't schedule), the title contains the function the stuck CPU executes:
+		// the RIP of the stuck CPU (printed either by the CPU itself, or in its NMI backtrace
+		// if the lockup is detected by another CPU), or the first frame of the NMI backtrace.
+		[]byte("Watchdog detected hard LOCKUP on cpu"),
+		[]oopsFormat{
+			{
+				name:  "hard-lockup",
+				title: compile("Watchdog detected hard LOCKUP on cpu(?:.*\\n)+?.*RIP: [0-9]+:(?:{{PC}} +{{PC}} +)?{{FUNC}}"),
+				fmt:   "BUG: hard lockup in %[1]v",
+			},
+			{
+				name: "hard-lockup-nmi-stack",
+				title: compile("Watchdog detected hard LOCKUP on cpu(?:.*\\n)+?.*NMI backtrace for cpu(?:.*\\n)+?.*Call Trace:\\n" +
+					"(?:.*(?:\\[inline\\]|IRQ>|NMI>|TASK>).*\\n| (?:{{PC}} )?(?:nmi_|dump_stack).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt: "BUG: hard lockup in %[1]v",
+			},
+			{
+				name:  "hard-lockup-nofunc",
+				title: compile("Watchdog detected hard LOCKUP on cpu"),
+				fmt:   "BUG: hard lockup",
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// OOM killer killed a process, this is less severe than OOM panic (see panic-oom).
+		// The title includes name of the victim without PID and numeric suffix (e.g. syz-executor3).
+		[]byte("Out of memory: Kill"),
+		[]oopsFormat{
+			{
+				name:         "oom-kill",
+				title:        compile("Out of memory: Kill(?:ed)? process [0-9]+ \\(([^)]*?)[0-9]*\\)"),
+				fmt:          "out of memory: killed %[1]v",
+				noStackTrace: true,
+			},
+			{
+				name:         "oom-kill-noproc",
+				title:        compile("Out of memory: Kill(?:ed)? process"),
+				fmt:          "out of memory: killed process",
+				noStackTrace: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// Newer kernels print sysfs/kobject registration failures with dump_stack instead of WARNING
+		// (older kernels, see warning-sysfs-duplicate and warning-kobject-add).
+		// Sysfs/kobject and driver core frames are skipped in the stack, so that the title names
+		// the driver that registers the object. The duplicate name is not included in the title.
+		[]byte("sysfs: cannot create duplicate filename"),
+		[]oopsFormat{
+			{
+				name:  "sysfs-duplicate",
+				title: compile("sysfs: cannot create duplicate filename(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:sysfs|kernfs|kobject|kset|device|driver|bus|class)_|dump_stack|show_stack|__warn|warn_slowpath|report_bug|panic|netdev_register_kobject|register_netdevice).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "sysfs: cannot create duplicate filename in %[1]v",
+			},
+			{
+				name:      "sysfs-duplicate-nofunc",
+				title:     compile("sysfs: cannot create duplicate filename"),
+				fmt:       "sysfs: cannot create duplicate filename",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		[]byte("kobject_add_internal failed"),
+		[]oopsFormat{
+			{
+				name:  "kobject-add",
+				title: compile("kobject_add_internal failed(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*(?:sysfs|kernfs|kobject|kset|device|driver|bus|class)_|dump_stack|show_stack|__warn|warn_slowpath|report_bug|panic|netdev_register_kobject|register_netdevice).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "kobject_add_internal failed in %[1]v",
+			},
+			{
+				name:      "kobject-add-nofunc",
+				title:     compile("kobject_add_internal failed"),
+				fmt:       "kobject_add_internal failed",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		// CONFIG_DEBUG_LIST reports. Newer kernels print the message before "kernel BUG at lib/list_debug.c",
+		// older kernels print it after "WARNING: ... at lib/list_debug.c" (see warning-list-corruption).
+		// List helpers are skipped in the stack, so that the title names the function
+		// that manipulates the corrupted list.
+		[]byte("list_add corruption"),
+		[]oopsFormat{
+			{
+				name:  "list-add-corruption",
+				title: compile("list_add corruption(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*h?list_(?:add|del|move|replace|splice)|dump_stack|__warn|warn_slowpath|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "list corruption in %[1]v",
+			},
+			{
+				name:      "list-add-corruption-nofunc",
+				title:     compile("list_add corruption"),
+				fmt:       "list corruption",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	&oops{
+		[]byte("list_del corruption"),
+		[]oopsFormat{
+			{
+				name:  "list-del-corruption",
+				title: compile("list_del corruption(?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n| (?:{{PC}} )?(?:_*h?list_(?:add|del|move|replace|splice)|dump_stack|__warn|warn_slowpath|panic).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "list corruption in %[1]v",
+			},
+			{
+				name:      "list-del-corruption-nofunc",
+				title:     compile("list_del corruption"),
+				fmt:       "list corruption",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
 	&oops{
 		[]byte("kernel BUG"),
 		[]oopsFormat{
 			{
+				name:  "kernel-bug",
 				title: compile("kernel BUG (.*)"),
 				fmt:   "kernel BUG %[1]v",
 			},
@@ -804,6 +3169,7 @@ var linuxOopses = []*oops{
 		[]byte("Kernel BUG"),
 		[]oopsFormat{
 			{
+				name:  "kernel-bug-arm",
 				title: compile("Kernel BUG (.*)"),
 				fmt:   "kernel BUG %[1]v",
 			},
@@ -814,6 +3180,7 @@ var linuxOopses = []*oops{
 		[]byte("BUG kmalloc-"),
 		[]oopsFormat{
 			{
+				name:  "kmalloc-double-free",
 				title: compile("BUG kmalloc-.*: Object already free"),
 				fmt:   "BUG: Object already free",
 			},
@@ -824,15 +3191,24 @@ var linuxOopses = []*oops{
 		[]byte("divide error:"),
 		[]oopsFormat{
 			{
+				name:  "divide-error-pc",
 				title: compile("divide error: (?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
 				fmt:   "divide error in %[1]v",
 			},
 			{
+				name:  "divide-error",
 				title: compile("divide error: (?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
 				fmt:   "divide error in %[1]v",
 			},
+			{
+				// RIP is not a function (e.g. "RIP: 0010:0x..."), take the function from the stack.
+				name:  "divide-error-stack",
+				title: compile("divide error: (?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "divide error in %[1]v",
+			},
 			{
 				// If we failed to extract function name where the fault happened, the report is most likely truncated.
+				name:      "divide-error-nofunc",
 				title:     compile("divide error"),
 				fmt:       "divide error",
 				corrupted: true,
@@ -844,15 +3220,24 @@ var linuxOopses = []*oops{
 		[]byte("invalid opcode:"),
 		[]oopsFormat{
 			{
+				name:  "invalid-opcode-pc",
 				title: compile("invalid opcode: (?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
 				fmt:   "invalid opcode in %[1]v",
 			},
 			{
+				name:  "invalid-opcode",
 				title: compile("invalid opcode: (?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
 				fmt:   "invalid opcode in %[1]v",
 			},
+			{
+				// RIP is not a function (e.g. "RIP: 0010:0x..."), take the function from the stack.
+				name:  "invalid-opcode-stack",
+				title: compile("invalid opcode: (?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "invalid opcode in %[1]v",
+			},
 			{
 				// If we failed to extract function name where the fault happened, the report is most likely truncated.
+				name:      "invalid-opcode-nofunc",
 				title:     compile("invalid opcode"),
 				fmt:       "invalid opcode",
 				corrupted: true,
@@ -860,10 +3245,40 @@ var linuxOopses = []*oops{
 		},
 		[]*regexp.Regexp{},
 	},
+	&oops{
+		// Stack segment fault (#SS), usually a non-canonical address access via RSP/RBP.
+		[]byte("stack segment:"),
+		[]oopsFormat{
+			{
+				name:  "stack-segment-pc",
+				title: compile("stack segment: (?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
+				fmt:   "stack segment in %[1]v",
+			},
+			{
+				name:  "stack-segment",
+				title: compile("stack segment: (?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
+				fmt:   "stack segment in %[1]v",
+			},
+			{
+				name:  "stack-segment-stack",
+				title: compile("stack segment: (?:.*\\n)+?.*Call Trace:\\n(?:.*(?:\\[inline\\]|IRQ>|TASK>).*\\n)* (?:{{PC}} )?{{FUNC}}"),
+				fmt:   "stack segment in %[1]v",
+			},
+			{
+				// If we failed to extract function name where the fault happened, the report is most likely truncated.
+				name:      "stack-segment-nofunc",
+				title:     compile("stack segment"),
+				fmt:       "stack segment",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
 	&oops{
 		[]byte("unreferenced object"),
 		[]oopsFormat{
 			{
+				name:  "memory-leak",
 				title: compile("unreferenced object {{ADDR}} \\(size ([0-9]+)\\):(?:.*\n.*)+backtrace:.*\n.*{{PC}}.*\n.*{{PC}}.*\n.*{{PC}} {{FUNC}}"),
 				fmt:   "memory leak in %[2]v (size %[1]v)",
 			},
@@ -874,10 +3289,63 @@ var linuxOopses = []*oops{
 		[]byte("UBSAN:"),
 		[]oopsFormat{
 			{
+				name:  "ubsan",
 				title: compile("UBSAN: (.*)"),
 				fmt:   "UBSAN: %[1]v",
 			},
 		},
 		[]*regexp.Regexp{},
 	},
+	&oops{
+		// Generic x86 die() line (e.g. "stack segment: 0000 [#1] SMP KASAN") for faults
+		// that don't have own oopses above. Without this the title of such crashes would be
+		// taken from the subsequent "Kernel panic - not syncing: Fatal exception".
+		// Must be the last one, so that more specific oopses on the same line win.
+		[]byte(" [#1]"),
+		[]oopsFormat{
+			{
+				name:  "die-pc",
+				title: compile("([a-zA-Z][a-zA-Z ]*?): [0-9a-f]+ \\[#1\\](?:.*\\n)+?.*RIP: [0-9]+:{{PC}} +{{PC}} +{{FUNC}}"),
+				fmt:   "%[1]v in %[2]v",
+			},
+			{
+				name:  "die",
+				title: compile("([a-zA-Z][a-zA-Z ]*?): [0-9a-f]+ \\[#1\\](?:.*\\n)+?.*RIP: [0-9]+:{{FUNC}}"),
+				fmt:   "%[1]v in %[2]v",
+			},
+			{
+				name:      "die-nofunc",
+				title:     compile("([a-zA-Z][a-zA-Z: ]*?): [0-9a-f]+ \\[#1\\]"),
+				fmt:       "%[1]v",
+				corrupted: true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
+	// Stack dumps printed by tracing infrastructure (e.g. ftrace stacktrace trigger) without any oops.
+	// These are not kernel bugs, so they are used only if Options.StackTraceDumps is set
+	// (see linuxStackTraceFormats).
+	&oops{
+		[]byte("stack trace:"),
+		[]oopsFormat{
+			{
+				name: "stack-trace",
+				title: compile("stack trace:\\n(?:(?: *=> )?.*(?:stack|trace|dump).*\\n)*" +
+					"(?: *=> | +)(?:{{PC}} +)?{{FUNC}}"),
+				fmt:          "stack trace in %[1]v",
+				noStackTrace: true,
+			},
+			{
+				name:         "stack-trace-nofunc",
+				title:        compile("stack trace:"),
+				fmt:          "stack trace",
+				noStackTrace: true,
+				corrupted:    true,
+			},
+		},
+		[]*regexp.Regexp{},
+	},
 }
+
+// linuxStackTraceFormats are formats of "stack trace:" dumps, disabled unless Options.StackTraceDumps is set.
+var linuxStackTraceFormats = []string{"stack-trace", "stack-trace-nofunc"}
@@ -4,9 +4,17 @@
 package report
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
 )
@@ -151,6 +159,68 @@ unrelateed line
 `, `WARNING in dev_watchdog`, true,
 		}, {
 			`
+[  140.614801] WARNING: CPU: 1 PID: 7733 at mm/vmalloc.c:1473 __vunmap+0x1ca/0x300 mm/vmalloc.c:1472()
+[  140.614801] Trying to vfree() bad address (ffff8800b3254fc0)
+[  140.614801] Kernel panic - not syncing: panic_on_warn set ...
+`, `WARNING in __vunmap`, true,
+		}, {
+			`
+[  140.614801] WARNING: CPU: 1 PID: 23686 at net/core/dev.c:2444 skb_warn_bad_offload+0x2c0/0x3a0 net/core/dev.c:2439()
+[  140.614801] lo: caps=(0x00000014401b7c69, 0x0000000000000000) len=246 data_len=0 gso_size=35328 gso_type=4 ip_summed=0
+[  140.614801] Kernel panic - not syncing: panic_on_warn set ...
+`, `WARNING in skb_warn_bad_offload`, true,
+		}, {
+			`
+[  140.614801] WARNING: CPU: 2 PID: 24023 at kernel/locking/lockdep.c:3344 __lock_acquire+0x10e5/0x3690 kernel/locking/lockdep.c:3344
+[  140.614801] Kernel panic - not syncing: panic_on_warn set ...
+`, `WARNING in __lock_acquire`, true,
+		}, {
+			`
+[  149.188010] ==================================================================
+[  149.195304] BUG: KASAN: use-after-free in skb_release_data+0x4e5/0x570 net/core/skbuff.c:582
+[  149.203193] Read of size 8 at addr ffff8801d5b6c8a0 by task syz-executor3/5838
+[  149.210621] CPU: 1 PID: 5838 Comm: syz-executor3 Not tainted 4.15.0+ #1
+[  149.218000] Call Trace:
+[  149.218000]  __dump_stack lib/dump_stack.c:17 [inline]
+[  149.218000]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  149.218000]  skb_release_data+0x4e5/0x570 net/core/skbuff.c:582
+[  149.218000]  skb_release_all+0x4a/0x60 net/core/skbuff.c:645
+[  149.218000] ==================================================================
+[  149.218000] Kernel panic - not syncing: Fatal exception
+[  149.218000] Kernel Offset: disabled
+`, `KASAN: use-after-free Read in skb_release_data`, false,
+		}, {
+			`
+[  149.188010] stack segment: 0000 [#1] SMP KASAN
+[  149.195304] Modules linked in:
+[  149.210621] CPU: 1 PID: 5838 Comm: syz-executor3 Not tainted 4.15.0+ #1
+[  149.210621] RIP: 0010:skb_release_data+0x4e5/0x570 net/core/skbuff.c:582
+[  149.218000] Call Trace:
+[  149.218000]  skb_release_all+0x4a/0x60 net/core/skbuff.c:645
+[  149.218000]  kfree_skb+0x165/0x4c0 net/core/skbuff.c:700
+[  149.218000]  skb_queue_purge+0x1a/0x30 net/core/skbuff.c:2839
+[  149.218000] ---[ end trace 1234567890abcdef ]---
+[  149.218000] Kernel panic - not syncing: Fatal exception
+`, `stack segment in skb_release_data`, false,
+		}, {
+			`
+[  149.188010] Oops: 0000 [#1] SMP KASAN
+[  149.210621] CPU: 1 PID: 5838 Comm: syz-executor3 Not tainted 4.15.0+ #1
+[  149.210621] RIP: 0010:[<ffffffff8456b026>]  [<ffffffff8456b026>] skb_release_data+0x4e5/0x570
+[  149.218000] Call Trace:
+[  149.218000]  skb_release_all+0x4a/0x60 net/core/skbuff.c:645
+[  149.218000]  kfree_skb+0x165/0x4c0 net/core/skbuff.c:700
+[  149.218000]  skb_queue_purge+0x1a/0x30 net/core/skbuff.c:2839
+[  149.218000] ---[ end trace 1234567890abcdef ]---
+[  149.218000] Kernel panic - not syncing: Fatal exception
+`, `Oops in skb_release_data`, false,
+		}, {
+			`
+[  149.188010] double fault: 0000 [#1] SMP KASAN
+[  149.218000] Kernel panic - not syncing: Fatal exception
+`, `double fault`, true,
+		}, {
+			`
 [ 1722.511384] ------------[ cut here ]------------
 [ 1722.511384] WARNING: CPU: 3 PID: 1975 at fs/locks.c:241 locks_free_lock_context+0x118/0x180()
 `, `WARNING in locks_free_lock_context`, true,
@@ -401,6 +471,49 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `BUG: held lock freed in sk_clone_lock`, true,
 		}, {
 			`
+[  104.214587] =========================
+[  104.219017] WARNING: held lock freed!
+[  104.223118] 4.16.0-rc1+ #238 Not tainted
+[  104.227471] -------------------------
+[  104.231565] syz-executor0/4485 is freeing memory ffff8801d8b72a40-ffff8801d8b73b3f, with a lock still held there!
+[  104.242385]  (sk_lock-AF_INET6){+.+.}, at: [<00000000e1a6a1e3>] lock_sock include/net/sock.h:1463 [inline]
+[  104.242385]  (sk_lock-AF_INET6){+.+.}, at: [<00000000e1a6a1e3>] sctp_getsockopt+0x1e/0x2390 net/sctp/socket.c:7050
+[  104.253395] 1 lock held by syz-executor0/4485:
+[  104.258004]  #0:  (sk_lock-AF_INET6){+.+.}, at: [<00000000e1a6a1e3>] lock_sock include/net/sock.h:1463 [inline]
+[  104.258004]  #0:  (sk_lock-AF_INET6){+.+.}, at: [<00000000e1a6a1e3>] sctp_getsockopt+0x1e/0x2390 net/sctp/socket.c:7050
+[  104.269339] 
+[  104.269339] stack backtrace:
+[  104.273870] CPU: 1 PID: 4485 Comm: syz-executor0 Not tainted 4.16.0-rc1+ #238
+[  104.281142] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  104.290498] Call Trace:
+[  104.293087]  __dump_stack lib/dump_stack.c:17 [inline]
+[  104.298377]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  104.303747]  print_freed_lock_bug kernel/locking/lockdep.c:4396 [inline]
+[  104.310675]  debug_check_no_locks_freed+0x3c5/0x4c0 kernel/locking/lockdep.c:4429
+[  104.318237]  kfree+0xb8/0x250 mm/slab.c:3799
+[  104.322655]  sctp_getsockopt_local_addrs+0x75d/0xbd0 net/sctp/socket.c:5318
+[  104.329773]  sctp_getsockopt+0x1e76/0x2390 net/sctp/socket.c:7123
+`, `BUG: held lock freed in sctp_getsockopt`, false,
+		}, {
+			`
+[   79.486065] ================================================
+[   79.491930] WARNING: lock held when returning to user space!
+[   79.497788] 4.17.0-rc1+ #10 Not tainted
+[   79.501806] ------------------------------------------------
+[   79.507660] syz-executor0/4567 is leaving the kernel with locks still held!
+[   79.514856] 1 lock held by syz-executor0/4567:
+[   79.519485]  #0: 00000000b3f37a3d (&pipe->mutex/1){+.+.}, at: pipe_lock_nested fs/pipe.c:62 [inline]
+[   79.519485]  #0: 00000000b3f37a3d (&pipe->mutex/1){+.+.}, at: pipe_lock+0x56/0x70 fs/pipe.c:70
+[   79.531216] ------------[ cut here ]------------
+`, `WARNING: lock held when returning to user space in pipe_lock`, false,
+		}, {
+			`
+[   79.486065] ================================================
+[   79.491930] WARNING: lock held when returning to user space!
+[   79.497788] 4.17.0-rc1+ #10 Not tainted
+`, `WARNING: lock held when returning to user space`, true,
+		}, {
+			`
 [ 2569.618120] BUG: Bad rss-counter state mm:ffff88005fac4300 idx:0 val:15
 `, `BUG: Bad rss-counter state`, false,
 		}, {
@@ -564,6 +677,269 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `divide error in __tcp_select_window`, true,
 		}, {
 			`
+[  204.308477] divide error: 0000 [#1] SMP KASAN
+[  204.312991] Modules linked in:
+[  204.316172] CPU: 0 PID: 7823 Comm: syz-executor3 Not tainted 4.15.0-rc8+ #263
+[  204.323428] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  204.332773] RIP: 0010:0xffffffffa0001240
+[  204.336816] RSP: 0018:ffff8801c3a775d0 EFLAGS: 00010246
+[  204.342163] RAX: 0000000000000001 RBX: 0000000000000000 RCX: 0000000000000000
+[  204.349416] Call Trace:
+[  204.351986]  bpf_prog_run_pin_on_cpu include/linux/filter.h:590 [inline]
+[  204.358728]  sk_filter_trim_cap+0x2a4/0x6a0 net/core/filter.c:121
+[  204.365197]  sk_filter include/linux/filter.h:686 [inline]
+[  204.370712]  netlink_unicast+0x281/0x6f0 net/netlink/af_netlink.c:1329
+[  204.377444] Code:  Bad RIP value.
+[  204.380896] RIP: 0xffffffffa0001240 RSP: ffff8801c3a775d0
+[  204.386328] ---[ end trace 9d2310c3cdbd1a57 ]---
+`, `divide error in sk_filter_trim_cap`, false,
+		}, {
+			`
+[   71.215703] invalid opcode: 0000 [#1] SMP KASAN
+[   71.220363] Modules linked in:
+[   71.223541] CPU: 1 PID: 5497 Comm: syz-executor6 Not tainted 4.16.0-rc1+ #228
+[   71.230796] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   71.240142] RIP: 0010:0xffffc90001a4e017
+[   71.244186] RSP: 0018:ffff8801b8e474c8 EFLAGS: 00010246
+[   71.249531] RAX: 0000000000000000 RBX: ffffc90001a4e000 RCX: ffffffff8188b9f7
+[   71.256784] Call Trace:
+[   71.259353]  bpf_prog_run_save_cb include/linux/filter.h:556 [inline]
+[   71.265826]  run_filter+0x1e8/0x3b0 net/packet/af_packet.c:2050
+[   71.272038]  packet_rcv+0x34d/0x1340 net/packet/af_packet.c:2073
+[   71.278337]  __netif_receive_skb_core+0x1a41/0x3460 net/core/dev.c:4537
+[   71.285154] Code:  Bad RIP value.
+[   71.288605] RIP: 0xffffc90001a4e017 RSP: ffff8801b8e474c8
+[   71.294038] ---[ end trace 5d2e7b5d8c4a3b2e ]---
+`, `invalid opcode in run_filter`, false,
+		}, {
+			`
+[  149.188010] stack segment: 0000 [#1] SMP KASAN
+[  149.192680] Modules linked in:
+[  149.195860] CPU: 1 PID: 13236 Comm: syz-executor2 Not tainted 4.15.0-rc2+ #123
+[  149.203203] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  149.212546] RIP: 0010:0x4
+[  149.215024] RSP: 0018:ffff8801c1f2f518 EFLAGS: 00010282
+[  149.220370] Call Trace:
+[  149.222940]  __sk_destruct+0xfd/0x910 net/core/sock.c:1560
+[  149.228588]  sk_destruct+0x47/0x80 net/core/sock.c:1595
+[  149.233979]  __sk_free+0x57/0x230 net/core/sock.c:1603
+[  149.239240] Code:  Bad RIP value.
+[  149.242698] ---[ end trace 1b3e0a1c5d9f8e7a ]---
+`, `stack segment in __sk_destruct`, false,
+		}, {
+			`
+[   58.132412] BUG: using smp_processor_id() in preemptible [00000000] code: syz-executor0/4460
+[   58.141003] caller is debug_smp_processor_id+0x1c/0x30 lib/smp_processor_id.c:57
+[   58.148000] CPU: 1 PID: 4460 Comm: syz-executor0 Not tainted 4.15.0-rc8+ #264
+[   58.155257] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   58.164599] Call Trace:
+[   58.167177]  __dump_stack lib/dump_stack.c:17 [inline]
+[   58.172447]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[   58.177808]  check_preemption_disabled+0x1ad/0x1f0 lib/smp_processor_id.c:47
+[   58.184818]  debug_smp_processor_id+0x1c/0x30 lib/smp_processor_id.c:57
+[   58.191572]  __netif_set_xps_queue+0x2b2/0x1310 net/core/dev.c:2229
+[   58.198330]  netif_set_xps_queue+0x21/0x30 net/core/dev.c:2299
+[   58.204810]  virtnet_set_affinity+0x17b/0x310 drivers/net/virtio_net.c:1884
+`, `BUG: using smp_processor_id() in preemptible code in __netif_set_xps_queue`, false,
+		}, {
+			`
+[  123.140002] syz-executor0 invoked oom-killer: gfp_mask=0x14200ca(GFP_HIGHUSER_MOVABLE), nodemask=(null), order=0, oom_score_adj=1000
+[  123.151539] Mem-Info:
+[  123.154121] Out of memory: Killed process 4460 (syz-executor0) total-vm:48464kB, anon-rss:3088kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:112kB oom_score_adj:1000
+[  123.170001] oom_reaper: reaped process 4460 (syz-executor0), now anon-rss:0kB, file-rss:0kB, shmem-rss:0kB
+`, `out of memory: killed syz-executor`, false,
+		}, {
+			`
+[  123.154121] Out of memory: Kill process 4460 (syz-executor0) score 1001 or sacrifice child
+[  123.162001] Killed process 4460 (syz-executor0) total-vm:48464kB, anon-rss:3088kB, file-rss:0kB, shmem-rss:0kB
+`, `out of memory: killed syz-executor`, false,
+		}, {
+			`
+[  123.140002] syz-executor0 invoked oom-killer: gfp_mask=0x14200ca(GFP_HIGHUSER_MOVABLE), nodemask=(null), order=0, oom_score_adj=0
+[  123.151539] Kernel panic - not syncing: Out of memory and no killable processes...
+[  123.151539]
+[  123.160200] CPU: 1 PID: 4460 Comm: syz-executor0 Not tainted 4.15.0-rc8+ #264
+[  123.167457] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  123.176799] Call Trace:
+[  123.179377]  __dump_stack lib/dump_stack.c:17 [inline]
+[  123.184647]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  123.190008]  panic+0x1e4/0x41c kernel/panic.c:183
+[  123.194934]  out_of_memory+0xc6b/0x1410 mm/oom_kill.c:1080
+[  123.200634]  __alloc_pages_slowpath+0x1b2f/0x2d70 mm/page_alloc.c:3859
+[  123.207412]  __alloc_pages_nodemask+0x9fb/0xd80 mm/page_alloc.c:4271
+`, `out of memory`, false,
+		}, {
+			`
+[  512.060104] NMI watchdog: Watchdog detected hard LOCKUP on cpu 1
+[  512.066564] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 6.5.0-rc4+ #1
+[  512.072747] Call Trace:
+[  512.075311]  <IRQ>
+[  512.077451]  dump_stack_lvl+0xd9/0x150 lib/dump_stack.c:106
+[  512.083108]  watchdog_hardlockup_check+0x1a7/0x2e0 kernel/watchdog.c:168
+[  512.089845]  watchdog_buddy_check_hardlockup+0x8c/0xa0 kernel/watchdog_buddy.c:112
+[  512.097290]  watchdog_timer_fn+0x7b/0x640 kernel/watchdog.c:461
+[  512.103481]  </IRQ>
+[  512.105723] Sending NMI from CPU 0 to CPUs 1:
+[  512.110294] NMI backtrace for cpu 1
+[  512.114005] CPU: 1 PID: 5120 Comm: syz-executor.0 Not tainted 6.5.0-rc4+ #1
+[  512.121290] RIP: 0010:tipc_crypto_key_revoke+0x4c/0x130 net/tipc/crypto.c:1256
+[  512.128823] Call Trace:
+[  512.131405]  <NMI>
+[  512.133550]  </NMI>
+[  512.135798]  <TASK>
+[  512.138039]  tipc_crypto_key_revoke+0x4c/0x130 net/tipc/crypto.c:1256
+[  512.144712]  tipc_rcv+0x1b6/0x3b0 net/tipc/node.c:2093
+[  512.149957]  tipc_l2_rcv_msg+0x125/0x1f0 net/tipc/bearer.c:667
+[  512.156195]  </TASK>
+`, `BUG: hard lockup in tipc_crypto_key_revoke`, false,
+		}, {
+			`
+[  184.537818] BUG: unable to handle kernel NULL pointer dereference at 0000000000000010
+[  184.545950] IP: sock_release+0x2a/0xe0 net/socket.c:602
+[  184.551341] Oops: 0000 [#1] SMP KASAN
+[  184.555135] CPU: 0 PID: 8134 Comm: syz-executor5 Not tainted 4.15.0-rc9+ #212
+[  184.562481] RIP: 0010:sock_release+0x2a/0xe0 net/socket.c:602
+[  184.568370] Call Trace:
+[  184.570950]  sock_close+0x16/0x20 net/socket.c:1151
+[  184.576035]  __fput+0x327/0x7e0 fs/file_table.c:209
+[  184.581108]  ____fput+0x15/0x20 fs/file_table.c:243
+[  184.586163]  task_work_run+0x199/0x270 kernel/task_work.c:113
+[  184.592354]  do_exit+0x9bb/0x1ad0 kernel/exit.c:865
+[  184.597431] ---[ end trace 3f4b0a9e4f3b5c01 ]---
+[  184.602217] Fixing recursive fault but reboot is needed!
+`, `BUG: unable to handle kernel NULL pointer dereference in sock_release`, false,
+		}, {
+			`
+[  301.233916] NMI watchdog: Watchdog detected hard LOCKUP on cpu 0
+[  301.233936] Modules linked in:
+[  301.233951] CPU: 0 PID: 7989 Comm: syz-executor3 Not tainted 4.16.0-rc7+ #3
+[  301.233960] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  301.233971] RIP: 0010:snd_timer_interrupt+0x3a/0x7f0 sound/core/timer.c:750
+[  301.233977] RSP: 0018:ffff8801db007a98 EFLAGS: 00000046
+[  301.234036] Call Trace:
+[  301.234041]  <IRQ>
+[  301.234052]  snd_hrtimer_callback+0x1b6/0x3b0 sound/core/hrtimer.c:59
+[  301.234066]  __hrtimer_run_queues+0x3e3/0xe60 kernel/time/hrtimer.c:1349
+[  301.234078]  hrtimer_interrupt+0x1c2/0x5e0 kernel/time/hrtimer.c:1411
+`, `BUG: hard lockup in snd_timer_interrupt`, false,
+		}, {
+			`
+[  512.060104] watchdog: Watchdog detected hard LOCKUP on cpu 1
+[  512.066564] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 6.5.0-rc4+ #1
+[  512.072747] Call Trace:
+[  512.075311]  <IRQ>
+[  512.077451]  dump_stack_lvl+0xd9/0x150 lib/dump_stack.c:106
+[  512.083108]  watchdog_hardlockup_check+0x1a7/0x2e0 kernel/watchdog.c:168
+[  512.097290]  watchdog_timer_fn+0x7b/0x640 kernel/watchdog.c:461
+[  512.103481]  </IRQ>
+[  512.105723] Sending NMI from CPU 0 to CPUs 1:
+[  512.110294] NMI backtrace for cpu 1
+[  512.114005] CPU: 1 PID: 5120 Comm: syz-executor.0 Not tainted 6.5.0-rc4+ #1
+[  512.128823] Call Trace:
+[  512.131405]  <NMI>
+[  512.131405]  nmi_cpu_backtrace+0x2a6/0x350 lib/nmi_backtrace.c:113
+[  512.131405]  nmi_cpu_backtrace_handler+0xc/0x20 arch/x86/kernel/apic/hw_nmi.c:33
+[  512.133550]  </NMI>
+[  512.135798]  <TASK>
+[  512.138039]  fq_pie_timer+0x1b6/0x3b0 net/sched/sch_fq_pie.c:387
+[  512.144712]  call_timer_fn+0x1a0/0x580 kernel/time/timer.c:1700
+[  512.149957]  run_timer_softirq+0x125/0x1f0 kernel/time/timer.c:2022
+[  512.156195]  </TASK>
+`, `BUG: hard lockup in fq_pie_timer`, false,
+		}, {
+			`
+[  512.060104] NMI watchdog: Watchdog detected hard LOCKUP on cpu 1
+`, `BUG: hard lockup`, true,
+		}, {
+			`
+[  431.104180] BUG: MAX_LOCKDEP_ENTRIES too low!
+[  431.108822] turning off the locking correctness validator.
+[  431.114527] CPU: 0 PID: 18425 Comm: syz-executor3 Not tainted 4.15.0-rc8+ #264
+[  431.121969] Call Trace:
+[  431.124548]  __dump_stack lib/dump_stack.c:17 [inline]
+[  431.129819]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  431.135178]  add_lock_to_list.isra.30+0x1ad/0x1f0 kernel/locking/lockdep.c:1194
+[  431.142737]  check_prev_add+0x2a9/0x1200 kernel/locking/lockdep.c:1939
+`, `BUG: MAX_LOCKDEP_ENTRIES too low!`, false,
+		}, {
+			`
+[  212.745072] BUG: MAX_STACK_TRACE_ENTRIES too low!
+[  212.750050] turning off the locking correctness validator.
+`, `BUG: MAX_STACK_TRACE_ENTRIES too low!`, false,
+		}, {
+			`
+[  102.304400] BUG: MAX_LOCKDEP_CHAIN_HLOCKS too low!
+[  102.309496] turning off the locking correctness validator.
+`, `BUG: MAX_LOCKDEP_CHAIN_HLOCKS too low!`, false,
+		}, {
+			`
+[  302.547116] clocksource: timekeeping watchdog on CPU1: Marking clocksource 'tsc' as unstable because the skew is too large:
+[  302.558960] clocksource:                       'kvm-clock' wd_now: 4659b3a0b0 wd_last: 45f7bd8a01 mask: ffffffffffffffff
+[  302.570479] clocksource:                       'tsc' cs_now: 8da6c35af0 cs_last: 8cd3a3a97a mask: ffffffffffffffff
+[  302.581767] tsc: Marking TSC unstable due to clocksource watchdog
+`, `clocksource: tsc marked unstable by watchdog`, false,
+		}, {
+			`
+[   95.208283] Clocksource tsc unstable (delta = 4398046511104 ns)
+[   95.215551] Switched to clocksource kvm-clock
+`, `clocksource: tsc unstable`, false,
+		}, {
+			`
+[  302.547116] clocksource: timekeeping watchdog on CPU1: Marking clocksource 'tsc' as unstable because the skew is too large:
+[  302.558960] clocksource:                       'kvm-clock' wd_now: 4659b3a0b0 wd_last: 45f7bd8a01 mask: ffffffffffffffff
+[  302.570479] clocksource:                       'tsc' cs_now: 8da6c35af0 cs_last: 8cd3a3a97a mask: ffffffffffffffff
+[  302.581767] tsc: Marking TSC unstable due to clocksource watchdog
+[  342.262162] ------------[ cut here ]------------
+[  342.266927] WARNING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:123 foo_bar+0xe4/0x110
+[  342.274727] Modules linked in:
+[  342.306981] Call Trace:
+[  342.309554]  foo_bar+0xe4/0x110 net/ipv4/tcp.c:123
+[  342.314898]  foo_ioctl+0x1bf/0x39f net/ipv4/tcp.c:200
+`, `WARNING in foo_bar`, false,
+		}, {
+			`
+[  231.402871] BUG: Dentry 00000000ecd2a1b5{i=0,n=/}  still in use (1) [unmount of tmpfs tmpfs]
+[  231.411419] ------------[ cut here ]------------
+[  231.416174] WARNING: CPU: 0 PID: 5301 at fs/dcache.c:1667 umount_check fs/dcache.c:1658 [inline]
+[  231.416174] WARNING: CPU: 0 PID: 5301 at fs/dcache.c:1667 umount_check+0x18d/0x240 fs/dcache.c:1647
+[  231.425647] Modules linked in:
+[  231.428837] CPU: 0 PID: 5301 Comm: syz-executor.1 Not tainted 6.3.0-rc1-syzkaller #0
+[  231.436731] RIP: 0010:umount_check fs/dcache.c:1658 [inline]
+[  231.436731] RIP: 0010:umount_check+0x18d/0x240 fs/dcache.c:1647
+[  231.443186] Call Trace:
+[  231.445771]  <TASK>
+[  231.448023]  d_walk+0x1d8/0x810 fs/dcache.c:1445
+[  231.452884]  do_one_tree fs/dcache.c:1681 [inline]
+[  231.457822]  shrink_dcache_for_umount+0x92/0x340 fs/dcache.c:1697
+[  231.464262]  generic_shutdown_super+0x6b/0x390 fs/super.c:474
+[  231.470445]  kill_litter_super+0x70/0xa0 fs/super.c:1174
+[  231.476107]  deactivate_locked_super+0xa4/0x110 fs/super.c:331
+[  231.482300]  cleanup_mnt+0x426/0x4c0 fs/namespace.c:1177
+[  231.487961]  task_work_run+0x224/0x320 kernel/task_work.c:179
+[  231.494061]  </TASK>
+`, `BUG: Dentry still in use [unmount of tmpfs]`, false,
+		}, {
+			`
+[  512.511384] VFS: Busy inodes after unmount of loop3 (vfat)
+[  512.517001] ------------[ cut here ]------------
+[  512.521760] kernel BUG at fs/super.c:650!
+`, `VFS: Busy inodes after unmount of vfat`, false,
+		}, {
+			`
+[  512.511384] VFS: Busy inodes after unmount of sda. Self-destruct in 5 seconds.  Have a nice day...
+`, `VFS: Busy inodes after unmount of DEV`, false,
+		}, {
+			`
+[  601.113532] VFS: Busy inodes after unmount of loop1. Self-destruct in 5 seconds.  Have a nice day...
+`, `VFS: Busy inodes after unmount of DEV`, false,
+		}, {
+			`
+[ 1722.511384] unreferenced object 0xffff880039a55260 (size 64):
+[ 1722.511384]   comm "syz-executor3", pid 11746, jiffies 4298984475 (age 16.078s)
+[ 1722.511384]   hex dump (first 32 bytes):
+`, `unreferenced object ADDR (size 64):`, true,
+		}, {
+			`
 [ 1722.511384] unreferenced object 0xffff880039a55260 (size 64): 
 [ 1722.511384]   comm "executor", pid 11746, jiffies 4298984475 (age 16.078s) 
 [ 1722.511384]   hex dump (first 32 bytes): 
@@ -708,6 +1084,71 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `INFO: rcu detected stall`, true,
 		}, {
 			`
+[  277.780013] rcu: INFO: rcu_preempt detected stalls on CPUs/tasks:
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
+[  277.780013] rcu: INFO: rcu_sched self-detected stall on CPU
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
+[  277.780013] rcu: INFO: rcu_preempt detected expedited stalls on CPUs/tasks: { 1-... } 10502 jiffies s: 2469 root: 0x2/.
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
+[  277.780013] INFO: rcu_sched_state detected stall on CPU 1 (t=65000 jiffies)
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
+[  277.780013] INFO: rcu_bh self-detected stall on CPU
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
 [   72.159680] BUG: spinlock lockup suspected on CPU#2, syz-executor/12636
 `, `BUG: spinlock lockup suspected`, true,
 		}, {
@@ -742,7 +1183,7 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 [  213.618060]  [<ffffffff814b7615>] ? __task_rq_lock+0xf5/0x330
 [  213.618060]  [<ffffffff814b7615>] __task_rq_lock+0xf5/0x330
 [  213.618060]  [<ffffffff814c89b2>] wake_up_new_task+0x592/0x1000
-`, `BUG: spinlock recursion`, false,
+`, `BUG: spinlock recursion in __task_rq_lock`, false,
 		}, {
 			`
 [  843.240752] INFO: task getty:2986 blocked for more than 120 seconds.
@@ -873,6 +1314,47 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `INFO: task hung in set_current_rng`, false,
 		}, {
 			`
+[  863.200911] INFO: task syz-executor0:5676 blocked for more than 120 seconds.
+[  863.203658]       Not tainted 4.14.0-rc8-44455-ge2105594a876 #110
+[  863.205780] "echo 0 > /proc/sys/kernel/hung_task_timeout_secs" disables this message.
+[  863.208544] syz-executor0   D27584  5676      1 0x00000004
+[  863.210626] Call Trace:
+[  863.211704]  __schedule+0x57e/0x1940
+[  863.213000]  schedule+0x84/0x1c0
+[  863.214031]  schedule_timeout+0xa8b/0xe80
+[  863.219875]  wait_for_completion+0x192/0x340
+[  863.222517]  kthread_stop+0x105/0x650
+[  863.223783]  set_current_rng+0x2b2/0x3b0
+[  863.225073]  hwrng_unregister+0x1db/0x230
+[  863.250857]  entry_SYSCALL_64_fastpath+0x23/0xc2
+[  863.260911] INFO: task kworker/0:1:764 blocked for more than 120 seconds.
+[  863.263658]       Not tainted 4.14.0-rc8-44455-ge2105594a876 #110
+[  863.265780] "echo 0 > /proc/sys/kernel/hung_task_timeout_secs" disables this message.
+[  863.268544] kworker/0:1     D27296   764      2 0x00000000
+[  863.270626] Call Trace:
+[  863.271704]  __schedule+0x57e/0x1940
+[  863.273000]  schedule+0x92/0x1b0
+[  863.274031]  schedule_preempt_disabled+0x13/0x20
+[  863.275000]  __mutex_lock+0x2ff/0x830
+[  863.276000]  mutex_lock_nested+0x16/0x20
+[  863.277000]  rtnl_lock+0x17/0x20
+[  863.278000]  ieee80211_unregister_hw+0x44/0x270
+[  863.279000]  ret_from_fork+0x2a/0x40
+[  863.280911] INFO: task syz-executor1:5677 blocked for more than 120 seconds.
+[  863.283658]       Not tainted 4.14.0-rc8-44455-ge2105594a876 #110
+[  863.285780] "echo 0 > /proc/sys/kernel/hung_task_timeout_secs" disables this message.
+[  863.288544] syz-executor1   D27584  5677      1 0x00000004
+[  863.290626] Call Trace:
+[  863.291704]  __schedule+0x57e/0x1940
+[  863.293000]  schedule+0x84/0x1c0
+[  863.294031]  schedule_preempt_disabled+0x13/0x20
+[  863.295000]  __mutex_lock+0x2ff/0x830
+[  863.296000]  mutex_lock_nested+0x16/0x20
+[  863.297000]  hwrng_register+0x2b2/0x3b0
+[  863.299000]  entry_SYSCALL_64_fastpath+0x23/0xc2
+`, `INFO: task hung in ieee80211_unregister_hw`, false,
+		}, {
+			`
 [  185.479466] BUG: scheduling while atomic: syz-executor0/19425/0x00000000
 [  185.486365] INFO: lockdep is turned off.
 [  185.490423] Modules linked in:
@@ -923,7 +1405,7 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 		}, {
 			`
 [  374.860710] BUG: KASAN: use-after-free in do_con_write.part.23+0x1c50/0x1cb0 at addr ffff88000012c43a
-`, `KASAN: use-after-free in do_con_write.part.23 at addr ADDR`, true,
+`, `KASAN: use-after-free in do_con_write at addr ADDR`, true,
 		}, {
 			`
 [  163.314570] WARNING: kernel stack regs at ffff8801d100fea8 in syz-executor1:16059 has bad 'bp' value ffff8801d100ff28
@@ -974,7 +1456,7 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 [ 1722.511384] ------------[ cut here ]------------
 [ 1722.511384] WARNING: CPU: 1 PID: 8922 at fs/dcache.c:1445 umount_check+0x246/0x2c0 fs/dcache.c:1436
 [ 1722.511384] Kernel panic - not syncing: panic_on_warn set ...
-`, `BUG: Dentry still in use [unmount of proc proc]`, true,
+`, `BUG: Dentry still in use [unmount of proc]`, true,
 		}, {
 			`
 [   72.159680] WARNING: kernel stack frame pointer at ffff88003e1f7f40 in migration/1:14 has bad value ffffffff85632fb0
@@ -993,6 +1475,41 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `BUG: Bad page state`, true,
 		}, {
 			`
+[  212.579138] BUG: Bad page state in process syz-executor.2  pfn:2b0ec
+[  212.585664] page:ffffea0000ac3b00 refcount:0 mapcount:0 mapping:0000000000000000 index:0x0 pfn:0x2b0ec
+[  212.595080] flags: 0xfff00000000200(slab|node=0|zone=1|lastcpupid=0x7ff)
+[  212.601770] raw: 00fff00000000200 dead000000000100 dead000000000122 0000000000000000
+[  212.609644] page dumped because: PAGE_FLAGS_CHECK_AT_FREE flag(s) set
+[  212.616223] page_owner tracks the page as allocated
+[  212.621231] page last allocated via order 0, migratetype Unmovable, gfp_mask 0x820c0(__GFP_ZERO|__GFP_NOMEMALLOC), pid 5093, tgid 5092 (syz-executor.2), ts 212231384926, free_ts 0
+[  212.637834]  post_alloc_hook+0x2d2/0x350 mm/page_alloc.c:1533
+[  212.643727]  prep_new_page mm/page_alloc.c:1540 [inline]
+[  212.643727]  get_page_from_freelist+0x10a6/0x31e0 mm/page_alloc.c:3311
+[  212.650629]  __alloc_pages+0x1d0/0x4a0 mm/page_alloc.c:4567
+[  212.656588]  alloc_pages_mpol+0x258/0x5f0 mm/mempolicy.c:2133
+[  212.662559]  skb_page_frag_refill+0x23c/0x3e0 net/core/sock.c:2878
+[  212.669276]  tun_build_skb drivers/net/tun.c:1610 [inline]
+[  212.669276]  tun_get_user+0x6f1/0x3a50 drivers/net/tun.c:1797
+[  212.675323]  tun_chr_write_iter+0xdc/0x200 drivers/net/tun.c:2039
+[  212.682031] page last free stack trace:
+[  212.686326]  free_unref_page_prepare mm/page_alloc.c:2346 [inline]
+[  212.686326]  free_unref_page+0x33/0x3b0 mm/page_alloc.c:2486
+[  212.692575]  vfs_write+0x650/0xd80 fs/read_write.c:586
+[  212.698178] Modules linked in:
+[  212.701475] CPU: 1 PID: 5093 Comm: syz-executor.2 Not tainted 6.6.0-rc5-syzkaller #0
+[  212.709324] Call Trace:
+[  212.711878]  <TASK>
+[  212.714119]  __dump_stack lib/dump_stack.c:88 [inline]
+[  212.714119]  dump_stack_lvl+0xd9/0x1b0 lib/dump_stack.c:106
+[  212.719797]  bad_page+0xd6/0x150 mm/page_alloc.c:494
+[  212.724955]  free_unref_page+0x33/0x3b0 mm/page_alloc.c:2486
+[  212.731103]  __put_page+0x4e/0x60 mm/swap.c:127
+[  212.736209]  skb_release_data+0x5a2/0x850 net/core/skbuff.c:1006
+[  212.742545]  tcp_recvmsg+0x1a6/0x5a0 net/ipv4/tcp.c:2571
+[  212.748062]  </TASK>
+`, `BUG: Bad page state`, false,
+		}, {
+			`
 [ 1722.511384] Kernel panic - not syncing: Couldn't open N_TTY ldisc for ptm1 --- error -12.
 [ 1722.511384] CPU: 1 PID: 14836 Comm: syz-executor5 Not tainted 4.12.0-rc4+ #15
 [ 1722.511384] Hardware name: QEMU Standard PC (i440FX + PIIX, 1996), BIOS Bochs 01/01/2011
@@ -1058,7 +1575,7 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 [  108.804399]  [<ffffffff81338300>] ? kthread_create_on_node+0x460/0x460
 [  108.811031]  [<ffffffff82d2fbac>] ret_from_fork+0x5c/0x90
 [  108.816532]  [<ffffffff81338300>] ? kthread_create_on_node+0x460/0x460
- `, `BUG: spinlock already unlocked`, false,
+ `, `BUG: spinlock already unlocked in __wake_up`, false,
 		}, {
 			`
 [  128.792466] R10: 00000000000f4244 R11: 0000000000000217 R12: 00000000004bbb5d
@@ -1135,7 +1652,80 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 [  153.634416] PGD a0ab067 PUD 21ffff067 PMD 80000000b3c001e3 
 [  153.640483] Oops: 0011 [#1] SMP KASAN
 [  153.644615] Modules linked in:
-`, `BUG: unable to handle kernel`, true,
+`, `BUG: kernel tried to execute NX-protected page`, true,
+		}, {
+			`
+[  151.724580] kernel tried to execute NX-protected page - exploit attempt? (uid: 0)
+[  151.732138] BUG: unable to handle kernel paging request at ffff8801c6947b80
+[  151.739343] IP: 0xffff8801c6947b80
+[  151.742850] PGD a86f067 P4D a86f067 PUD 21ffff067 PMD 80000001c68001e3
+[  151.749528] Oops: 0011 [#1] SMP KASAN
+[  151.753320] Modules linked in:
+[  151.756499] CPU: 1 PID: 11729 Comm: syz-executor7 Not tainted 4.15.0-rc3+ #136
+[  151.763829] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  151.773158] task: ffff8801c5c1a340 task.stack: ffff8801c5de0000
+[  151.779200] RIP: 0010:0xffff8801c6947b80
+[  151.783240] RSP: 0018:ffff8801db307cb8 EFLAGS: 00010246
+[  151.788583] RAX: 0000000000000000 RBX: ffff8801c6947b80 RCX: ffffffff8456b2a1
+[  151.795836] CR2: ffff8801c6947b80 CR3: 00000001c5e2d000 CR4: 00000000001406e0
+[  151.803089] Call Trace:
+[  151.805658]  <IRQ>
+[  151.807798]  ? dst_destroy+0x9c/0x310
+[  151.811588]  dst_destroy_rcu+0x16/0x30 net/core/dst.c:143
+[  151.817193]  rcu_process_callbacks+0xd6c/0x17f0 kernel/rcu/tree.c:2922
+[  151.823660]  __do_softirq+0x2d7/0xb85 kernel/softirq.c:285
+[  151.829264]  </IRQ>
+[  151.831520] Code:  Bad RIP value.
+[  151.834966] RIP: 0xffff8801c6947b80 RSP: ffff8801db307cb8
+[  151.840400] CR2: ffff8801c6947b80
+[  151.843833] ---[ end trace 2ffc3a4c3bd7a96b ]---
+`, `BUG: kernel tried to execute NX-protected page in dst_destroy_rcu`, false,
+		}, {
+			`
+[   42.041479] unable to execute userspace code (SMEP?) (uid: 0)
+[   42.047289] BUG: unable to handle kernel paging request at 0000000020000000
+[   42.054318] IP: 0x20000000
+[   42.057108] PGD 1c5b7a067 P4D 1c5b7a067 PUD 1c5b7b067 PMD 1c5b7c067 PTE 1b0c1d867
+[   42.064741] Oops: 0011 [#1] SMP KASAN
+[   42.068522] Modules linked in:
+[   42.071701] CPU: 0 PID: 4211 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #170
+[   42.088663] RIP: 0010:0x20000000
+[   42.091998] RSP: 0018:ffff8801c5e47c38 EFLAGS: 00010286
+[   42.097340] RAX: 0000000020000000 RBX: ffff8801d8bb3680 RCX: ffffffff84359b5c
+[   42.104590] CR2: 0000000020000000 CR3: 00000001c5b79000 CR4: 00000000001406f0
+[   42.111841] Call Trace:
+[   42.114410]  sock_ioctl+0x2f6/0x3c0 net/socket.c:1008
+[   42.119667]  vfs_ioctl fs/ioctl.c:46 [inline]
+[   42.124138]  do_vfs_ioctl+0x1b1/0x1520 fs/ioctl.c:686
+[   42.129312]  SyS_ioctl+0x8f/0xc0 fs/ioctl.c:701
+[   42.133965]  entry_SYSCALL_64_fastpath+0x1f/0x96
+[   42.138696] Code:  Bad RIP value.
+[   42.142152] RIP: 0x20000000 RSP: ffff8801c5e47c38
+[   42.147064] CR2: 0000000020000000
+[   42.150501] ---[ end trace 4a9ad2d3e3c5b1f0 ]---
+`, `BUG: unable to execute userspace code in sock_ioctl`, false,
+		}, {
+			`
+[  100.612089] BUG: unable to handle page fault for address: ffffffffa0000000
+[  100.619208] #PF: supervisor instruction fetch in kernel mode
+[  100.625004] #PF: error_code(0x0010) - not-present page
+[  100.630281] PGD 9a6f067 P4D 9a6f067 PUD 9a70063 PMD 0
+[  100.635561] Oops: 0010 [#1] PREEMPT SMP KASAN
+[  100.640058] CPU: 0 PID: 8472 Comm: syz-executor.0 Not tainted 5.4.0-rc3+ #0
+[  100.647157] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  100.656513] RIP: 0010:0xffffffffa0000000
+[  100.660572] Code: Bad RIP value.
+[  100.663933] RSP: 0018:ffff88809f307cb8 EFLAGS: 00010246
+[  100.669298] RAX: 0000000000000000 RBX: ffff8880a1b2c300 RCX: ffffffff85f2a1b6
+[  100.676559] CR2: ffffffffa0000000 CR3: 0000000098f27000 CR4: 00000000001406f0
+[  100.683820] Call Trace:
+[  100.686408]  __sock_release+0xcd/0x280 net/socket.c:590
+[  100.691818]  sock_close+0x1b/0x30 net/socket.c:1268
+[  100.696881]  __fput+0x2ff/0x890 fs/file_table.c:280
+[  100.701868]  task_work_run+0x145/0x1c0 kernel/task_work.c:113
+[  100.707714] Modules linked in:
+[  100.710922] ---[ end trace 7b1f1e0d3c2a4b5e ]---
+`, `BUG: bad RIP value in __sock_release`, false,
 		}, {
 			`
 [   46.415093] syz2: link speed 10 Mbps
@@ -1264,6 +1854,25 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `KASAN: slab-out-of-bounds in do_raw_write_lock at addr ADDR`, true,
 		}, {
 			`
+[   75.263016] ==================================================================
+** replaying previous printk message ** [   75.270413] BUG: KASAN: use-after-free in __list_del_entry_valid+0x12b/0x150 lib/list_debug.c:54
+** replaying previous printk message ** [   75.278577] Read of size 8 at addr ffff88809ad85f08 by task syz-executor.1/9152
+** replaying previous printk message ** [   75.286190] CPU: 1 PID: 9152 Comm: syz-executor.1 Not tainted 6.1.0-rc5-syzkaller #0
+** replaying previous printk message ** [   75.294507] Call Trace:
+** replaying previous printk message ** [   75.297099]  <TASK>
+** replaying previous printk message ** [   75.299366]  __dump_stack lib/dump_stack.c:88 [inline]
+** replaying previous printk message ** [   75.304547]  dump_stack_lvl+0x1b1/0x28e lib/dump_stack.c:106
+** replaying previous printk message ** [   75.310560]  print_address_description+0x74/0x340 mm/kasan/report.c:284
+** replaying previous printk message ** [   75.317733]  print_report+0x107/0x1f0 mm/kasan/report.c:395
+** replaying previous printk message ** [   75.323753]  kasan_report+0xcd/0x100 mm/kasan/report.c:495
+** replaying previous printk message ** [   75.329671]  __list_del_entry_valid+0x12b/0x150 lib/list_debug.c:54
+[   75.336548]  __list_del_entry include/linux/list.h:134 [inline]
+[   75.342548]  list_del include/linux/list.h:148 [inline]
+[   75.347910]  tipc_sk_remove_from_list+0x2a/0x190 net/tipc/socket.c:1120
+[   75.354839]  </TASK>
+`, `KASAN: use-after-free Read in __list_del_entry_valid`, false,
+		}, {
+			`
 [  208.131930] ==================================================================
 [  208.139343] BUG: KMSAN: use of uninitialized memory in packet_set_ring+0x11b8/0x2ff0
 [  208.147224] CPU: 0 PID: 12442 Comm: syz-executor0 Tainted: G    B           4.13.0+ #12
@@ -1659,6 +2268,75 @@ r0 = ioctl$KVM_CREATE_VM(0xffffffffffffffff, 0xae01, 0x0)
 `, `BUG: bad unlock balance in ipmr_mfc_seq_stop`, false,
 		}, {
 			`
+[   37.914160] =====================================
+[   37.919021] WARNING: bad unlock balance detected!
+[   37.923860] 4.15.0-rc3+ #219 Not tainted
+[   37.927913] -------------------------------------
+[   37.932748] syz-executor2/3569 is trying to release lock (&mm->mmap_sem) at:
+[   37.939881] [<ffffffff8144fa5a>] up_read+0x1a/0x40 kernel/locking/rwsem.c:125
+[   37.946139] but there are no more locks to release!
+[   37.951149] 
+[   37.951149] other info that might help us debug this:
+[   37.957812] no locks held by syz-executor2/3569.
+[   37.962557] 
+[   37.962557] stack backtrace:
+[   37.967050] CPU: 1 PID: 3569 Comm: syz-executor2 Not tainted 4.15.0-rc3+ #219
+[   37.974327] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   37.983685] Call Trace:
+[   37.986269]  __dump_stack lib/dump_stack.c:17 [inline]
+[   37.986269]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[   37.991897]  print_unlock_imbalance_bug+0x12f/0x140 kernel/locking/lockdep.c:3556
+[   37.999273]  __lock_release kernel/locking/lockdep.c:3602 [inline]
+[   37.999273]  lock_release+0x5f9/0xda0 kernel/locking/lockdep.c:3911
+[   38.005602]  up_read+0x1a/0x40 kernel/locking/rwsem.c:125
+[   38.011010]  userfaultfd_event_wait_completion+0x466/0x810 fs/userfaultfd.c:609
+[   38.018644]  userfaultfd_event_complete fs/userfaultfd.c:666 [inline]
+[   38.018644]  mremap_userfaultfd_complete+0x1e7/0x280 fs/userfaultfd.c:747
+`, `BUG: bad unlock balance in up_read`, false,
+		}, {
+			`
+[  118.227047] =====================================
+[  118.231851] WARNING: bad unlock balance detected!
+[  118.236661] 5.10.0-rc4-syzkaller #0 Not tainted
+[  118.241309] -------------------------------------
+[  118.246116] syz-executor.4/10873 is trying to release lock (&ctx->uring_lock) at:
+[  118.253726]  io_ring_exit_work+0x5ba/0x790 fs/io_uring.c:8581
+[  118.259606] but there are no more locks to release!
+[  118.264588] 
+[  118.264588] stack backtrace:
+[  118.269072] CPU: 0 PID: 10873 Comm: syz-executor.4 Not tainted 5.10.0-rc4-syzkaller #0
+[  118.288270] Call Trace:
+[  118.290849]  __dump_stack lib/dump_stack.c:77 [inline]
+[  118.290849]  dump_stack+0x107/0x163 lib/dump_stack.c:118
+[  118.296467]  print_unlock_imbalance_bug kernel/locking/lockdep.c:4946 [inline]
+[  118.296467]  print_unlock_imbalance_bug.cold+0x114/0x123 kernel/locking/lockdep.c:4927
+[  118.304704]  __lock_release kernel/locking/lockdep.c:5126 [inline]
+[  118.304704]  lock_release+0x5ac/0x6f0 kernel/locking/lockdep.c:5462
+[  118.310957]  __mutex_unlock_slowpath+0x84/0x610 kernel/locking/mutex.c:1228
+[  118.317750]  io_ring_exit_work+0x5ba/0x790 fs/io_uring.c:8581
+[  118.323654]  process_one_work+0x933/0x15a0 kernel/workqueue.c:2272
+`, `BUG: bad unlock balance in io_ring_exit_work`, false,
+		}, {
+			`
+[   52.114127] INFO: bad unlock balance detected!
+[   52.118882] -------------------------------------
+[   52.123712] syz-executor0/7158 is trying to release lock (rtnl_mutex) at:
+[   52.130678] [<ffffffff85b1ac3f>] rtnl_unlock+0x9/0x10 net/core/rtnetlink.c:79
+[   52.136959] but there are no more locks to release!
+[   52.141962] 
+[   52.141962] stack backtrace:
+[   52.146489] Call Trace:
+[   52.149059]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[   52.154421]  rtnl_unlock+0x9/0x10 net/core/rtnetlink.c:79
+[   52.160099]  tun_chr_close+0x3f/0x60 drivers/net/tun.c:3237
+`, `BUG: bad unlock balance in rtnl_unlock`, false,
+		}, {
+			`
+[   37.919021] WARNING: bad unlock balance detected!
+[   37.923860] 4.15.0-rc3+ #219 Not tainted
+`, `BUG: bad unlock balance`, true,
+		}, {
+			`
 syzkaller login: [   16.305150] INFO: trying to register non-static key.
 [   16.305671] the code is fine but needs lockdep annotation.
 [   16.306408] turning off the locking correctness validator.
@@ -1705,181 +2383,1004 @@ syzkaller login: [   16.305150] INFO: trying to register non-static key.
 [   47.713035]  _raw_spin_lock_bh+0x31/0x40
 [   47.713045]  tun_flow_cleanup+0xf4/0x300
 `, `INFO: trying to register non-static key in tun_flow_cleanup`, false,
-		},
-	}
-	testParse(t, "linux", tests)
-}
-
-func TestLinuxIgnores(t *testing.T) {
-	reporter, err := NewReporter("linux", "", "", nil, nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	ignores1 := []*regexp.Regexp{
-		regexp.MustCompile("BUG: bug3"),
-	}
-	reporter1, err := NewReporter("linux", "", "", nil, ignores1)
-	if err != nil {
-		t.Fatal(err)
-	}
-	ignores2 := []*regexp.Regexp{
-		regexp.MustCompile("BUG: bug3"),
-		regexp.MustCompile("BUG: bug1"),
-	}
-	reporter2, err := NewReporter("linux", "", "", nil, ignores2)
-	if err != nil {
-		t.Fatal(err)
-	}
-	ignores3 := []*regexp.Regexp{
-		regexp.MustCompile("BUG: bug3"),
-		regexp.MustCompile("BUG: bug1"),
-		regexp.MustCompile("BUG: bug2"),
-	}
-	reporter3, err := NewReporter("linux", "", "", nil, ignores3)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	const log = `
-[    0.000000] BUG: bug1
-[    0.000000] BUG: bug2
-	`
-	if !reporter.ContainsCrash([]byte(log)) {
-		t.Fatalf("no crash")
-	}
-	if rep := reporter.Parse([]byte(log)); rep.Title != "BUG: bug1" {
-		t.Fatalf("want `BUG: bug1`, found `%v`", rep.Title)
-	}
-
-	if !reporter1.ContainsCrash([]byte(log)) {
-		t.Fatalf("no crash")
-	}
-	if rep := reporter1.Parse([]byte(log)); rep.Title != "BUG: bug1" {
-		t.Fatalf("want `BUG: bug1`, found `%v`", rep.Title)
-	}
-
-	if !reporter2.ContainsCrash([]byte(log)) {
-		t.Fatalf("no crash")
-	}
-	if rep := reporter2.Parse([]byte(log)); rep.Title != "BUG: bug2" {
-		t.Fatalf("want `BUG: bug2`, found `%v`", rep.Title)
-	}
-
-	if reporter3.ContainsCrash([]byte(log)) {
-		t.Fatalf("found crash, should be ignored")
-	}
-	if rep := reporter3.Parse([]byte(log)); rep != nil {
-		t.Fatalf("found `%v`, should be ignored", rep.Title)
-	}
-}
+		}, {
+			`
+[  129.374986] =============================
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375017] 4.15.0-rc2+ #208 Not tainted
+[  129.375030] -----------------------------
+[  129.375045] ./include/linux/rcupdate.h:302 Illegal context switch in RCU read-side critical section!
+[  129.375057] 
+[  129.375057] other info that might help us debug this:
+[  129.375057] 
+[  129.375071] 
+[  129.375071] rcu_scheduler_active = 2, debug_locks = 1
+[  129.375086] 2 locks held by syz-executor1/10463:
+[  129.375098]  #0:  (sk_lock-AF_INET6){+.+.}, at: [<00000000b27fa8af>] lock_sock include/net/sock.h:1463 [inline]
+[  129.375098]  #0:  (sk_lock-AF_INET6){+.+.}, at: [<00000000b27fa8af>] do_ipv6_setsockopt.isra.9+0x23d/0x3870 net/ipv6/ipv6_sockglue.c:167
+[  129.375196] 
+[  129.375196] stack backtrace:
+[  129.375215] CPU: 1 PID: 10463 Comm: syz-executor1 Not tainted 4.15.0-rc2+ #208
+[  129.375226] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  129.375234] Call Trace:
+[  129.375257]  __dump_stack lib/dump_stack.c:17 [inline]
+[  129.375257]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  129.375285]  lockdep_rcu_suspicious+0x123/0x170 kernel/locking/lockdep.c:4585
+[  129.375313]  rcu_preempt_sleep_check include/linux/rcupdate.h:301 [inline]
+[  129.375313]  ___might_sleep+0x385/0x470 kernel/sched/core.c:6079
+[  129.375342]  __might_sleep+0x95/0x190 kernel/sched/core.c:6067
+[  129.375372]  __mutex_lock+0x12e/0x1730 kernel/locking/mutex.c:895
+[  129.375431]  mutex_lock_nested+0x16/0x20 kernel/locking/mutex.c:1023
+[  129.375453]  ipmr_mfc_add+0x49/0x1470 net/ipv6/ip6mr.c:1324
+`, `suspicious RCU usage at ./include/linux/rcupdate.h:LINE`, false,
+		}, {
+			`
+[  211.303107] =============================
+[  211.303113] WARNING: suspicious RCU usage
+[  211.303120] 4.19.0-rc5+ #41 Not tainted
+[  211.303126] -----------------------------
+[  211.303133] ./include/linux/rcupdate.h:631 rcu_read_lock() used illegally while idle!
+[  211.303139] 
+[  211.303139] other info that might help us debug this:
+[  211.303139] 
+[  211.303146] 
+[  211.303146] RCU used illegally from idle CPU!
+[  211.303146] rcu_scheduler_active = 2, debug_locks = 1
+[  211.303153] RCU used illegally from extended quiescent state!
+[  211.303160] 1 lock held by swapper/0/0:
+[  211.303167]  #0: 00000000b97f5a79 (rcu_read_lock){....}, at: trace_call_bpf+0xf6/0x620 kernel/trace/bpf_trace.c:60
+[  211.303190] 
+[  211.303190] stack backtrace:
+[  211.303198] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 4.19.0-rc5+ #41
+[  211.303213] Call Trace:
+[  211.303226]  __dump_stack lib/dump_stack.c:77 [inline]
+[  211.303226]  dump_stack+0x1c4/0x2b4 lib/dump_stack.c:113
+[  211.303243]  lockdep_rcu_suspicious+0x14a/0x153 kernel/locking/lockdep.c:4562
+[  211.303259]  rcu_read_lock include/linux/rcupdate.h:630 [inline]
+[  211.303259]  trace_call_bpf+0x5b1/0x620 kernel/trace/bpf_trace.c:60
+[  211.303275]  perf_trace_run_bpf_submit+0x143/0x3f0 kernel/events/core.c:8457
+`, `suspicious RCU usage at ./include/linux/rcupdate.h:LINE`, false,
+		}, {
+			`
+[  305.115284] =============================
+[  305.115291] WARNING: suspicious RCU usage
+[  305.115299] 5.6.0-rc3-syzkaller #0 Not tainted
+[  305.115305] -----------------------------
+[  305.115313] net/ipv4/ipmr.c:1757 RCU-list traversed in non-reader section!!
+[  305.115320] 
+[  305.115320] other info that might help us debug this:
+[  305.115320] 
+[  305.115327] 
+[  305.115327] rcu_scheduler_active = 2, debug_locks = 1
+[  305.115335] 1 lock held by syz-executor.0/9742:
+[  305.115342]  #0: ffffffff8a60a1a8 (rtnl_mutex){+.+.}, at: rtnl_lock+0x17/0x20 net/core/rtnetlink.c:72
+[  305.115365] 
+[  305.115365] stack backtrace:
+[  305.115374] CPU: 1 PID: 9742 Comm: syz-executor.0 Not tainted 5.6.0-rc3-syzkaller #0
+[  305.115392] Call Trace:
+[  305.115405]  __dump_stack lib/dump_stack.c:77 [inline]
+[  305.115405]  dump_stack+0x197/0x210 lib/dump_stack.c:118
+[  305.115422]  lockdep_rcu_suspicious+0x153/0x15d kernel/locking/lockdep.c:5444
+[  305.115438]  ipmr_device_event+0x240/0x2b0 net/ipv4/ipmr.c:1757
+[  305.115455]  notifier_call_chain+0xc2/0x230 kernel/notifier.c:83
+`, `suspicious RCU usage at net/ipv4/ipmr.c:LINE`, false,
+		}, {
+			`
+[  146.522262] ========================================================
+[  146.522270] WARNING: possible irq lock inversion dependency detected
+[  146.522280] 4.15.0-rc3+ #219 Not tainted
+[  146.522289] --------------------------------------------------------
+[  146.522297] syz-executor4/17863 just changed the state of lock:
+[  146.522306]  (&(&r->producer_lock)->rlock){+.-.}, at: [<00000000a7ea2d35>] spin_lock include/linux/spinlock.h:310 [inline]
+[  146.522306]  (&(&r->producer_lock)->rlock){+.-.}, at: [<00000000a7ea2d35>] ptr_ring_produce include/linux/ptr_ring.h:101 [inline]
+[  146.522306]  (&(&r->producer_lock)->rlock){+.-.}, at: [<00000000a7ea2d35>] tun_net_xmit+0x5d2/0x1870 drivers/net/tun.c:1036
+[  146.522359] but this lock took another, SOFTIRQ-unsafe lock in the past:
+`, `possible deadlock in tun_net_xmit`, true,
+		}, {
+			`
+[  101.112739] =====================================================
+[  101.112745] WARNING: HARDIRQ-safe -> HARDIRQ-unsafe lock order detected
+[  101.112753] 4.15.0-rc4+ #1 Not tainted
+[  101.112759] -----------------------------------------------------
+[  101.112768] syz-executor0/4420 [HC0[0]:SC0[0]:HE0:SE1] is trying to acquire:
+[  101.112776]  (&(&ctx->ctx_lock)->rlock){+.+.}, at: [<000000008c8d5a6f>] spin_lock include/linux/spinlock.h:310 [inline]
+[  101.112776]  (&(&ctx->ctx_lock)->rlock){+.+.}, at: [<000000008c8d5a6f>] free_ioctx_users+0xbc/0x390 fs/aio.c:610
+`, `possible deadlock in free_ioctx_users`, true,
+		}, {
+			`
+[   67.392094] ==================================================================
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+`, `KASAN: use-after-free Read in ip6_dst_store`, true,
+		}, {
+			`
+[   67.392094] ==================================================================
+[   67.392145] BUG: KASAN: 
+[   67.392145] use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+`, `KASAN: use-after-free Read in ip6_dst_store`, true,
+		}, {
+			`
+[   67.392094] ==================================================================
+[   67.392145] BUG: KASAN:
+[   67.392145] slab-out-of-bounds on address ffff8801c6a1a080
+[   67.392150] Write of size 8 by task syz-executor3/4496
+`, `KASAN: slab-out-of-bounds Write`, true,
+		}, {
+			`
+[   96.862470] IPVS: ftp: loaded support on port[0] = 21
+panic: executor 3: failed: pthread_create failed (errno 11)
 
-func TestLinuxParseText(t *testing.T) {
-	tests := map[string]string{
-		`mmap(&(0x7f00008dd000/0x1000)=nil, (0x1000), 0x3, 0x32, 0xffffffffffffffff, 0x0)
-getsockopt$NETROM_N2(r2, 0x103, 0x3, &(0x7f00008de000-0x4)=0x1, &(0x7f00008dd000)=0x4)
-[  522.560667] nla_parse: 5 callbacks suppressed
-[  522.565344] netlink: 3 bytes leftover after parsing attributes in process 'syz-executor5'.
-[  536.429346] NMI watchdog: BUG: soft lockup - CPU#1 stuck for 11s! [syz-executor7:16813]
-mmap(&(0x7f0000557000/0x2000)=nil, (0x2000), 0x1, 0x11, r2, 0x1b)
-[  536.437530] Modules linked in:
-[  536.440808] CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.3.5-smp-DEV #119`: `nla_parse: 5 callbacks suppressed
-netlink: 3 bytes leftover after parsing attributes in process 'syz-executor5'.
-NMI watchdog: BUG: soft lockup - CPU#1 stuck for 11s! [syz-executor7:16813]
-Modules linked in:
-CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.3.5-smp-DEV #119
-`,
 
-		// Raw 'dmesg -r' and /proc/kmsg output.
-		`<6>[   85.501187] WARNING: foo
-<6>[   85.501187] nouveau  [     DRM] suspending kernel object tree...
-executing program 1:
-<6>[   85.525111] nouveau  [     DRM] nouveau suspended
-<14>[   85.912347] init: computing context for service 'clear-bcb'`: `WARNING: foo
-nouveau  [     DRM] suspending kernel object tree...
-nouveau  [     DRM] nouveau suspended
-init: computing context for service 'clear-bcb'
-`,
-
-		`[   94.864848] line 0
-[   94.864848] line 1
-[   94.864848] line 2
-[   94.864848] line 3
-[   94.864848] line 4
-[   94.864848] line 5
-[   95.145581] ==================================================================
-[   95.152992] BUG: KASAN: use-after-free in snd_seq_queue_alloc+0x670/0x690 at addr ffff8801d0c6b080
-[   95.162080] Read of size 4 by task syz-executor2/5764`: `line 2
-line 3
-line 4
-line 5
-==================================================================
-BUG: KASAN: use-after-free in snd_seq_queue_alloc+0x670/0x690 at addr ffff8801d0c6b080
-Read of size 4 by task syz-executor2/5764
-`,
+goroutine 25 [running]:
+main.(*Proc).executeRaw(0xc4201ea000, 0xc420334000, 0xc42028e000, 0xc42000000a)
+	/syzkaller/gopath/src/github.com/google/syzkaller/syz-fuzzer/proc.go:250 +0x40b
+`, `executor crash: failed: pthread_create failed (errno 11)`, false,
+		}, {
+			`
+2018/01/10 10:24:15 executing program 1:
+panic: executor 12345: got bad reply magic 0xafd35bf2
+`, `executor crash: got bad reply magic ADDR`, false,
+		}, {
+			`
+[   86.491531] ==================================================================
+[   86.498962] BUG: KCSAN: data-race in ext4_mark_iloc_dirty / ext4_mark_iloc_dirty
+[   86.506280] 
+[   86.507910] write to 0xffff88812c1d3a48 of 4 bytes by task 5961 on cpu 0:
+[   86.514842]  ext4_mark_iloc_dirty+0x77/0x1520 fs/ext4/inode.c:5714
+[   86.521163]  __ext4_mark_inode_dirty+0x10c/0x460 fs/ext4/inode.c:5902
+[   86.527753]  ext4_dirty_inode+0x74/0xa0 fs/ext4/inode.c:5939
+[   86.533730] 
+[   86.535351] read to 0xffff88812c1d3a48 of 4 bytes by task 5963 on cpu 1:
+[   86.542240]  ext4_mark_iloc_dirty+0x6e/0x1520 fs/ext4/inode.c:5711
+[   86.548571]  __ext4_mark_inode_dirty+0x10c/0x460 fs/ext4/inode.c:5902
+[   86.555165] 
+[   86.556784] Reported by Kernel Concurrency Sanitizer on:
+[   86.562244] CPU: 1 PID: 5963 Comm: syz-executor.2 Not tainted 5.5.0-rc1-syzkaller #0
+[   86.570138] ==================================================================
+`, `KCSAN: data-race in ext4_mark_iloc_dirty / ext4_mark_iloc_dirty`, false,
+		}, {
+			`
+[   86.491531] ==================================================================
+[   86.498962] BUG: KCSAN: data-race in do_sys_poll
+[   86.506280] 
+[   86.507910] race at unknown origin, with read to 0xffff88812c1d3a48 of 4 bytes by task 5961 on cpu 0:
+[   86.514842]  do_sys_poll+0x77/0x1520 fs/select.c:990
+[   86.533730] 
+[   86.556784] Reported by Kernel Concurrency Sanitizer on:
+[   86.570138] ==================================================================
+`, `KCSAN: data-race in do_sys_poll`, false,
+		}, {
+			`
+[  112.398570] ==================================================================
+[  112.406031] BUG: KCSAN: assert: race in dequeue_entities / enqueue_task_fair
+[  112.413236] 
+[  112.414861] assert no writes to 0xffff8881f5c2f1c0 of 8 bytes by task 2934 on cpu 1:
+[  112.422673]  dequeue_entities+0x2a4/0x6e0 kernel/sched/fair.c:6935
+[  112.428958]  dequeue_task_fair+0x27/0x120 kernel/sched/fair.c:7024
+[  112.435152] 
+[  112.436769] write to 0xffff8881f5c2f1c0 of 8 bytes by task 2936 on cpu 0:
+[  112.443661]  enqueue_task_fair+0xc57/0x1240 kernel/sched/fair.c:6812
+[  112.449944] 
+[  112.451568] Reported by Kernel Concurrency Sanitizer on:
+[  112.479410] ==================================================================
+`, `KCSAN: assert: race in dequeue_entities / enqueue_task_fair`, false,
+		}, {
+			`
+[  112.398570] ==================================================================
+[  112.406031] BUG: KCSAN: assert: race in rcu_preempt_deferred_qs_irqrestore
+[  112.413236] 
+[  112.414861] assert no accesses to 0xffff8881f5c2f1c0 of 8 bytes by task 2934 on cpu 1:
+[  112.422673]  rcu_preempt_deferred_qs_irqrestore+0x2a4/0x6e0 kernel/rcu/tree_plugin.h:456
+[  112.451568] Reported by Kernel Concurrency Sanitizer on:
+[  112.479410] ==================================================================
+`, `KCSAN: assert: race in rcu_preempt_deferred_qs_irqrestore`, false,
+		}, {
+			`
+[   40.109352] Unable to handle kernel paging request at virtual address ffff800000000008
+[   40.110288] Mem abort info:
+[   40.110607]   ESR = 0x96000006
+[   40.110950]   EC = 0x25: DABT (current EL), IL = 32 bits
+[   40.117384] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[   40.118014] Modules linked in:
+[   40.118372] CPU: 1 PID: 2997 Comm: syz-executor.0 Not tainted 5.10.0-syzkaller #0
+[   40.119101] Hardware name: linux,dummy-virt (DT)
+[   40.119613] pstate: 80400005 (Nzcv daif +PAN -UAO -TCO BTYPE=--)
+[   40.120283] pc : __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.120739] lr : lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.121171] sp : ffff800012c4bb70
+[   40.131234] Call trace:
+[   40.131563]  __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.132021]  lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.132462]  walk_component+0x44/0x1c8 fs/namei.c:1853
+[   40.137008] Code: f9400282 b4000482 d1002042 f9400443 (f9400444)
+[   40.137470] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, `unable to handle kernel paging request in __d_lookup_rcu`, false,
+		}, {
+			`
+[   73.318485] Unable to handle kernel NULL pointer dereference at virtual address 0000000000000010
+[   73.319507] Mem abort info:
+[   73.319827]   ESR = 0x96000005
+[   73.326475] Internal error: Oops: 0000000096000005 [#1] PREEMPT SMP
+[   73.327104] Modules linked in:
+[   73.327462] CPU: 0 PID: 3120 Comm: syz-executor.1 Not tainted 6.3.0-syzkaller #0
+[   73.329521] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   73.329977] lr : sock_bar+0x35/0x50 net/core/sock.c:136
+[   73.338112] Call trace:
+[   73.338443]  sock_foo+0x24/0x30 net/core/sock.c:123
+[   73.338871]  sock_bar+0x35/0x50 net/core/sock.c:136
+[   73.339335]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[   73.343651] Code: 91004000 97fff331 f9400a60 b4000080 (f9400800)
+`, `unable to handle kernel NULL pointer dereference in sock_foo`, false,
+		}, {
+			`
+[  524.323582] Internal error: Oops - undefined instruction: 0 [#1] PREEMPT SMP
+[  524.324221] Modules linked in:
+[  524.324578] CPU: 1 PID: 4811 Comm: syz-executor.2 Not tainted 5.15.0-syzkaller #0
+[  524.326357] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[  524.326812] lr : sock_bar+0x35/0x50 net/core/sock.c:136
+[  524.334912] Call trace:
+[  524.335243]  sock_foo+0x24/0x30 net/core/sock.c:123
+[  524.335671]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  524.340101] Code: d503233f a9bf7bfd 910003fd d4210000 (00000000)
+`, `invalid opcode in sock_foo`, false,
+		}, {
+			`
+[  524.323582] Internal error: synchronous external abort: 96000210 [#1] PREEMPT SMP
+[  524.324221] Modules linked in:
+[  524.326357] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[  524.334912] Call trace:
+[  524.335243]  sock_foo+0x24/0x30 net/core/sock.c:123
+[  524.335671]  sock_bar+0x35/0x50 net/core/sock.c:136
+`, `internal error in sock_foo`, false,
+		}, {
+			`
+[  524.323582] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[  524.324221] Modules linked in:
+`, `internal error`, true,
+		}, {
+			`
+[   56.270367] Unable to handle kernel paging request at virtual address ffffffff00000008
+[   56.271581] Oops [#1]
+[   56.271809] Modules linked in:
+[   56.272017] CPU: 0 PID: 3069 Comm: syz-executor.0 Not tainted 5.19.0-syzkaller #0
+[   56.272017] Hardware name: riscv-virtio,qemu (DT)
+[   56.272017] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.272017]  ra : sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.275433] epc : ffffffff8000405c ra : ffffffff80004a5c sp : ffffffe0006b3d30
+[   56.275433]  gp : ffffffff85863ac0 tp : ffffffe00a5e0000 t0 : 0000000000000000
+[   56.275433] status: 0000000000000120 badaddr: ffffffff00000008 cause: 000000000000000d
+[   56.279245] Call Trace:
+[   56.279646] [<ffffffff8000405c>] sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.280419] [<ffffffff80004a5c>] sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.281032] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, `unable to handle kernel paging request in sock_foo`, false,
+		}, {
+			`
+[   31.103401] Unable to handle kernel NULL pointer dereference at virtual address 0000000000000000
+[   31.104572] Oops [#1]
+[   31.105321] CPU: 1 PID: 4418 Comm: syz-executor.0 Not tainted 5.17.0-rc1-syzkaller #0
+[   31.106100] epc : __memset+0x60/0xfc arch/riscv/lib/memset.S:64
+[   31.106100]  ra : skb_put+0x35/0x50 net/core/skbuff.c:1935
+[   31.106100] epc : ffffffff831668cc ra : ffffffff8199d5b2 sp : ffffffe0006b3d30
+[   31.106100] status: 0000000000000120 badaddr: 0000000000000000 cause: 000000000000000f
+[   31.107793] [<ffffffff831668cc>] __memset+0x60/0xfc arch/riscv/lib/memset.S:64
+[   31.108364] [<ffffffff8199d5b2>] skb_put+0x35/0x50 net/core/skbuff.c:1935
+`, `unable to handle kernel NULL pointer dereference in __memset`, false,
+		}, {
+			`
+[   31.103401] Unable to handle kernel access to user memory without uaccess routines at virtual address 0000000020000100
+[   31.104572] Oops [#1]
+[   31.106100] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   31.106100] status: 0000000000000120 badaddr: 0000000020000100 cause: 000000000000000f
+[   31.107793] [<ffffffff831668cc>] sock_foo+0x24/0x30 net/core/sock.c:123
+`, `unable to handle kernel access to user memory in sock_foo`, false,
+		}, {
+			`
+[  112.863452] Unable to handle kernel access to user memory outside uaccess routines at virtual address 0000000020000180
+[  112.874287] Mem abort info:
+[  112.877085]   ESR = 0x9600000f
+[  112.880148]   EC = 0x25: DABT (current EL), IL = 32 bits
+[  112.885664] Internal error: Oops: 9600000f [#1] PREEMPT SMP
+[  112.891236] Modules linked in:
+[  112.894436] CPU: 0 PID: 3123 Comm: syz-executor.1 Not tainted 5.15.0-rc4-syzkaller #0
+[  112.902293] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.907681] lr : sock_bar+0x35/0x50 net/core/sock.c:136
+[  112.913068] Call trace:
+[  112.915521]  sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.920643]  sock_bar+0x35/0x50 net/core/sock.c:136
+`, `unable to handle kernel access to user memory in sock_foo`, false,
+		}, {
+			`
+[  112.863452] Unable to handle kernel read access to user memory outside uaccess routines at virtual address 0000000020000180
+[  112.885664] Internal error: Oops: 9600000f [#1] PREEMPT SMP
+[  112.902293] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.913068] Call trace:
+[  112.915521]  sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.920643]  sock_bar+0x35/0x50 net/core/sock.c:136
+`, `unable to handle kernel access to user memory in sock_foo`, false,
+		}, {
+			`
+[   31.104572] Oops - illegal instruction [#1]
+[   31.106100] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   31.106100] status: 0000000000000120 badaddr: 0000000000000000 cause: 0000000000000002
+[   31.107793] [<ffffffff831668cc>] sock_foo+0x24/0x30 net/core/sock.c:123
+`, `invalid opcode in sock_foo`, false,
+		}, {
+			`
+[   31.104572] Oops - load access fault [#1]
+[   31.106100] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   31.106100] status: 0000000000000120 badaddr: 0000000000000000 cause: 0000000000000005
+[   31.107793] [<ffffffff831668cc>] sock_foo+0x24/0x30 net/core/sock.c:123
+`, `load access fault in sock_foo`, false,
+		}, {
+			`
+[   31.104572] Oops [#1]
+[   31.105321] CPU: 1 PID: 4418 Comm: syz-executor.0 Not tainted 5.17.0-rc1-syzkaller #0
+[   31.106100] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   31.106100] status: 0000000000000120 badaddr: 0000000000000000 cause: 000000000000000f
+[   31.107793] [<ffffffff831668cc>] sock_foo+0x24/0x30 net/core/sock.c:123
+`, `Oops in sock_foo`, false,
+		}, {
+			// RCU stall is detected on an idle CPU, but the soft lockup stack from the same hang
+			// shows the stuck task. Both banners are from the same hang, the soft lockup is selected.
+			`
+[  277.780013] INFO: rcu_sched detected stalls on CPUs/tasks:
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.781197] 	(detected by 0, t=65002 jiffies, g=72940, c=72939, q=1777)
+[  277.782014] NMI backtrace for cpu 0
+[  277.782014] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 4.15.0+ #1
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  dump_stack+0x194/0x257
+[  277.782014]  nmi_cpu_backtrace+0x1d2/0x210
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  update_process_times+0x30/0x60
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  rcu_idle_exit+0x15/0x20
+[  277.782014]  default_idle+0x27/0x2f0
+[  277.782014]  arch_cpu_idle+0xa/0x10
+[  277.782014]  do_idle+0x283/0x3c0
+[  277.782014]  cpu_startup_entry+0x104/0x120
+[  278.429346] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [syz-executor7:16813]
+[  278.437530] Modules linked in:
+[  278.440808] CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.15.0+ #1
+[  278.440808] RIP: 0010:sock_spin+0x24/0x30 net/core/sock.c:123
+[  278.440808] Call Trace:
+[  278.440808]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  278.440808]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  278.440808]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+`, `BUG: soft lockup`, false,
+		}, {
+			// The soft lockup stack has only the watchdog interrupt and idle frames.
+			`
+[  276.429346] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [swapper/1:0]
+[  276.437530] Modules linked in:
+[  276.440808] CPU: 1 PID: 0 Comm: swapper/1 Not tainted 4.15.0+ #1
+[  276.440808] Call Trace:
+[  276.440808]  <IRQ>
+[  276.440808]  watchdog_timer_fn+0x20/0x60
+[  276.440808]  __hrtimer_run_queues+0x3a6/0xfa0
+[  276.440808]  </IRQ>
+[  276.440808]  default_idle+0x27/0x2f0
+[  276.440808]  do_idle+0x283/0x3c0
+[  277.780013] INFO: rcu_sched self-detected stall on CPU
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			// Both stacks are equally specific, the first banner wins.
+			`
+[  277.780013] INFO: rcu_sched self-detected stall on CPU
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+[  278.429346] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [syz-executor7:16813]
+[  278.437530] Modules linked in:
+[  278.440808] CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.15.0+ #1
+[  278.440808] RIP: 0010:sock_spin+0x24/0x30 net/core/sock.c:123
+[  278.440808] Call Trace:
+[  278.440808]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  278.440808]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  278.440808]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+`, `INFO: rcu detected stall in __sctp_write_space`, false,
+		}, {
+			`
+[  276.429346] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [syz-executor7:16813]
+[  276.437530] Modules linked in:
+[  276.440808] CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.15.0+ #1
+[  276.440808] RIP: 0010:sock_spin+0x24/0x30 net/core/sock.c:123
+[  276.440808] Call Trace:
+[  276.440808]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  276.440808]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  276.440808]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+[  277.780013] INFO: rcu_sched self-detected stall on CPU
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  rcu_check_callbacks+0x17fd/0x2a10
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014]  sctp_wfree+0x1a6/0x2a0
+`, `BUG: soft lockup`, false,
+		}, {
+			`
+[   88.117380] list_add corruption. prev->next should be next (ffff88806e55e3c8), but was ffff888063b6c1c8. (prev=ffff88806e55e3c8).
+[   88.128904] ------------[ cut here ]------------
+[   88.133703] kernel BUG at lib/list_debug.c:28!
+[   88.138351] invalid opcode: 0000 [#1] PREEMPT SMP KASAN
+[   88.143754] CPU: 0 PID: 7239 Comm: syz-executor.2 Not tainted 5.10.0-rc6-syzkaller #0
+[   88.160402] RIP: 0010:__list_add_valid.cold+0x26/0x3c lib/list_debug.c:26
+[   88.245130] Call Trace:
+[   88.247726]  __list_add include/linux/list.h:67 [inline]
+[   88.253215]  list_add_tail include/linux/list.h:100 [inline]
+[   88.258782]  nfc_llcp_sock_link net/nfc/llcp_sock.c:56 [inline]
+[   88.264432]  llcp_sock_bind+0x4c3/0x8d0 net/nfc/llcp_sock.c:101
+[   88.270493]  __sys_bind+0x1e9/0x250 net/socket.c:1656
+[   88.275840]  do_syscall_64+0x2d/0x70 arch/x86/entry/common.c:46
+`, `list corruption in llcp_sock_bind`, false,
+		}, {
+			`
+[  145.395339] list_del corruption, ffff8880a2c8a268->next is LIST_POISON1 (dead000000000100)
+[  145.403993] ------------[ cut here ]------------
+[  145.408808] kernel BUG at lib/list_debug.c:47!
+[  145.413411] invalid opcode: 0000 [#1] PREEMPT SMP KASAN
+[  145.418824] CPU: 1 PID: 10330 Comm: syz-executor.4 Not tainted 5.8.0-rc7-syzkaller #0
+[  145.434100] RIP: 0010:__list_del_entry_valid.cold+0xf/0x55 lib/list_debug.c:47
+[  145.520218] Call Trace:
+[  145.522829]  __list_del_entry include/linux/list.h:132 [inline]
+[  145.528880]  list_del include/linux/list.h:146 [inline]
+[  145.534337]  tipc_sk_remove_from_list+0x2a/0x190 net/tipc/socket.c:1120
+[  145.541149]  tipc_release+0x331/0x1330 net/tipc/socket.c:631
+[  145.547212]  __sock_release+0xcd/0x280 net/socket.c:605
+`, `list corruption in tipc_sk_remove_from_list`, false,
+		}, {
+			`
+[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at lib/list_debug.c:33 __list_add+0xe4/0x110
+[   42.274727] list_add corruption. prev->next should be next (ffff8801ca8a5a78), but was ffff8801c4f77a78. (prev=ffff8801ca8a5a78).
+[   42.286564] Kernel panic - not syncing: panic_on_warn set ...
+[   42.292452] CPU: 1 PID: 4340 Comm: syz-executor1 Not tainted 4.9.80-ge4c7b5f #33
+[   42.306981] Call Trace:
+[   42.309554]  [<ffffffff81d93349>] dump_stack+0xc1/0x128
+[   42.314898]  [<ffffffff8141a396>] panic+0x1bf/0x39f
+[   42.320059]  [<ffffffff8141a500>] ? add_taint.cold+0x16/0x16
+[   42.326374]  [<ffffffff811341b1>] __warn.cold+0x2f/0x2f
+[   42.331749]  [<ffffffff81e1ebb4>] warn_slowpath_fmt+0xc5/0x100
+[   42.337707]  [<ffffffff81df4d64>] __list_add+0xe4/0x110
+[   42.343170]  [<ffffffff8273f2e1>] l2tp_session_create+0x5c1/0x8e0
+[   42.349568]  [<ffffffff82747786>] pppol2tp_connect+0x1086/0x1820
+`, `list corruption in l2tp_session_create`, false,
+		}, {
+			`
+[  145.395339] list_del corruption. prev->next should be ffff8880a2c8a268, but was ffff8880a2c8a000
+[  145.403993] ------------[ cut here ]------------
+`, `list corruption`, true,
+		}, {
+			`
+[   24.503514] sysfs: cannot create duplicate filename '/devices/virtual/net/bond0'
+[   24.511272] CPU: 0 PID: 8472 Comm: syz-executor.0 Not tainted 5.10.0-rc3-syzkaller #0
+[   24.519892] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   24.529230] Call Trace:
+[   24.531812]  __dump_stack lib/dump_stack.c:77 [inline]
+[   24.537015]  dump_stack+0x107/0x163 lib/dump_stack.c:118
+[   24.542458]  sysfs_warn_dup.cold+0x1c/0x29 fs/sysfs/dir.c:31
+[   24.548234]  sysfs_create_dir_ns+0x238/0x290 fs/sysfs/dir.c:59
+[   24.554335]  create_dir lib/kobject.c:70 [inline]
+[   24.559258]  kobject_add_internal+0x2ba/0x8b0 lib/kobject.c:238
+[   24.565460]  kobject_add_varg lib/kobject.c:390 [inline]
+[   24.570810]  kobject_add+0x150/0x1c0 lib/kobject.c:442
+[   24.576220]  device_add+0x368/0x1d90 drivers/base/core.c:2952
+[   24.582150]  netdev_register_kobject+0x183/0x3b0 net/core/net-sysfs.c:1915
+[   24.589079]  register_netdevice+0xe0d/0x1530 net/core/dev.c:10060
+[   24.595312]  bond_create+0xc1/0x120 drivers/net/bonding/bond_main.c:5090
+[   24.601475]  bond_net_init+0x1c8/0x250 drivers/net/bonding/bond_main.c:5170
+[   24.607975]  ops_init+0xaf/0x470 net/core/net_namespace.c:152
+[   24.614295] kobject_add_internal failed for bond0 with -EEXIST, don't try to register things with the same name in the same directory.
+`, `sysfs: cannot create duplicate filename in bond_create`, false,
+		}, {
+			`
+[  112.443721] kobject_add_internal failed for hci0 (error: -12 parent: bluetooth)
+[  112.451345] CPU: 1 PID: 10245 Comm: syz-executor.3 Not tainted 5.4.0-rc8-syzkaller #0
+[  112.460123] Call Trace:
+[  112.462732]  dump_stack+0x197/0x210 lib/dump_stack.c:118
+[  112.468379]  kobject_add_internal.cold+0x18/0x3e lib/kobject.c:244
+[  112.474765]  kobject_add+0x150/0x1c0 lib/kobject.c:442
+[  112.480189]  device_add+0x3d2/0x1b00 drivers/base/core.c:2136
+[  112.486063]  hci_register_dev+0x2e6/0x8f0 net/bluetooth/hci_core.c:3330
+[  112.492717]  __vhci_create_device+0x2ac/0x5b0 drivers/bluetooth/hci_vhci.c:124
+[  112.499856]  vhci_create_device drivers/bluetooth/hci_vhci.c:148 [inline]
+[  112.505601]  vhci_get_user drivers/bluetooth/hci_vhci.c:205 [inline]
+[  112.511034]  vhci_write+0x2d0/0x460 drivers/bluetooth/hci_vhci.c:285
+`, `kobject_add_internal failed in hci_register_dev`, false,
+		}, {
+			`
+[   61.595432] ------------[ cut here ]------------
+[   61.600226] WARNING: CPU: 0 PID: 7075 at fs/sysfs/dir.c:31 sysfs_warn_dup+0x8a/0xa0
+[   61.608165] sysfs: cannot create duplicate filename '/class/ieee80211/phy3'
+[   61.615128] Kernel panic - not syncing: panic_on_warn set ...
+[   61.621035] CPU: 0 PID: 7075 Comm: syz-executor5 Not tainted 4.9.112-g2f5b8a3 #20
+[   61.636235] Call Trace:
+[   61.638804]  [<ffffffff81eb5e8d>] dump_stack+0xc1/0x128
+[   61.644149]  [<ffffffff81428f23>] panic+0x1bf/0x39f
+[   61.655013]  [<ffffffff81290ce1>] __warn.cold+0x2f/0x2f
+[   61.660365]  [<ffffffff81290fd9>] warn_slowpath_fmt+0xc5/0x100
+[   61.666562]  [<ffffffff81700f2a>] sysfs_warn_dup+0x8a/0xa0
+[   61.672150]  [<ffffffff817012e7>] sysfs_create_dir_ns+0x127/0x150
+[   61.678363]  [<ffffffff81f06581>] kobject_add_internal+0x291/0x960
+[   61.684664]  [<ffffffff81f06f4b>] kobject_add+0x11b/0x160
+[   61.690115]  [<ffffffff822b3a18>] device_add+0x368/0x16d0
+[   61.695567]  [<ffffffff838e3a63>] wiphy_register+0x13c3/0x1c90
+[   61.701633]  [<ffffffff83b13ff0>] ieee80211_register_hw+0x1310/0x2fd0
+[   61.708108]  [<ffffffff827bcca7>] mac80211_hwsim_new_radio+0x1c47/0x2f40
+`, `sysfs: cannot create duplicate filename in wiphy_register`, false,
+		}, {
+			`
+[   94.120453] ------------[ cut here ]------------
+[   94.125252] WARNING: CPU: 1 PID: 9188 at lib/kobject.c:240 kobject_add_internal+0x7bf/0x960
+[   94.133787] kobject_add_internal failed for loop0 (error: -12 parent: block)
+[   94.141327] Kernel panic - not syncing: panic_on_warn set ...
+[   94.147213] CPU: 1 PID: 9188 Comm: syz-executor2 Not tainted 4.14.50+ #3
+[   94.160750] Call Trace:
+[   94.163338]  [<ffffffff81d0959e>] dump_stack+0x114/0x1cf
+[   94.168778]  [<ffffffff8140c0d5>] panic+0x1bf/0x3bc
+[   94.180639]  [<ffffffff811a733d>] __warn.cold+0x2f/0x2f
+[   94.186006]  [<ffffffff811a7561>] warn_slowpath_fmt+0xc5/0x100
+[   94.192175]  [<ffffffff81d2329f>] kobject_add_internal+0x7bf/0x960
+[   94.198471]  [<ffffffff81d238ea>] kobject_add+0x11a/0x160
+[   94.203991]  [<ffffffff82166ef7>] device_add+0x3a7/0x16a0
+[   94.209477]  [<ffffffff81c49a44>] device_add_disk+0x2e4/0x1030
+[   94.215580]  [<ffffffff8228d283>] loop_add+0x5e3/0x7c0
+[   94.220818]  [<ffffffff8228d3e1>] loop_probe+0x161/0x1b0
+`, `kobject_add_internal failed in loop_add`, false,
+		}, {
+			`
+[   24.503514] sysfs: cannot create duplicate filename '/devices/virtual/misc/vhost-net'
+[   24.511272] CPU: 0 PID: 8472 Comm: syz-executor.0 Not tainted 5.10.0-rc3-syzkaller #0
+`, `sysfs: cannot create duplicate filename`, true,
+		}, {
+			`
+[  982.271203] BUG: spinlock bad magic on CPU#0, syz-executor12/24932
+[  982.277556]  lock: 0xffff88005c4a1c78, .magic: 00000000, .owner: <none>/-1, .owner_cpu: 0
+[  982.286002] CPU: 0 PID: 24932 Comm: syz-executor12 Not tainted 4.4.0+ #250
+[  982.293049] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  982.302393] Call Trace:
+[  982.304962]  [<ffffffff82ae8cbd>] dump_stack+0x6f/0xa2
+[  982.310212]  [<ffffffff813fba22>] spin_dump+0x152/0x280
+[  982.315560]  [<ffffffff813fbd51>] spin_bug+0x31/0x40
+[  982.320564]  [<ffffffff813fc2a2>] do_raw_spin_lock+0x222/0x2c0
+[  982.326533]  [<ffffffff866bd1d4>] _raw_spin_lock_irqsave+0x44/0x60
+[  982.332838]  [<ffffffff8156ea11>] skb_queue_tail+0x21/0xb0
+[  982.338440]  [<ffffffff85e1ec0a>] unix_dgram_sendmsg+0x77a/0x1290
+`, `BUG: spinlock bad magic in skb_queue_tail`, false,
+		}, {
+			`
+[   72.159680] BUG: spinlock lockup suspected on CPU#2, syz-executor/12636
+[   72.166149]  lock: 0xffff88003ed16a00, .magic: dead4ead, .owner: syz-executor/12630, .owner_cpu: 1
+[   72.175275] CPU: 2 PID: 12636 Comm: syz-executor Not tainted 4.3.0+ #35
+[   72.182190] Call Trace:
+[   72.184793]  [<ffffffff81d9d4a5>] dump_stack+0xc1/0x128
+[   72.190156]  [<ffffffff813fba22>] spin_dump+0x152/0x280
+[   72.195533]  [<ffffffff813fc3ab>] do_raw_spin_lock+0x28b/0x3c0
+[   72.201533]  [<ffffffff85b8b03d>] _raw_spin_lock+0x3d/0x50
+[   72.207130]  [<ffffffff815a1d58>] get_partial_node.isra.62+0x48/0x2f0
+[   72.213749]  [<ffffffff815a7cc5>] ___slab_alloc+0x245/0x590
+`, `BUG: spinlock lockup suspected in get_partial_node`, false,
+		}, {
+			`
+[  315.218535] BUG: rwlock recursion on CPU#1, syz-executor2/17405, ffff8801c8b71228
+[  315.226966] CPU: 1 PID: 17405 Comm: syz-executor2 Not tainted 4.15.0-rc3+ #219
+[  315.234406] Call Trace:
+[  315.236998]  dump_stack+0x194/0x257
+[  315.240622]  rwlock_bug.part.0+0x90/0x97
+[  315.244680]  do_raw_write_lock+0x1a1/0x1d0
+[  315.248915]  _raw_write_lock_bh+0x35/0x40
+[  315.253067]  sk_dst_set+0x2e/0x90
+[  315.256523]  ip6_datagram_dst_update+0x37b/0xa10
+`, `BUG: rwlock recursion in sk_dst_set`, false,
+		}, {
+			`
+[  107.174714] ------------[ cut here ]------------
+[  107.179512] ODEBUG: free active (active state 0) object: ffff8880a7c1c2e8 object type: timer_list hint: delayed_work_timer_fn+0x0/0x90 kernel/workqueue.c:1438
+[  107.193474] WARNING: CPU: 1 PID: 10062 at lib/debugobjects.c:485 debug_print_object+0x168/0x250 lib/debugobjects.c:485
+[  107.204177] Modules linked in:
+[  107.207368] CPU: 1 PID: 10062 Comm: kworker/u4:5 Not tainted 5.6.0-rc3-syzkaller #0
+[  107.225889] Workqueue: netns cleanup_net
+[  107.229951] RIP: 0010:debug_print_object+0x168/0x250 lib/debugobjects.c:485
+[  107.248678] RSP: 0018:ffffc90004c77a98 EFLAGS: 00010282
+[  107.320179] Call Trace:
+[  107.322775]  __debug_check_no_obj_freed lib/debugobjects.c:967 [inline]
+[  107.328929]  debug_check_no_obj_freed+0x2e1/0x445 lib/debugobjects.c:998
+[  107.335865]  kfree+0xf8/0x2c0 mm/slab.c:3756
+[  107.340280]  rds_tcp_kill_sock net/rds/tcp.c:616 [inline]
+[  107.345721]  rds_tcp_exit_net+0x4a0/0x880 net/rds/tcp.c:630
+[  107.351811]  ops_exit_list.isra.0+0xb1/0x160 net/core/net_namespace.c:172
+[  107.358649]  cleanup_net+0x538/0xaf0 net/core/net_namespace.c:589
+`, `ODEBUG: free active in rds_tcp_exit_net`, false,
+		}, {
+			`
+[   52.911813] ------------[ cut here ]------------
+[   52.916579] ODEBUG: init active (active state 0) object: ffff88809fb3b5a8 object type: hrtimer hint: hrtimer_wakeup+0x0/0x30 kernel/time/hrtimer.c:1868
+[   52.930360] WARNING: CPU: 0 PID: 8561 at lib/debugobjects.c:485 debug_print_object+0x168/0x250 lib/debugobjects.c:485
+[   52.941024] Modules linked in:
+[   52.944233] CPU: 0 PID: 8561 Comm: syz-executor.1 Not tainted 5.6.0-rc7-syzkaller #0
+[   52.962639] RIP: 0010:debug_print_object+0x168/0x250 lib/debugobjects.c:485
+[   53.039941] Call Trace:
+[   53.042513]  __debug_object_init+0x524/0xd10 lib/debugobjects.c:568
+[   53.049022]  debug_hrtimer_init kernel/time/hrtimer.c:414 [inline]
+[   53.054359]  debug_init kernel/time/hrtimer.c:462 [inline]
+[   53.059399]  hrtimer_init+0x2d/0x310 kernel/time/hrtimer.c:1383
+[   53.064920]  tcf_block_get_ext+0x3f1/0x9f0 net/sched/cls_api.c:1398
+[   53.071243]  mqprio_init+0x2ad/0x6d0 net/sched/sch_mqprio.c:241
+[   53.077221]  qdisc_create+0x4b6/0x12e0 net/sched/sch_api.c:1234
+`, `ODEBUG: init active in tcf_block_get_ext`, false,
+		}, {
+			`
+[  122.393139] ------------[ cut here ]------------
+[  122.397922] WARNING: CPU: 1 PID: 7676 at lib/debugobjects.c:291 debug_print_object+0xfb/0x1d0
+[  122.406587] ODEBUG: activate not available (active state 0) object type: rcu_head hint: (null)
+[  122.415454] Kernel panic - not syncing: panic_on_warn set ...
+[  122.421340] CPU: 1 PID: 7676 Comm: syz-executor3 Not tainted 4.14.43+ #2
+[  122.434947] Call Trace:
+[  122.437533]  [<ffffffff81d0959e>] dump_stack+0x114/0x1cf
+[  122.442971]  [<ffffffff8140c0d5>] panic+0x1bf/0x3bc
+[  122.454856]  [<ffffffff811a733d>] __warn.cold+0x2f/0x2f
+[  122.460223]  [<ffffffff811a7561>] warn_slowpath_fmt+0xc5/0x100
+[  122.466390]  [<ffffffff81d2ae5b>] debug_print_object+0xfb/0x1d0
+[  122.472634]  [<ffffffff81d2b8d4>] debug_object_activate+0x2c4/0x400
+[  122.479235]  [<ffffffff812753b9>] __call_rcu.constprop.0+0x69/0x7f0
+[  122.485827]  [<ffffffff82aa5c47>] fib6_del_route+0x967/0xd30
+`, `ODEBUG: activate not available in __call_rcu`, false,
+		}, {
+			`
+[  107.179512] ODEBUG: free active (active state 0) object: ffff8880a7c1c2e8 object type: timer_list hint: delayed_work_timer_fn+0x0/0x90 kernel/workqueue.c:1438
+`, `ODEBUG: free active`, true,
+		},
 	}
+	testParse(t, "linux", tests)
+	testTitle(t, "linux", tests)
+}
+
+func TestLinuxIgnores(t *testing.T) {
 	reporter, err := NewReporter("linux", "", "", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	for log, text0 := range tests {
-		if rep := reporter.Parse([]byte(log)); string(rep.Report) != text0 {
-			t.Logf("log:\n%s", log)
-			t.Logf("want text:\n%s", text0)
-			t.Logf("got text:\n%s", rep.Report)
-			t.Fatalf("bad text, desc: '%v'", rep.Title)
-		}
+	ignores1 := []*regexp.Regexp{
+		regexp.MustCompile("BUG: bug3"),
+	}
+	reporter1, err := NewReporter("linux", "", "", nil, ignores1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignores2 := []*regexp.Regexp{
+		regexp.MustCompile("BUG: bug3"),
+		regexp.MustCompile("BUG: bug1"),
+	}
+	reporter2, err := NewReporter("linux", "", "", nil, ignores2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignores3 := []*regexp.Regexp{
+		regexp.MustCompile("BUG: bug3"),
+		regexp.MustCompile("BUG: bug1"),
+		regexp.MustCompile("BUG: bug2"),
+	}
+	reporter3, err := NewReporter("linux", "", "", nil, ignores3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const log = `
+[    0.000000] BUG: bug1
+[    0.000000] BUG: bug2
+	`
+	if !reporter.ContainsCrash([]byte(log)) {
+		t.Fatalf("no crash")
+	}
+	if rep := reporter.Parse([]byte(log)); rep.Title != "BUG: bug1" {
+		t.Fatalf("want `BUG: bug1`, found `%v`", rep.Title)
+	}
+
+	if !reporter1.ContainsCrash([]byte(log)) {
+		t.Fatalf("no crash")
+	}
+	if rep := reporter1.Parse([]byte(log)); rep.Title != "BUG: bug1" {
+		t.Fatalf("want `BUG: bug1`, found `%v`", rep.Title)
+	}
+
+	if !reporter2.ContainsCrash([]byte(log)) {
+		t.Fatalf("no crash")
+	}
+	if rep := reporter2.Parse([]byte(log)); rep.Title != "BUG: bug2" {
+		t.Fatalf("want `BUG: bug2`, found `%v`", rep.Title)
+	}
+
+	if reporter3.ContainsCrash([]byte(log)) {
+		t.Fatalf("found crash, should be ignored")
+	}
+	if rep := reporter3.Parse([]byte(log)); rep != nil {
+		t.Fatalf("found `%v`, should be ignored", rep.Title)
 	}
 }
 
-func TestLinuxSymbolizeLine(t *testing.T) {
+func TestLinuxContainsCrashDetailed(t *testing.T) {
+	ignores := []*regexp.Regexp{
+		regexp.MustCompile("BUG: bug1"),
+	}
+	reporter, err := NewReporter("linux", "", "", nil, ignores)
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := []struct {
-		line   string
-		result string
+		log        string
+		found      bool
+		suppressed int
+		ignored    int
 	}{
-		// Normal symbolization.
-		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x101/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x101/0x185 foo.c:555\n",
-		},
-		{
-			"RIP: 0010:[<ffffffff8188c0e6>]  [<ffffffff8188c0e6>]  foo+0x101/0x185\n",
-			"RIP: 0010:[<ffffffff8188c0e6>]  [<ffffffff8188c0e6>]  foo+0x101/0x185 foo.c:555\n",
-		},
-		// Strip "./" file prefix.
+		{"[    0.000000] no crash\n", false, 0, 0},
+		{"[    0.000000] BUG: bug1\n", false, 0, 1},
+		{"[    0.000000] INFO: lockdep is turned off\n", false, 1, 0},
 		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x111/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x111/0x185 foo.h:111\n",
+			"[    0.000000] BUG: bug1\n" +
+				"[    0.000000] INFO: lockdep is turned off\n" +
+				"[    0.000000] BUG: bug2\n",
+			true, 1, 1,
 		},
-		// Needs symbolization, but symbolizer returns nothing.
+	}
+	for i, test := range tests {
+		found, suppressed, ignored := reporter.ContainsCrashDetailed([]byte(test.log))
+		if found != test.found || suppressed != test.suppressed || ignored != test.ignored {
+			t.Errorf("#%v: got found=%v suppressed=%v ignored=%v, want %v/%v/%v",
+				i, found, suppressed, ignored, test.found, test.suppressed, test.ignored)
+		}
+		if containsCrash := reporter.ContainsCrash([]byte(test.log)); containsCrash != found {
+			t.Errorf("#%v: ContainsCrash returned %v, ContainsCrashDetailed returned %v",
+				i, containsCrash, found)
+		}
+	}
+}
+
+func TestLinuxParseFrom(t *testing.T) {
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const log = `
+[    0.000000] BUG: bug1
+[    0.000000] some line
+[    0.000000] BUG: bug2
+[    0.000000] another line
+`
+	rep := reporter.ParseFrom([]byte(log), 0)
+	if rep == nil || rep.Title != "BUG: bug1" {
+		t.Fatalf("want `BUG: bug1`, found %+v", rep)
+	}
+	second := strings.Index(log, "[    0.000000] BUG: bug2")
+	rep = reporter.ParseFrom([]byte(log), second)
+	if rep == nil || rep.Title != "BUG: bug2" {
+		t.Fatalf("want `BUG: bug2`, found %+v", rep)
+	}
+	if rep.StartPos != second {
+		t.Fatalf("want StartPos %v, got %v", second, rep.StartPos)
+	}
+	if bytes.Contains(rep.Report, []byte("bug1")) {
+		t.Fatalf("report contains content before start position:\n%s", rep.Report)
+	}
+	if rep := reporter.ParseFrom([]byte(log), len(log)); rep != nil {
+		t.Fatalf("found `%v` at the end of output", rep.Title)
+	}
+}
+
+func TestLinuxOopsCount(t *testing.T) {
+	tests := []struct {
+		log       string
+		title     string
+		count     int
+		recursive bool
+		fatal     bool
+	}{
 		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x121/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x121/0x185\n",
+			`
+[   67.392094] BUG: KASAN: use-after-free in foo+0x12/0x34
+[   67.392095] Read of size 8 by task syz-executor0/4460
+`, `KASAN: use-after-free Read in foo`, 0, false, false,
 		},
-		// Needs symbolization, but symbolizer returns error.
 		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x131/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x131/0x185\n",
+			`
+[  289.399057] general protection fault: 0000 [#1] SMP KASAN
+[  289.399120] Modules linked in:
+[  289.399135] CPU: 1 PID: 7147 Comm: syz-executor6 Not tainted 4.15.0-rc5+ #236
+[  289.399149] RIP: 0010:__lock_acquire+0xd55/0x47f0 kernel/locking/lockdep.c:3378
+`, `general protection fault in __lock_acquire`, 1, false, false,
 		},
-		// Needs symbolization, but symbol is missing.
 		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0x131/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0x131/0x185\n",
+			`
+[  289.399057] general protection fault: 0000 [#1] SMP KASAN
+[  289.399120] Modules linked in:
+[  289.399135] CPU: 1 PID: 7147 Comm: syz-executor6 Not tainted 4.15.0-rc5+ #236
+[  289.399149] RIP: 0010:__lock_acquire+0xd55/0x47f0 kernel/locking/lockdep.c:3378
+[  289.399159] ---[ end trace 5c91b4dd5d3b0e84 ]---
+[  289.400301] BUG: unable to handle kernel paging request at ffffffff8a1c2e5b
+[  289.400315] IP: do_exit+0x1e7/0x2ce0 kernel/exit.c:820
+[  289.400335] Oops: 0000 [#2] SMP KASAN
+`, `general protection fault in __lock_acquire`, 2, true, false,
 		},
-		// Bad offset.
 		{
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0xffffffffffffffffffff/0x185\n",
-			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0xffffffffffffffffffff/0x185\n",
+			`
+[  289.399057] general protection fault: 0000 [#1] SMP KASAN
+[  289.399120] Modules linked in:
+[  289.399135] CPU: 1 PID: 7147 Comm: syz-executor6 Not tainted 4.15.0-rc5+ #236
+[  289.399149] RIP: 0010:__lock_acquire+0xd55/0x47f0 kernel/locking/lockdep.c:3378
+[  289.399159] ---[ end trace 5c91b4dd5d3b0e84 ]---
+[  289.400301] BUG: unable to handle kernel paging request at ffffffff8a1c2e5b
+[  289.400315] IP: do_exit+0x1e7/0x2ce0 kernel/exit.c:820
+[  289.400335] Oops: 0000 [#2] SMP KASAN
+[  289.400359] ---[ end trace 5c91b4dd5d3b0e85 ]---
+[  289.400366] Fixing recursive fault but reboot is needed!
+`, `general protection fault in __lock_acquire`, 2, true, true,
 		},
-		// Should not be symbolized.
 		{
-			"WARNING: CPU: 2 PID: 2636 at ipc/shm.c:162 foo+0x101/0x185\n",
-			"WARNING: CPU: 2 PID: 2636 at ipc/shm.c:162 foo+0x101/0x185 foo.c:555\n",
+			`
+[  289.399057] general protection fault: 0000 [#1] SMP KASAN
+[  289.399120] Modules linked in:
+[  289.399135] CPU: 1 PID: 7147 Comm: syz-executor6 Not tainted 4.15.0-rc5+ #236
+[  289.399149] RIP: 0010:__lock_acquire+0xd55/0x47f0 kernel/locking/lockdep.c:3378
+[  289.399159] ---[ end trace 5c91b4dd5d3b0e84 ]---
+[  289.400366] Fixing recursive fault but reboot is needed!
+`, `general protection fault in __lock_acquire`, 1, true, true,
 		},
-		// Tricky function name.
-		{
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || rep.OopsCount != test.count || rep.Recursive != test.recursive ||
+			rep.Fatal != test.fatal {
+			t.Fatalf("#%v: got %q count=%v recursive=%v fatal=%v, want %q count=%v recursive=%v fatal=%v",
+				i, rep.Title, rep.OopsCount, rep.Recursive, rep.Fatal,
+				test.title, test.count, test.recursive, test.fatal)
+		}
+	}
+}
+
+func TestLinuxParseText(t *testing.T) {
+	tests := map[string]string{
+		`mmap(&(0x7f00008dd000/0x1000)=nil, (0x1000), 0x3, 0x32, 0xffffffffffffffff, 0x0)
+getsockopt$NETROM_N2(r2, 0x103, 0x3, &(0x7f00008de000-0x4)=0x1, &(0x7f00008dd000)=0x4)
+[  522.560667] nla_parse: 5 callbacks suppressed
+[  522.565344] netlink: 3 bytes leftover after parsing attributes in process 'syz-executor5'.
+[  536.429346] NMI watchdog: BUG: soft lockup - CPU#1 stuck for 11s! [syz-executor7:16813]
+mmap(&(0x7f0000557000/0x2000)=nil, (0x2000), 0x1, 0x11, r2, 0x1b)
+[  536.437530] Modules linked in:
+[  536.440808] CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.3.5-smp-DEV #119`: `nla_parse: 5 callbacks suppressed
+netlink: 3 bytes leftover after parsing attributes in process 'syz-executor5'.
+NMI watchdog: BUG: soft lockup - CPU#1 stuck for 11s! [syz-executor7:16813]
+Modules linked in:
+CPU: 1 PID: 16813 Comm: syz-executor7 Not tainted 4.3.5-smp-DEV #119
+`,
+
+		// Raw 'dmesg -r' and /proc/kmsg output.
+		`<6>[   85.501187] WARNING: foo
+<6>[   85.501187] nouveau  [     DRM] suspending kernel object tree...
+executing program 1:
+<6>[   85.525111] nouveau  [     DRM] nouveau suspended
+<14>[   85.912347] init: computing context for service 'clear-bcb'`: `WARNING: foo
+nouveau  [     DRM] suspending kernel object tree...
+nouveau  [     DRM] nouveau suspended
+init: computing context for service 'clear-bcb'
+`,
+
+		`[   94.864848] line 0
+[   94.864848] line 1
+[   94.864848] line 2
+[   94.864848] line 3
+[   94.864848] line 4
+[   94.864848] line 5
+[   95.145581] ==================================================================
+[   95.152992] BUG: KASAN: use-after-free in snd_seq_queue_alloc+0x670/0x690 at addr ffff8801d0c6b080
+[   95.162080] Read of size 4 by task syz-executor2/5764`: `line 2
+line 3
+line 4
+line 5
+==================================================================
+BUG: KASAN: use-after-free in snd_seq_queue_alloc+0x670/0x690 at addr ffff8801d0c6b080
+Read of size 4 by task syz-executor2/5764
+`,
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for log, text0 := range tests {
+		if rep := reporter.Parse([]byte(log)); string(rep.Report) != text0 {
+			t.Logf("log:\n%s", log)
+			t.Logf("want text:\n%s", text0)
+			t.Logf("got text:\n%s", rep.Report)
+			t.Fatalf("bad text, desc: '%v'", rep.Title)
+		}
+	}
+}
+
+func TestLinuxSymbolizeLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		result string
+	}{
+		// Normal symbolization.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x101/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x101/0x185 foo.c:555\n",
+		},
+		{
+			"RIP: 0010:[<ffffffff8188c0e6>]  [<ffffffff8188c0e6>]  foo+0x101/0x185\n",
+			"RIP: 0010:[<ffffffff8188c0e6>]  [<ffffffff8188c0e6>]  foo+0x101/0x185 foo.c:555\n",
+		},
+		// Strip "./" file prefix.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x111/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x111/0x185 foo.h:111\n",
+		},
+		// Needs symbolization, but symbolizer returns nothing.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x121/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x121/0x185\n",
+		},
+		// Needs symbolization, but symbolizer returns error.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x131/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] foo+0x131/0x185\n",
+		},
+		// Needs symbolization, but symbol is missing.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0x131/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0x131/0x185\n",
+		},
+		// Bad offset.
+		{
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0xffffffffffffffffffff/0x185\n",
+			"[ 2713.153531]  [<ffffffff82d1b1d9>] bar+0xffffffffffffffffffff/0x185\n",
+		},
+		// Should not be symbolized.
+		{
+			"WARNING: CPU: 2 PID: 2636 at ipc/shm.c:162 foo+0x101/0x185\n",
+			"WARNING: CPU: 2 PID: 2636 at ipc/shm.c:162 foo+0x101/0x185 foo.c:555\n",
+		},
+		// Tricky function name.
+		{
 			"    [<ffffffff84e5bea0>] do_ipv6_setsockopt.isra.7.part.3+0x101/0x2830 \n",
 			"    [<ffffffff84e5bea0>] do_ipv6_setsockopt.isra.7.part.3+0x101/0x2830 net.c:111 \n",
 		},
+		// riscv faulting pc is not a return address.
+		{
+			"[   56.272017] epc : foo+0x100/0x185\n",
+			"[   56.272017] epc : foo+0x100/0x185 foo.c:555\n",
+		},
+		{
+			"[   56.272017]  ra : foo+0x101/0x185\n",
+			"[   56.272017]  ra : foo+0x101/0x185 foo.c:555\n",
+		},
 		// Old KASAN frame format (with tab).
 		{
 			"[   50.419727] 	baz+0x101/0x200\n",
@@ -3085,6 +4586,24 @@ DR3: 0000000000000000 DR6: 00000000fffe0ff0 DR7: 0000000000000400
 Call Trace:
   blkcipher_walk_done+0x72b/0xde0 crypto/blkcipher.c:139
 `: `crypto/blkcipher.c`,
+		`
+BUG: KASAN: use-after-free in tcp_v4_rcv+0x47/0x80
+Call Trace:
+ <IRQ>
+ ? sctp_ulpevent_free+0x16/0x70 net/sctp/ulpevent.c:1033
+ ? ip6_finish_output2+0x8a3/0x1c40 net/ipv6/ip6_output.c:120
+ tcp_v4_rcv+0x47/0x80 net/ipv4/tcp_ipv4.c:1595
+ __do_softirq+0x2d7/0xb85 kernel/softirq.c:285
+ </IRQ>
+`: `net/ipv4/tcp_ipv4.c`,
+		`
+BUG: unable to handle kernel paging request at ffff8801c6947b80
+Call Trace:
+ <IRQ>
+ ? dst_destroy+0x9c/0x310 net/core/dst.c:108
+ __do_softirq+0x2d7/0xb85 kernel/softirq.c:285
+ </IRQ>
+`: `net/core/dst.c`,
 	}
 	reporter, err := NewReporter("linux", "", "", nil, nil)
 	if err != nil {
@@ -3100,3 +4619,2937 @@ Call Trace:
 		}
 	}
 }
+
+func TestLinuxGuiltyFrame(t *testing.T) {
+	const log = `
+[   95.152992] BUG: KASAN: use-after-free in skb_clone+0x3a2/0x420 net/core/skbuff.c:1029 at addr ffff88003b910d8c
+[   95.162080] Read of size 4 by task syz-executor0/5591
+[   95.162080] CPU: 1 PID: 5591 Comm: syz-executor0 Not tainted 4.10.0-rc8+ #201
+[   95.162080] Call Trace:
+[   95.162080]  <IRQ>
+[   95.162080]  __dump_stack lib/dump_stack.c:15 [inline]
+[   95.162080]  dump_stack+0x292/0x398 lib/dump_stack.c:51
+[   95.162080]  kasan_report.part.1+0x20e/0x4e0 mm/kasan/report.c:311
+[   95.162080]  skb_pfmemalloc include/linux/skbuff.h:829 [inline]
+[   95.162080]  skb_clone+0x3a2/0x420 net/core/skbuff.c:1029
+[   95.162080]  dccp_v6_request_recv_sock+0xb5e/0x1960 net/dccp/ipv6.c:527
+[   95.162080]  </IRQ>
+[   95.162080]  dccp_check_req+0x335/0x5a0 net/dccp/minisocks.c:186
+[   95.162080] 
+[   95.162080] Allocated by task 5591:
+[   95.162080]  kmalloc+0x11/0x22 mm/slab.c:100
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if len(rep.Frames) != 7 || rep.GuiltyFrame != -1 {
+		t.Fatalf("bad parsed frames: %+v, guilty frame %v", rep.Frames, rep.GuiltyFrame)
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	want := StackFrame{
+		Func:   "dccp_v6_request_recv_sock",
+		Offset: 0xb5e,
+		Size:   0x1960,
+		File:   "net/dccp/ipv6.c",
+		Line:   527,
+	}
+	if rep.GuiltyFile != want.File {
+		t.Fatalf("got guilty file %q, want %q", rep.GuiltyFile, want.File)
+	}
+	if rep.GuiltyFrame != 5 || rep.Frames[rep.GuiltyFrame] != want {
+		t.Fatalf("got guilty frame %v (%+v), want 5 (%+v)", rep.GuiltyFrame, rep.Frames, want)
+	}
+	if frame := rep.Frames[3]; !frame.Inline || frame.Func != "skb_pfmemalloc" {
+		t.Fatalf("bad inline frame: %+v", frame)
+	}
+}
+
+func TestLinuxRcuStallStacks(t *testing.T) {
+	const log = `
+[  277.781045] INFO: rcu_sched detected stalls on CPUs/tasks:
+[  277.781153] 	1-...: (65000 ticks this GP) idle=395/140000000000001/0 softirq=122875/122875 fqs=16248 
+[  277.781153] 	3-...: (1 GPs behind) idle=c01/140000000000000/0 softirq=8771/8772 fqs=16248 
+[  277.781197] 	(detected by 0, t=65002 jiffies, g=72940, c=72939, q=1777)
+[  277.781212] NMI backtrace for cpu 0
+[  277.781212] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 4.15.0-rc5+ #180
+[  277.781212] Call Trace:
+[  277.781212]  <IRQ>
+[  277.781212]  dump_stack+0x194/0x257
+[  277.781212]  nmi_cpu_backtrace+0x1d2/0x210
+[  277.781212]  rcu_dump_cpu_stacks+0x186/0x1de
+[  277.781212]  rcu_check_callbacks+0x1c5b/0x2110
+[  277.781212]  </IRQ>
+[  277.781212]  default_idle+0x1f/0x40
+[  277.781212] Sending NMI from CPU 0 to CPUs 1:
+[  277.782014] NMI backtrace for cpu 1
+[  277.782014] CPU: 1 PID: 12579 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #180
+[  277.782014] RIP: 0010:io_serial_in+0x6b/0x90
+[  277.782014] Call Trace:
+[  277.782014]  <IRQ>
+[  277.782014]  wait_for_xmitr+0x89/0x1c0
+[  277.782014]  serial8250_console_putchar+0x1f/0x60
+[  277.782014]  apic_timer_interrupt+0x93/0xa0
+[  277.782014] RIP: 0010:debug_lockdep_rcu_enabled.part.19+0xf/0x60
+[  277.782014] RSP: 0018:ffff8801cd596778 EFLAGS: 00000202 ORIG_RAX: ffffffffffffff10
+[  277.782014]  </IRQ>
+[  277.782014]  debug_lockdep_rcu_enabled+0x77/0x90
+[  277.782014]  __sctp_write_space+0x5b6/0x920
+[  277.782014] Sending NMI from CPU 0 to CPUs 3:
+[  277.782014] NMI backtrace for cpu 3
+[  277.782014] CPU: 3 PID: 12580 Comm: syz-executor1 Not tainted 4.15.0-rc5+ #180
+[  277.782014] Call Trace:
+[  277.782014]  _raw_spin_lock+0x32/0x40
+[  277.782014]  sctp_sendmsg+0x111/0x2220
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if want := "INFO: rcu detected stall in __sctp_write_space"; rep.Title != want {
+		t.Fatalf("got title %q, want %q", rep.Title, want)
+	}
+	if len(rep.AuxStacks) != 3 {
+		t.Fatalf("want 3 aux stacks, got %+v", rep.AuxStacks)
+	}
+	for i, title := range []string{"NMI backtrace for cpu 0", "NMI backtrace for cpu 1", "NMI backtrace for cpu 3"} {
+		if rep.AuxStacks[i].Title != title {
+			t.Fatalf("aux stack #%v: got title %q, want %q", i, rep.AuxStacks[i].Title, title)
+		}
+	}
+	var funcs []string
+	for _, frame := range rep.Frames {
+		funcs = append(funcs, frame.Func)
+	}
+	want := "wait_for_xmitr serial8250_console_putchar apic_timer_interrupt " +
+		"debug_lockdep_rcu_enabled __sctp_write_space"
+	if got := strings.Join(funcs, " "); got != want {
+		t.Fatalf("got frames %q, want %q", got, want)
+	}
+}
+
+func TestLinuxSymbolizeMaxFrames(t *testing.T) {
+	symbols := map[string][]symbolizer.Symbol{
+		"foo": []symbolizer.Symbol{
+			{Addr: 0x1000000, Size: 0x190},
+		},
+	}
+	symb := func(bin string, pc uint64) ([]symbolizer.Frame, error) {
+		return []symbolizer.Frame{{File: "/linux/foo.c", Line: 555}}, nil
+	}
+	text := []byte(strings.Repeat(" foo+0x101/0x185\n", 5))
+	symbolized, tooDeep := symbolizeLines(symb, symbols, "vmlinux", "/linux/", text, 5)
+	if tooDeep || bytes.Count(symbolized, []byte("foo.c:555")) != 5 {
+		t.Fatalf("bad symbolization (too deep: %v):\n%s", tooDeep, symbolized)
+	}
+	symbolized, tooDeep = symbolizeLines(symb, symbols, "vmlinux", "/linux/", text, 3)
+	if !tooDeep || bytes.Count(symbolized, []byte("foo.c:555")) != 3 {
+		t.Fatalf("bad symbolization (too deep: %v):\n%s", tooDeep, symbolized)
+	}
+}
+
+func TestLinuxHeldLocks(t *testing.T) {
+	const log = `
+[  246.809325] INFO: task syz-executor5:7576 blocked for more than 120 seconds.
+[  246.816588]       Not tainted 4.15.0-rc5+ #180
+[  246.821162] "echo 0 > /proc/sys/kernel/hung_task_timeout_secs" disables this message.
+[  246.829154] syz-executor5   D24488  7576   3583 0x00000004
+[  246.834695] Call Trace:
+[  246.837292]  __schedule+0x8eb/0x2060
+[  246.841011]  schedule+0xf5/0x430
+[  246.844388]  __rwsem_down_write_failed_common+0x7a7/0x1510
+[  246.850148]  rwsem_down_write_failed+0xe/0x10
+[  246.854618]  call_rwsem_down_write_failed+0x17/0x30
+[  246.859619]  down_write+0x87/0x120
+[  246.863218]  vm_mmap_pgoff+0x162/0x200
+[  246.867149]  SyS_mmap_pgoff+0x462/0x5f0
+[  246.881274] 
+[  246.881274] Showing all locks held in the system:
+[  246.887626] 2 locks held by khungtaskd/671:
+[  246.891981]  #0:  (rcu_read_lock){....}, at: [<ffffffff8160d6a0>] check_hung_uninterruptible_tasks kernel/hung_task.c:175 [inline]
+[  246.891981]  #0:  (rcu_read_lock){....}, at: [<ffffffff8160d6a0>] watchdog+0x1c0/0xd60 kernel/hung_task.c:249
+[  246.904615]  #1:  (tasklist_lock){.+.+}, at: [<ffffffff8146f1ff>] debug_show_all_locks+0x7f/0x270 kernel/locking/lockdep.c:4470
+[  246.916490] 1 lock held by rsyslogd/3381:
+[  246.920676]  #0:  ffff8801d4d7a0d0 (&f->f_pos_lock){+.+.}-{3:3}, at: __fdget_pos+0x12c/0x1a0 fs/file.c:765
+[  246.929005] 
+[  246.930619] =============================================
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatal("no report")
+	}
+	want := []HeldLock{
+		{"khungtaskd", 671, "rcu_read_lock", "check_hung_uninterruptible_tasks"},
+		{"khungtaskd", 671, "tasklist_lock", "debug_show_all_locks"},
+		{"rsyslogd", 3381, "&f->f_pos_lock", "__fdget_pos"},
+	}
+	if !reflect.DeepEqual(rep.HeldLocks, want) {
+		t.Fatalf("bad held locks:\n%+v\nwant:\n%+v", rep.HeldLocks, want)
+	}
+	rep = reporter.Parse([]byte("[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40\n" +
+		"[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30\n"))
+	if rep == nil || rep.HeldLocks != nil {
+		t.Fatalf("expected nil held locks, got %+v", rep)
+	}
+}
+
+func TestLinuxSymbolizeCancel(t *testing.T) {
+	symbols := map[string][]symbolizer.Symbol{
+		"foo": []symbolizer.Symbol{
+			{Addr: 0x1000000, Size: 0x190},
+		},
+	}
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	symb := func(bin string, pc uint64) ([]symbolizer.Frame, error) {
+		calls++
+		if calls == 2 {
+			// Cancel in the middle of symbolization.
+			cancel()
+		}
+		return []symbolizer.Frame{{File: "/linux/foo.c", Line: 555}}, nil
+	}
+	text := []byte(strings.Repeat(" foo+0x101/0x185\n", 5))
+	symbolized, _ := symbolizeLines(symbolizeWithContext(c, symb), symbols, "vmlinux", "/linux/", text, 5)
+	if calls != 2 || bytes.Count(symbolized, []byte("foo.c:555")) != 2 ||
+		bytes.Count(symbolized, []byte("foo+0x101/0x185")) != 5 {
+		t.Fatalf("bad partial symbolization (%v calls):\n%s", calls, symbolized)
+	}
+}
+
+func TestLinuxRedactors(t *testing.T) {
+	opts := Options{
+		Redactors: []*regexp.Regexp{regexp.MustCompile(`/home/[a-z]+`)},
+	}
+	reporter, err := NewReporterOptions("linux", "", "", nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const log = `
+[    2.932505] VFS: Cannot open root device "/home/alice/image" or unknown-block(0,0): error -6
+[    2.935367] Kernel panic - not syncing: VFS: Unable to mount root fs on /home/alice/image
+`
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if want := "kernel panic: VFS: Unable to mount root fs on ***********/image"; rep.Title != want {
+		t.Fatalf("got title %q, want %q", rep.Title, want)
+	}
+	if len(rep.Output) != len(log) {
+		t.Fatalf("redacted output length %v, want %v", len(rep.Output), len(log))
+	}
+	if bytes.Contains(rep.Output, []byte("alice")) || bytes.Contains(rep.Report, []byte("alice")) {
+		t.Fatalf("sensitive data is not redacted:\n%s\n%s", rep.Output, rep.Report)
+	}
+	if !strings.Contains(string(rep.Output[rep.StartPos:rep.EndPos]), "Kernel panic") {
+		t.Fatalf("bad report region: %q", rep.Output[rep.StartPos:rep.EndPos])
+	}
+}
+
+func TestLinuxCollapseFrames(t *testing.T) {
+	log := `
+[  166.251036] BUG: stack guard page was hit at ffffc90000c97ff8 (stack is ffffc90000c98000..ffffc90000c9ffff)
+[  166.251046] kernel stack overflow (double-fault): 0000 [#1] SMP KASAN
+[  166.251052] CPU: 1 PID: 13869 Comm: syz-executor6 Not tainted 4.15.0-rc4+ #1
+[  166.251060] Call Trace:
+[  166.251060]  memcpy+0x11/0x20 mm/kasan/kasan.c:303
+` + strings.Repeat("[  166.251060]  recurse+0x32/0x50 fs/foo.c:10\n", 500) +
+		`[  166.251060]  foo_ioctl+0x55/0x70 fs/foo.c:20
+`
+	opts := Options{CollapseFrames: true}
+	reporter, err := NewReporterOptions("linux", "", "", nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if len(rep.Frames) != 502 {
+		t.Fatalf("want 502 frames before Symbolize, got %v", len(rep.Frames))
+	}
+	text := append([]byte{}, rep.Report...)
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Frames) != 3 {
+		t.Fatalf("want 3 frames, got %+v", rep.Frames)
+	}
+	for i, want := range []struct {
+		fn     string
+		repeat int
+	}{{"memcpy", 1}, {"recurse", 500}, {"foo_ioctl", 1}} {
+		if frame := rep.Frames[i]; frame.Func != want.fn || frame.Repeat != want.repeat {
+			t.Fatalf("frame #%v: got %v (x%v), want %v (x%v)",
+				i, frame.Func, frame.Repeat, want.fn, want.repeat)
+		}
+	}
+	if rep.GuiltyFile != "fs/foo.c" || rep.GuiltyFrame != 1 {
+		t.Fatalf("bad guilty file/frame: %v/%v", rep.GuiltyFile, rep.GuiltyFrame)
+	}
+	if !bytes.Equal(text, rep.Report) {
+		t.Fatalf("report text has changed")
+	}
+}
+
+func TestLinuxExecutorCrash(t *testing.T) {
+	tests := []struct {
+		log      string
+		executor bool
+	}{
+		{`
+[   96.862470] IPVS: ftp: loaded support on port[0] = 21
+panic: executor 3: failed: pthread_create failed (errno 11)
+
+goroutine 25 [running]:
+main.(*Proc).executeRaw(0xc4201ea000, 0xc420334000, 0xc42028e000, 0xc42000000a)
+`, true},
+		{`
+[ 1019.110825] BUG: unable to handle kernel paging request at 000000010000001a
+[ 1019.112065] IP: skb_release_data+0x258/0x470
+panic: executor 3: failed: pthread_create failed (errno 11)
+`, false},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.ExecutorCrash != test.executor {
+			t.Fatalf("#%v: ExecutorCrash=%v, want %v", i, rep.ExecutorCrash, test.executor)
+		}
+		if test.executor && !bytes.HasPrefix(rep.Report, []byte("panic: executor")) {
+			t.Fatalf("#%v: bad report:\n%s", i, rep.Report)
+		}
+	}
+}
+
+func TestLinuxTaskInfo(t *testing.T) {
+	tests := []struct {
+		log     string
+		comm    string
+		taint   string
+		version string
+	}{
+		{`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] CPU: 1 PID: 4070 Comm: syz-executor Not tainted 4.8.0-rc3+ #33
+[  772.919010] Hardware name: QEMU Standard PC (i440FX + PIIX, 1996), BIOS Bochs 01/01/2011
+`, "syz-executor", "", "4.8.0-rc3+"},
+		{`
+[   88.046885] WARNING: CPU: 0 PID: 3104 at net/core/dev.c:2612 skb_warn_bad_offload+0x2bc/0x3a0
+[   88.051167] CPU: 0 PID: 3104 Comm: kworker/0:2 Tainted: G    B   W       4.15.0+ #1
+[   88.051167] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+`, "kworker/0:2", "GBW", "4.15.0+"},
+		{`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+`, "", "", ""},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Comm != test.comm || rep.Taint != test.taint || rep.KernelVersion != test.version {
+			t.Fatalf("#%v: got comm=%q taint=%q version=%q, want comm=%q taint=%q version=%q",
+				i, rep.Comm, rep.Taint, rep.KernelVersion, test.comm, test.taint, test.version)
+		}
+	}
+}
+
+func TestLinuxDisableFormats(t *testing.T) {
+	log := `
+[   67.392094] ==================================================================
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392160] UBSAN: Undefined behaviour in drivers/usb/core/devio.c:1517:25
+`
+	tests := []struct {
+		disabled []string
+		title    string
+	}{
+		{nil, "KASAN: use-after-free Read in ip6_dst_store"},
+		{[]string{"kasan"}, "KASAN: use-after-free in ip6_dst_store include/net/ip6_fib.h:LINE"},
+		// No matching formats left, so the title is the oops header line.
+		{[]string{"kasan", "kasan-generic"}, "BUG: KASAN: use-after-free in ip6_dst_store include/net/ip6_fib.h:LINE"},
+		{[]string{"ubsan"}, "KASAN: use-after-free Read in ip6_dst_store"},
+	}
+	for i, test := range tests {
+		var opts Options
+		opts.DisableFormats(test.disabled...)
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := reporter.Parse([]byte(log))
+		if rep == nil || rep.Title != test.title {
+			t.Fatalf("#%v: got report %+v, want title %q", i, rep, test.title)
+		}
+	}
+	// UBSAN oops has no other formats, so it's not detected at all.
+	var opts Options
+	opts.DisableFormats("ubsan")
+	reporter, err := NewReporterOptions("linux", "", "", nil, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reporter.ContainsCrash([]byte("[   67.392160] UBSAN: Undefined behaviour in foo.c:1:2\n")) {
+		t.Fatalf("disabled UBSAN format is detected")
+	}
+	opts.DisableFormats("no-such-format")
+	if _, err := NewReporterOptions("linux", "", "", nil, nil, opts); err == nil {
+		t.Fatalf("no error for unknown format")
+	}
+}
+
+func TestLinuxConfidenceAndFormat(t *testing.T) {
+	tests := []struct {
+		log        string
+		confidence float64
+		format     string
+	}{
+		{`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+[   67.392150]  udpv6_sendmsg+0x1a39/0x2b60 net/ipv6/udp.c:1341
+`, ConfidenceFormat, "kasan"},
+		{`
+[   67.392145] UBSAN: 
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+[   67.392150]  udpv6_sendmsg+0x1a39/0x2b60 net/ipv6/udp.c:1341
+`, ConfidencePartialFormat, "ubsan"},
+		{`
+[   67.392145] BUG: something new
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+[   67.392150]  udpv6_sendmsg+0x1a39/0x2b60 net/ipv6/udp.c:1341
+`, ConfidenceHeader, ""},
+		{`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+`, ConfidenceCorrupted, "kasan"},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Confidence != test.confidence {
+			t.Fatalf("#%v: confidence %v, want %v (title %q, corrupted %q)",
+				i, rep.Confidence, test.confidence, rep.Title, rep.CorruptedReason)
+		}
+		if rep.MatchedFormat != test.format {
+			t.Fatalf("#%v: matched format %q, want %q", i, rep.MatchedFormat, test.format)
+		}
+	}
+}
+
+func TestLinuxTruncatedHead(t *testing.T) {
+	tests := []struct {
+		log   string
+		title string
+	}{
+		// Output starts in the middle of a backtrace.
+		{`0x3a/0x50 net/socket.c:2100
+[  150.103055]  __sys_sendmsg+0xe5/0x210 net/socket.c:2137
+[  150.103055]  SyS_sendmsg+0x2d/0x50 net/socket.c:2144
+[  150.103055]  entry_SYSCALL_64_fastpath+0x1f/0x96
+[  150.103055] RIP: 0033:0x4512e9
+[  150.103055] RSP: 002b:00007f0a3e5c4c08 EFLAGS: 00000216 ORIG_RAX: 000000000000002e
+[  150.103055] ---[ end trace 9a3c1ad2a8b6e8c5 ]---
+`, "truncated oops in __sys_sendmsg"},
+		// Output starts in the middle of a register dump.
+		{`[  150.103055] RIP: 0010:[<ffffffff8456b026>]  [<ffffffff8456b026>] ip6_dst_store+0x4e4/0x520
+[  150.103055] RSP: 0018:ffff880066befc88  EFLAGS: 00010006
+[  150.103055] Kernel Offset: disabled
+`, "truncated oops in ip6_dst_store"},
+		// No end of oops marker (e.g. a stack dump due to fault injection).
+		{`[  150.103055]  __sys_sendmsg+0xe5/0x210 net/socket.c:2137
+[  150.103055]  SyS_sendmsg+0x2d/0x50 net/socket.c:2144
+[  150.103055]  entry_SYSCALL_64_fastpath+0x1f/0x96
+`, ""},
+		// Output does not start with an oops part.
+		{`[  150.103055] random kernel message
+[  150.103055]  __sys_sendmsg+0xe5/0x210 net/socket.c:2137
+[  150.103055] ---[ end trace 9a3c1ad2a8b6e8c5 ]---
+`, ""},
+	}
+	reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{TruncatedHeads: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultReporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		// By default Parse agrees with ContainsCrash.
+		if rep := defaultReporter.Parse([]byte(test.log)); rep != nil {
+			t.Fatalf("#%v: got report %q without Options.TruncatedHeads", i, rep.Title)
+		}
+		if title := defaultReporter.Title([]byte(test.log)); title != "" {
+			t.Fatalf("#%v: got title %q without Options.TruncatedHeads", i, title)
+		}
+		if title := reporter.Title([]byte(test.log)); title != test.title {
+			t.Fatalf("#%v: Title returned %q, want %q", i, title, test.title)
+		}
+		rep := reporter.Parse([]byte(test.log))
+		if test.title == "" {
+			if rep != nil {
+				t.Fatalf("#%v: unexpected report %q", i, rep.Title)
+			}
+			continue
+		}
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title {
+			t.Fatalf("#%v: got title %q, want %q", i, rep.Title, test.title)
+		}
+		if !rep.Corrupted || rep.CorruptedReason != "truncated head" {
+			t.Fatalf("#%v: corrupted=%v reason=%q", i, rep.Corrupted, rep.CorruptedReason)
+		}
+		if rep.EndPos != len(test.log)-1 {
+			t.Fatalf("#%v: EndPos=%v, want %v", i, rep.EndPos, len(test.log)-1)
+		}
+	}
+}
+
+func TestLinuxPageFault(t *testing.T) {
+	tests := []struct {
+		log                                 string
+		pageFault, prot, write, user, instr bool
+	}{
+		{`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+[  772.919010] Oops: 0002 [#1] SMP DEBUG_PAGEALLOC KASAN
+[  772.919010] RIP: 0010:[<ffffffff82d4e304>]  [<ffffffff82d4e304>] __memset+0x24/0x30
+`, true, false, true, false, false},
+		{`
+[ 1019.110825] BUG: unable to handle kernel NULL pointer dereference at 0000000000000000
+[ 1019.110825] IP:           (null)
+[ 1019.110825] Oops: 0010 [#1] SMP KASAN
+[ 1019.110825] RIP: 0010:          (null)
+`, true, false, false, false, true},
+		{`
+[ 1019.110825] BUG: unable to handle kernel paging request at ffffc90001b3a000
+[ 1019.110825] IP: strnlen+0x13/0x40
+[ 1019.110825] Oops: 0001 [#1] SMP
+[ 1019.110825] EIP: strnlen+0x13/0x40
+`, true, true, false, false, false},
+		// arm64 reports ESR, not x86 error code.
+		{`
+[  102.650094] Unable to handle kernel paging request at virtual address ffff800000000008
+[  102.650094] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[  102.650094] PC is at __d_lookup_rcu+0x38/0x1a8
+`, false, false, false, false, false},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.PageFault != test.pageFault || rep.FaultProtection != test.prot ||
+			rep.FaultWrite != test.write || rep.FaultUser != test.user ||
+			rep.FaultInstruction != test.instr {
+			t.Fatalf("#%v: bad page fault info: %v/%v/%v/%v/%v", i, rep.PageFault,
+				rep.FaultProtection, rep.FaultWrite, rep.FaultUser, rep.FaultInstruction)
+		}
+	}
+}
+
+func TestLinuxParseMalformed(t *testing.T) {
+	// These inputs used to cause panics during parsing.
+	tests := []ParseTest{
+		{
+			// Oops is not in console output.
+			"BUG: foo\n",
+			"BUG: foo",
+			true,
+		},
+		{
+			"[    1.000000] foo\nBUG: foo\n[    1.000000] bar\n",
+			"BUG: foo",
+			true,
+		},
+		{
+			// Less than 10 lines after Call Trace.
+			`[    1.000000] BUG: KASAN: use-after-free
+[    1.000000] Call Trace:
+[    1.000000] a
+[    1.000000] b
+[    1.000000] c
+`,
+			"KASAN: use-after-free",
+			true,
+		},
+	}
+	testParse(t, "linux", tests)
+}
+
+func TestLinuxStackMarkers(t *testing.T) {
+	tests := []struct {
+		log    string
+		title  string
+		frames []string
+	}{
+		{
+			// Old kernels: stack ends with the first non-frame line.
+			log: `
+[   62.563013] WARNING: CPU: 1 PID: 4321 at net/core/dev.c:1234 dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013] Kernel panic - not syncing: panic_on_warn set ...
+[   62.563013] CPU: 1 PID: 4321 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #180
+[   62.563013] Call Trace:
+[   62.563013]  <IRQ>
+[   62.563013]  __dump_stack lib/dump_stack.c:17 [inline]
+[   62.563013]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[   62.563013]  </IRQ>
+[   62.563013]  dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013]  sock_ioctl+0x2ac/0x4e0 net/socket.c:1011
+[   62.563013]  entry_SYSCALL_64_fastpath+0x1f/0x96
+[   62.563013] RIP: 0033:0x452df9
+[   62.563013] Kernel Offset: disabled
+`,
+			title:  "WARNING in dev_foo",
+			frames: []string{"__dump_stack", "dump_stack", "dev_foo", "sock_ioctl", "entry_SYSCALL_64_fastpath"},
+		},
+		{
+			// 5.18+ kernels: stack is enclosed in <TASK>/</TASK> markers and
+			// is followed by user-space registers and other tasks.
+			log: `
+[   62.563013] WARNING: CPU: 1 PID: 4321 at net/core/dev.c:1234 dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013] Modules linked in:
+[   62.563013] CPU: 1 PID: 4321 Comm: syz-executor.0 Not tainted 5.19.0-rc1-syzkaller #0
+[   62.563013] RIP: 0010:dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013] Call Trace:
+[   62.563013]  <IRQ>
+[   62.563013]  __dump_stack lib/dump_stack.c:88 [inline]
+[   62.563013]  dump_stack_lvl+0xcd/0x134 lib/dump_stack.c:106
+[   62.563013]  </IRQ>
+[   62.563013]  <TASK>
+[   62.563013]  dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013]  sock_ioctl+0x2ac/0x4e0 net/socket.c:1011
+[   62.563013]  do_syscall_64+0x35/0xb0 arch/x86/entry/common.c:80
+[   62.563013]  entry_SYSCALL_64_after_hwframe+0x46/0xb0
+[   62.563013]  </TASK>
+[   62.563013] task:syz-executor.1  state:D stack:28184 pid: 4322
+[   62.563013]  schedule+0xd2/0x1f0 kernel/sched/core.c:6495
+`,
+			title: "WARNING in dev_foo",
+			frames: []string{"__dump_stack", "dump_stack_lvl", "dev_foo", "sock_ioctl",
+				"do_syscall_64", "entry_SYSCALL_64_after_hwframe"},
+		},
+		{
+			// The markers also need to be skipped when extracting titles from stacks.
+			log: `
+[  246.809325] INFO: task syz-executor.5:7576 blocked for more than 143 seconds.
+[  246.816588]       Not tainted 5.19.0-rc1-syzkaller #0
+[  246.821162] "echo 0 > /proc/sys/kernel/hung_task_timeout_secs" disables this message.
+[  246.829154] task:syz-executor.5  state:D stack:24488 pid: 7576 ppid:  3583 flags:0x00004004
+[  246.834695] Call Trace:
+[  246.837292]  <TASK>
+[  246.837292]  context_switch kernel/sched/core.c:5146 [inline]
+[  246.837292]  __schedule+0x8eb/0x2060 kernel/sched/core.c:6458
+[  246.841011]  schedule+0xf5/0x430 kernel/sched/core.c:6530
+[  246.859619]  down_write+0x87/0x120 kernel/locking/rwsem.c:1552
+[  246.863218]  vm_mmap_pgoff+0x162/0x200 mm/util.c:519
+[  246.867149]  ksys_mmap_pgoff+0x462/0x5f0 mm/mmap.c:1624
+[  246.867149]  </TASK>
+`,
+			title: "INFO: task hung in down_write",
+			frames: []string{"context_switch", "__schedule", "schedule", "down_write",
+				"vm_mmap_pgoff", "ksys_mmap_pgoff"},
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title {
+			t.Errorf("#%v: got title %q, want %q", i, rep.Title, test.title)
+		}
+		var frames []string
+		for _, frame := range rep.Frames {
+			frames = append(frames, frame.Func)
+		}
+		if !reflect.DeepEqual(frames, test.frames) {
+			t.Errorf("#%v: got frames %q, want %q", i, frames, test.frames)
+		}
+	}
+}
+
+const linuxBenchmarkLog = `
+[   45.123456] ==================================================================
+[   45.123456] BUG: KASAN: use-after-free in skb_pfmemalloc include/linux/skbuff.h:829 [inline]
+[   45.123456] BUG: KASAN: use-after-free in skb_clone+0x1a0/0x1c0 net/core/skbuff.c:1238
+[   45.123456] Read of size 4 at addr ffff8801c6a1a080 by task syz-executor0/12345
+[   45.123456] 
+[   45.123456] CPU: 1 PID: 12345 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #180
+[   45.123456] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   45.123456] Call Trace:
+[   45.123456]  __dump_stack lib/dump_stack.c:17 [inline]
+[   45.123456]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[   45.123456]  print_address_description+0x73/0x250 mm/kasan/report.c:252
+[   45.123456]  kasan_report_error mm/kasan/report.c:351 [inline]
+[   45.123456]  kasan_report+0x25b/0x340 mm/kasan/report.c:409
+[   45.123456]  skb_pfmemalloc include/linux/skbuff.h:829 [inline]
+[   45.123456]  skb_clone+0x1a0/0x1c0 net/core/skbuff.c:1238
+[   45.123456]  dccp_v6_request_recv_sock+0xc2a/0x1e00 net/dccp/ipv6.c:471
+[   45.123456]  dccp_check_req+0x3c1/0x5c0 net/dccp/minisocks.c:186
+[   45.123456]  dccp_v4_rcv+0x773/0x1800 net/dccp/ipv4.c:875
+[   45.123456]  ip_local_deliver_finish+0x2fd/0xa10 net/ipv4/ip_input.c:216
+[   45.123456]  entry_SYSCALL_64_fastpath+0x1f/0x96
+[   45.123456] 
+[   45.123456] Allocated by task 12345:
+[   45.123456]  save_stack+0x43/0xd0 mm/kasan/kasan.c:447
+[   45.123456]  kmem_cache_alloc+0x12e/0x760 mm/slab.c:3541
+[   45.123456] 
+[   45.123456] Freed by task 12345:
+[   45.123456]  save_stack+0x43/0xd0 mm/kasan/kasan.c:447
+[   45.123456]  kmem_cache_free+0x77/0x280 mm/slab.c:3745
+[   45.123456] 
+[   45.123456] The buggy address belongs to the object at ffff8801c6a1a000
+[   45.123456] ==================================================================
+`
+
+func benchmarkLinux(b *testing.B, fn func(reporter Reporter, log []byte)) {
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	log := []byte(strings.Repeat("[   44.000000] random kernel output\nfuzzer output\n", 100) + linuxBenchmarkLog)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fn(reporter, log)
+	}
+}
+
+func BenchmarkLinuxParse(b *testing.B) {
+	benchmarkLinux(b, func(reporter Reporter, log []byte) { reporter.Parse(log) })
+}
+
+func BenchmarkLinuxTitle(b *testing.B) {
+	benchmarkLinux(b, func(reporter Reporter, log []byte) { reporter.Title(log) })
+}
+
+func TestLinuxSyscall(t *testing.T) {
+	tests := []struct {
+		frames  []string
+		syscall string
+	}{
+		{[]string{"foo", "__do_sys_ioctl", "__se_sys_ioctl", "__x64_sys_ioctl", "do_syscall_64"}, "ioctl"},
+		{[]string{"foo", "__se_sys_mmap_pgoff", "ksys_mmap_pgoff"}, "mmap_pgoff"},
+		{[]string{"foo", "__arm64_sys_sendmsg", "el0_svc_common.constprop.0"}, "sendmsg"},
+		{[]string{"foo", "__ia32_compat_sys_ioctl", "do_fast_syscall_32"}, "ioctl"},
+		{[]string{"foo", "__do_compat_sys_recvmmsg.isra.0", "__se_compat_sys_recvmmsg"}, "recvmmsg"},
+		{[]string{"foo", "SYSC_setsockopt", "SyS_setsockopt", "entry_SYSCALL_64_fastpath"}, "setsockopt"},
+		{[]string{"foo", "C_SYSC_ioctl", "compat_SyS_ioctl"}, "ioctl"},
+		// The outermost syscall frame wins.
+		{[]string{"sys_imageblit", "__x64_sys_ioctl", "do_syscall_64"}, "ioctl"},
+		{[]string{"ksys_ioctl", "do_syscall_64", "entry_SYSCALL_64_after_hwframe"}, ""},
+		{nil, ""},
+	}
+	for i, test := range tests {
+		var frames []StackFrame
+		for _, fn := range test.frames {
+			frames = append(frames, StackFrame{Func: fn})
+		}
+		if syscall := linuxSyscall(frames); syscall != test.syscall {
+			t.Errorf("#%v: got syscall %q, want %q", i, syscall, test.syscall)
+		}
+	}
+	const log = `
+[   62.563013] WARNING: CPU: 1 PID: 4321 at net/core/dev.c:1234 dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013] Modules linked in:
+[   62.563013] CPU: 1 PID: 4321 Comm: syz-executor.0 Not tainted 5.19.0-rc1-syzkaller #0
+[   62.563013] RIP: 0010:dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013] Call Trace:
+[   62.563013]  <TASK>
+[   62.563013]  dev_foo+0x12/0x34 net/core/dev.c:1234
+[   62.563013]  sock_ioctl+0x2ac/0x4e0 net/socket.c:1011
+[   62.563013]  vfs_ioctl fs/ioctl.c:51 [inline]
+[   62.563013]  __do_sys_ioctl fs/ioctl.c:870 [inline]
+[   62.563013]  __se_sys_ioctl fs/ioctl.c:856 [inline]
+[   62.563013]  __x64_sys_ioctl+0x193/0x200 fs/ioctl.c:856
+[   62.563013]  do_syscall_64+0x35/0xb0 arch/x86/entry/common.c:80
+[   62.563013]  entry_SYSCALL_64_after_hwframe+0x46/0xb0
+[   62.563013]  </TASK>
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil || rep.Syscall != "ioctl" {
+		t.Fatalf("bad syscall in report: %+v", rep)
+	}
+}
+
+func TestLinuxSortMaintainers(t *testing.T) {
+	mtrs := []string{
+		"netdev@vger.kernel.org",
+		"davem@davemloft.net",
+		"linux-kernel@vger.kernel.org",
+		"syzkaller@googlegroups.com",
+		"kuznet@ms2.inr.ac.ru",
+		"linux-arm-kernel@lists.infradead.org",
+		"bpf@vger.kernel.org",
+	}
+	want := []string{
+		"davem@davemloft.net",
+		"kuznet@ms2.inr.ac.ru",
+		"bpf@vger.kernel.org",
+		"linux-arm-kernel@lists.infradead.org",
+		"linux-kernel@vger.kernel.org",
+		"netdev@vger.kernel.org",
+		"syzkaller@googlegroups.com",
+	}
+	sortMaintainers(mtrs)
+	if !reflect.DeepEqual(mtrs, want) {
+		t.Fatalf("bad maintainers order:\n%q\nwant:\n%q", mtrs, want)
+	}
+}
+
+func TestLinuxStackTraceDumps(t *testing.T) {
+	const log = `
+[  112.301234] syz-executor0 (4321) used greatest stack depth: 10912 bytes left
+[  112.303412] stack trace:
+[  112.303412]  => __ftrace_trace_stack+0x12/0x20
+[  112.303412]  => trace_event_buffer_commit+0x175/0x320
+[  112.303412]  => tcp_sendmsg_locked+0x1b2/0x2d90
+[  112.303412]  => tcp_sendmsg+0x2f/0x50
+[  112.303412]  => sock_sendmsg+0xca/0x110
+[  112.303412]  => __x64_sys_sendto+0x25/0x30
+[  112.303412]  => entry_SYSCALL_64_after_hwframe+0x44/0xae
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reporter.ContainsCrash([]byte(log)) {
+		t.Fatalf("stack trace dump is reported as a crash by default")
+	}
+	if rep := reporter.Parse([]byte(log)); rep != nil {
+		t.Fatalf("stack trace dump is parsed by default: %q", rep.Title)
+	}
+	reporter, err = NewReporterOptions("linux", "", "", nil, nil, Options{StackTraceDumps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reporter.ContainsCrash([]byte(log)) {
+		t.Fatalf("stack trace dump is not detected")
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("stack trace dump is not parsed")
+	}
+	if rep.Title != "stack trace in tcp_sendmsg_locked" || rep.Corrupted || rep.Severity != SeverityLow {
+		t.Fatalf("bad report: title %q, corrupted %v (%v), severity %v",
+			rep.Title, rep.Corrupted, rep.CorruptedReason, rep.Severity)
+	}
+}
+
+func TestLinuxCodeBytes(t *testing.T) {
+	const gpf = `
+[   44.033797] kasan: GPF could be caused by NULL-ptr deref or user memory access
+[   44.041231] general protection fault: 0000 [#1] SMP KASAN
+[   44.046783] Dumping ftrace buffer:
+[   44.050302]    (ftrace buffer empty)
+[   44.053985] Modules linked in:
+[   44.057159] CPU: 0 PID: 4230 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #180
+[   44.064403] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[   44.073758] RIP: 0010:__lock_acquire+0x1c6/0x4800 kernel/locking/lockdep.c:3375
+[   44.080131] RSP: 0018:ffff8801cd3576d8 EFLAGS: 00010006
+[   44.085476] RAX: dffffc0000000000 RBX: 0000000000000001 RCX: 0000000000000000
+[   44.177477] Call Trace:
+[   44.180046]  lock_acquire+0x1d5/0x580 kernel/locking/lockdep.c:3914
+[   44.185901]  _raw_spin_lock_irqsave+0x96/0xc0 kernel/locking/spinlock.c:152
+[   44.192977]  skb_dequeue+0x21/0x180 net/core/skbuff.c:2787
+[   44.230946] Code: 48 89 fa 48 c1 ea 03 (bad) <80> 3c 02 00 0f 85 cd 00 00 00
+[   44.250357] RIP: __lock_acquire+0x1c6/0x4800 kernel/locking/lockdep.c:3375 RSP: ffff8801cd3576d8
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(gpf))
+	if rep == nil {
+		t.Fatal("no report")
+	}
+	want := []byte{0x48, 0x89, 0xfa, 0x48, 0xc1, 0xea, 0x03, 0x80, 0x3c, 0x02, 0x00,
+		0x0f, 0x85, 0xcd, 0x00, 0x00, 0x00}
+	if !bytes.Equal(rep.CodeBytes, want) || rep.CodeFault != 7 {
+		t.Fatalf("bad code bytes: %x (fault at %v)", rep.CodeBytes, rep.CodeFault)
+	}
+	for _, code := range []string{"Code: Bad RIP value.", "Modules linked in:"} {
+		log := strings.Replace(gpf, "Code: 48 89 fa 48 c1 ea 03 (bad) <80> 3c 02 00 0f 85 cd 00 00 00", code, 1)
+		if rep := reporter.Parse([]byte(log)); rep == nil || rep.CodeBytes != nil {
+			t.Fatalf("unexpected code bytes for %q: %+v", code, rep)
+		}
+	}
+	if code, fault := extractCodeBytes("amd64", []byte("Code: 0f 0b 48 c7 c7\n")); len(code) != 5 || fault != -1 {
+		t.Fatalf("bad code bytes without marker: %x (fault at %v)", code, fault)
+	}
+}
+
+func TestLinuxSymbolizeFrame(t *testing.T) {
+	symbols := map[string][]symbolizer.Symbol{
+		"foo": {{Addr: 0x1000000, Size: 0x190}},
+	}
+	calls := 0
+	symb := func(bin string, pc uint64) ([]symbolizer.Frame, error) {
+		calls++
+		if bin != "/linux/vmlinux" {
+			return nil, fmt.Errorf("unknown binary %q", bin)
+		}
+		switch pc {
+		case 0x1000100:
+			return []symbolizer.Frame{
+				{Func: "inlined", File: "/linux/include/foo.h", Line: 11, Inline: true},
+				{Func: "foo", File: "/linux/net/foo.c", Line: 555},
+			}, nil
+		case 0x1000110:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("unknown pc 0x%x", pc)
+		}
+	}
+	ctx := &linux{vmlinux: "/linux/vmlinux", symbols: symbols}
+	for i := 0; i < 2; i++ {
+		frame := &StackFrame{Func: "foo", Offset: 0x101, Size: 0x190}
+		if err := ctx.symbolizeFrame(frame, symb); err != nil {
+			t.Fatal(err)
+		}
+		if frame.File != "net/foo.c" || frame.Line != 555 {
+			t.Fatalf("bad symbolized frame: %+v", frame)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("symbolizer is called %v times, want 1", calls)
+	}
+	for _, frame := range []*StackFrame{
+		{Func: "foo", Offset: 0x111, Size: 0x190},
+		{Func: "foo", Offset: 0x121, Size: 0x190},
+		{Func: "bar", Offset: 0x101, Size: 0x190},
+	} {
+		if err := ctx.symbolizeFrame(frame, symb); err == nil {
+			t.Fatalf("symbolized bad frame: %+v", frame)
+		}
+	}
+	// Without vmlinux, frames are left intact.
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := StackFrame{Func: "foo", Offset: 0x101, Size: 0x190}
+	if err := reporter.SymbolizeFrame(&frame); err != nil || frame.File != "" {
+		t.Fatalf("bad frame symbolization without vmlinux: %+v, %v", frame, err)
+	}
+}
+
+func TestLinuxNonCanonical(t *testing.T) {
+	tests := []struct {
+		log          string
+		title        string
+		nonCanonical bool
+		addr         uint64
+		nullPtrDeref bool
+	}{
+		{`
+[   44.041231] general protection fault: 0000 [#1] SMP KASAN
+[   44.053985] Modules linked in:
+[   44.057159] CPU: 0 PID: 4230 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #180
+[   44.073758] RIP: 0010:__lock_acquire+0x1c6/0x4800 kernel/locking/lockdep.c:3375
+[   44.177477] Call Trace:
+[   44.180046]  lock_acquire+0x1d5/0x580 kernel/locking/lockdep.c:3914
+[   44.185901]  _raw_spin_lock_irqsave+0x96/0xc0 kernel/locking/spinlock.c:152
+[   44.192977]  skb_dequeue+0x21/0x180 net/core/skbuff.c:2787
+`, "general protection fault in __lock_acquire", false, 0, false},
+		{`
+[   76.363858] general protection fault, probably for non-canonical address 0xdffffc0000000001: 0000 [#1] PREEMPT SMP KASAN
+[   76.375408] KASAN: null-ptr-deref in range [0x0000000000000008-0x000000000000000f]
+[   76.383201] CPU: 1 PID: 8311 Comm: syz-executor.0 Not tainted 5.6.0-rc7-syzkaller #0
+[   76.392698] RIP: 0010:__lock_acquire+0x1c6/0x4800 kernel/locking/lockdep.c:3375
+[   76.477477] Call Trace:
+[   76.480046]  lock_acquire+0x1d5/0x580 kernel/locking/lockdep.c:3914
+[   76.485901]  _raw_spin_lock_irqsave+0x96/0xc0 kernel/locking/spinlock.c:152
+[   76.492977]  skb_dequeue+0x21/0x180 net/core/skbuff.c:2787
+`, "general protection fault in __lock_acquire", true, 0xdffffc0000000001, true},
+		{`
+[   76.363858] general protection fault, probably for non-canonical address 0xdead000000000100: 0000 [#1] PREEMPT SMP KASAN
+[   76.375408] KASAN: maybe wild-memory-access in range [0xdead000000000100-0xdead000000000107]
+[   76.383201] CPU: 1 PID: 8311 Comm: syz-executor.0 Not tainted 5.6.0-rc7-syzkaller #0
+[   76.392698] RIP: 0010:__list_del_entry include/linux/list.h:132 [inline]
+[   76.392698] RIP: 0010:list_del_init include/linux/list.h:190 [inline]
+[   76.392698] RIP: 0010:sock_foo+0x8a/0x240 net/core/sock.c:1234
+[   76.477477] Call Trace:
+[   76.480046]  sock_foo+0x8a/0x240 net/core/sock.c:1234
+[   76.485901]  sock_close+0x1c/0x30 net/socket.c:1225
+[   76.492977]  __fput+0x33e/0x880 fs/file_table.c:280
+`, "general protection fault in sock_foo", true, 0xdead000000000100, false},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || rep.Corrupted {
+			t.Fatalf("#%v: got title %q (corrupted %q), want %q",
+				i, rep.Title, rep.CorruptedReason, test.title)
+		}
+		if rep.NonCanonical != test.nonCanonical || rep.NonCanonicalAddr != test.addr ||
+			rep.NullPtrDeref != test.nullPtrDeref {
+			t.Fatalf("#%v: got non-canonical %v addr 0x%x null %v, want %v 0x%x %v", i,
+				rep.NonCanonical, rep.NonCanonicalAddr, rep.NullPtrDeref,
+				test.nonCanonical, test.addr, test.nullPtrDeref)
+		}
+	}
+}
+
+type fakeSymbolizer struct {
+	calls  *int
+	closed *int
+}
+
+func (s fakeSymbolizer) Symbolize(bin string, pc uint64) ([]symbolizer.Frame, error) {
+	*s.calls++
+	if bin != "/linux/vmlinux" || pc != 0x1000100 {
+		return nil, fmt.Errorf("bad request %v 0x%x", bin, pc)
+	}
+	return []symbolizer.Frame{
+		{Func: "inlined", File: "/linux/include/linux/foo.h", Line: 11, Inline: true},
+		{Func: "foo", File: "/linux/include/linux/bar.h", Line: 555},
+	}, nil
+}
+
+func (s fakeSymbolizer) Close() {
+	*s.closed++
+}
+
+func TestLinuxCustomSymbolizer(t *testing.T) {
+	symbols := map[string][]symbolizer.Symbol{
+		"foo": {{Addr: 0x1000000, Size: 0x190}},
+	}
+	calls, closed := 0, 0
+	opts := Options{
+		Symbolizer: func() Symbolizer {
+			return fakeSymbolizer{&calls, &closed}
+		},
+	}
+	reporter, err := NewReporterOptions("linux", "", "/linux", symbols, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := &Report{
+		Report: []byte("Call Trace:\n foo+0x101/0x190\n"),
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	want := " inlined include/linux/foo.h:11 [inline]\n foo+0x101/0x190 include/linux/bar.h:555\n"
+	if !bytes.Contains(rep.Report, []byte(want)) {
+		t.Fatalf("bad symbolized report:\n%s\nwant:\n%s", rep.Report, want)
+	}
+	if calls != 1 || closed != 1 {
+		t.Fatalf("symbolizer is called %v times and closed %v times", calls, closed)
+	}
+	frame := StackFrame{Func: "foo", Offset: 0x101, Size: 0x190}
+	if err := reporter.SymbolizeFrame(&frame); err != nil {
+		t.Fatal(err)
+	}
+	if frame.File != "include/linux/bar.h" || frame.Line != 555 || calls != 2 {
+		t.Fatalf("bad symbolized frame: %+v (calls %v)", frame, calls)
+	}
+	// The symbolizer kept by SymbolizeFrame is shut down by Close.
+	if closed != 1 {
+		t.Fatalf("the frame symbolizer is closed before Close")
+	}
+	if err := reporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if closed != 2 {
+		t.Fatalf("the frame symbolizer is closed %v times after Close, want 2", closed)
+	}
+	if err := reporter.Close(); err != nil || closed != 2 {
+		t.Fatalf("repeated Close: %v, closed %v times", err, closed)
+	}
+}
+
+func TestLinuxMaxLineLen(t *testing.T) {
+	huge := strings.Repeat("\\x00\\x01 escaped serialized data ", 64<<10)
+	const header = "[   86.498962] BUG: KASAN: use-after-free in foo+0x10/0x20\n"
+	tests := []struct {
+		log        string
+		maxLineLen int
+		crash      bool
+	}{
+		// The header is beyond the limit.
+		{huge + header, 0, false},
+		{huge + header, -1, true},
+		{huge + header, len(huge) + len(header), true},
+		// The header is within the limit, the rest of the line is ignored.
+		{header[:len(header)-1] + huge + "\n", 0, true},
+		{"[   86.498962] unrelated line\n" + huge + "\n" + header, 0, true},
+	}
+	for i, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{MaxLineLen: test.maxLineLen})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crash := reporter.ContainsCrash([]byte(test.log)); crash != test.crash {
+			t.Fatalf("#%v: ContainsCrash returned %v", i, crash)
+		}
+		rep := reporter.Parse([]byte(test.log))
+		if (rep != nil) != test.crash {
+			t.Fatalf("#%v: Parse returned %+v", i, rep)
+		}
+		if rep != nil && !strings.HasPrefix(rep.Title, "KASAN: use-after-free in foo") {
+			t.Fatalf("#%v: bad title %q", i, rep.Title)
+		}
+	}
+}
+
+func TestLinuxPageFaultWording(t *testing.T) {
+	tests := []struct {
+		log   string
+		title string
+		addr  uint64
+		null  bool
+	}{
+		{`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+[  772.919010] PGD ae2c067 PUD ae2d067 PMD 7faa5067 PTE 800000002bde1060
+[  772.919010] Oops: 0002 [#1] SMP DEBUG_PAGEALLOC KASAN
+[  772.919010] RIP: 0010:[<ffffffff82d4e304>]  [<ffffffff82d4e304>] __memset+0x24/0x30
+`, "BUG: unable to handle kernel paging request in __memset", 0xffff88002bde1e40, false},
+		{`
+[  122.183921] BUG: unable to handle page fault for address: ffff88002bde1e40
+[  122.191166] #PF: supervisor write access in kernel mode
+[  122.196547] #PF: error_code(0x0002) - not-present page
+[  122.201839] PGD ae2c067 P4D ae2c067 PUD ae2d067 PMD 7faa5067 PTE 800000002bde1060
+[  122.209611] Oops: 0002 [#1] PREEMPT SMP KASAN
+[  122.214102] CPU: 0 PID: 8243 Comm: syz-executor.3 Not tainted 5.4.0-rc6-syzkaller #0
+[  122.222032] RIP: 0010:__memset+0x24/0x30 arch/x86/lib/memset_64.S:41
+[  122.228321] Call Trace:
+[  122.230917]  kasan_unpoison_shadow+0x35/0x50 mm/kasan/common.c:136
+[  122.237281]  __kasan_kmalloc.constprop.0+0xcf/0xe0 mm/kasan/common.c:496
+[  122.244374]  kmem_cache_alloc_trace+0x158/0x790 mm/slab.c:3551
+`, "BUG: unable to handle kernel paging request in __memset", 0xffff88002bde1e40, false},
+		{`
+[  122.183921] BUG: kernel NULL pointer dereference, address: 0000000000000008
+[  122.191166] #PF: supervisor read access in kernel mode
+[  122.196547] #PF: error_code(0x0000) - not-present page
+[  122.201839] PGD 9a4a7067 P4D 9a4a7067 PUD 9a4a6067 PMD 0
+[  122.209611] Oops: 0000 [#1] PREEMPT SMP KASAN
+[  122.214102] CPU: 0 PID: 8243 Comm: syz-executor.3 Not tainted 5.4.0-rc6-syzkaller #0
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  122.237281]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  122.244374]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+`, "BUG: unable to handle kernel NULL pointer dereference in sock_foo", 8, true},
+		{`
+[  122.183921] BUG: kernel NULL pointer dereference, address: 0000000000000000
+[  122.191166] #PF: supervisor instruction fetch in kernel mode
+[  122.196547] #PF: error_code(0x0010) - not-present page
+[  122.209611] Oops: 0010 [#1] PREEMPT SMP KASAN
+[  122.222032] RIP: 0010:0x0
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  122.237281]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  122.244374]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+`, "BUG: unable to handle kernel NULL pointer dereference", 0, true},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title {
+			t.Fatalf("#%v: got title %q, want %q", i, rep.Title, test.title)
+		}
+		if !rep.HasFaultAddr || rep.FaultAddr != test.addr || rep.NullPtrDeref != test.null {
+			t.Fatalf("#%v: got fault addr 0x%x (%v), null %v, want 0x%x, %v", i,
+				rep.FaultAddr, rep.HasFaultAddr, rep.NullPtrDeref, test.addr, test.null)
+		}
+	}
+}
+
+func TestLinuxArm64(t *testing.T) {
+	tests := []struct {
+		log    string
+		esr    uint64
+		hasESR bool
+		frames []string
+	}{
+		{`
+[   40.109352] Unable to handle kernel paging request at virtual address ffff800000000008
+[   40.117384] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[   40.120283] pc : __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.120739] lr : lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.131234] Call trace:
+[   40.131563]  __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.132021]  lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.132462]  walk_component+0x44/0x1c8 fs/namei.c:1853
+[   40.137008] Code: f9400282 b4000482 d1002042 f9400443 (f9400444)
+`, 0x96000006, true, []string{"__d_lookup_rcu", "lookup_fast", "walk_component"}},
+		// Newer kernels print 64-bit ESR.
+		// The stack does not start with the pc function, so it is prepended.
+		{`
+[   73.318485] Unable to handle kernel NULL pointer dereference at virtual address 0000000000000010
+[   73.326475] Internal error: Oops: 0000000096000005 [#1] PREEMPT SMP
+[   73.329521] pc : sock_foo+0x24/0x30
+[   73.338112] Call trace:
+[   73.338871]  sock_bar+0x35/0x50
+[   73.339335]  sock_ioctl+0xcf/0xe0
+`, 0x96000005, true, []string{"sock_foo", "sock_bar", "sock_ioctl"}},
+		// ESR from "Mem abort info:" if the die() line is lost.
+		{`
+[   40.109352] Unable to handle kernel paging request at virtual address ffff800000000008
+[   40.110288] Mem abort info:
+[   40.110607]   ESR = 0x96000004
+[   40.120283] PC is at __d_lookup_rcu+0x38/0x1a8
+`, 0x96000004, true, nil},
+		// x86 reports don't have ESR.
+		{`
+[  122.209611] BUG: kernel NULL pointer dereference, address: 0000000000000008
+[  122.209611] Oops: 0000 [#1] PREEMPT SMP KASAN
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+`, 0, false, []string{"sock_bar"}},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.ESR != test.esr || rep.HasESR != test.hasESR {
+			t.Fatalf("#%v: got ESR 0x%x (%v), want 0x%x (%v)", i, rep.ESR, rep.HasESR, test.esr, test.hasESR)
+		}
+		var frames []string
+		for _, frame := range rep.Frames {
+			frames = append(frames, frame.Func)
+		}
+		if !reflect.DeepEqual(frames, test.frames) {
+			t.Fatalf("#%v: got frames %q, want %q", i, frames, test.frames)
+		}
+	}
+}
+
+func TestLinuxArch(t *testing.T) {
+	const x86Log = `
+[  122.209611] BUG: kernel NULL pointer dereference, address: 0000000000000008
+[  122.209611] Oops: 0002 [#1] PREEMPT SMP KASAN
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.222032] Code: 48 89 fa <80> 3c 02
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+`
+	const arm64Log = `
+[   40.109352] Unable to handle kernel paging request at virtual address ffff800000000008
+[   40.117384] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[   40.119613] pstate: 80400005 (Nzcv daif +PAN -UAO -TCO BTYPE=--)
+[   40.120283] pc : __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.131234] Call trace:
+[   40.131563]  __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.132021]  lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.137008] Code: f9400282 b4000482 (f9400444)
+`
+	const riscvLog = `
+[   56.270367] Unable to handle kernel paging request at virtual address ffffffff00000008
+[   56.271581] Oops [#1]
+[   56.272017] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.272017]  ra : sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.275433] epc : ffffffff8000405c ra : ffffffff80004a5c sp : ffffffe0006b3d30
+[   56.275433] status: 0000000000000120 badaddr: ffffffff00000008 cause: 000000000000000d
+[   56.279245] Call Trace:
+[   56.279646] [<ffffffff8000405c>] sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.280419] [<ffffffff80004a5c>] sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.281032] Code: 8b93 8526 (a783) 0007
+`
+	tests := []struct {
+		arch      string
+		log       string
+		wantArch  string
+		pageFault bool
+		hasESR    bool
+		code      []byte
+		fault     int
+		frames    []string
+	}{
+		{"", x86Log, "amd64", true, false, []byte{0x48, 0x89, 0xfa, 0x80, 0x3c, 0x02}, 3, []string{"sock_bar"}},
+		{"amd64", x86Log, "amd64", true, false, []byte{0x48, 0x89, 0xfa, 0x80, 0x3c, 0x02}, 3, []string{"sock_bar"}},
+		{"", arm64Log, "arm64", false, true, []byte{0x82, 0x02, 0x40, 0xf9, 0x82, 0x04, 0x00, 0xb4,
+			0x44, 0x04, 0x40, 0xf9}, 8, []string{"__d_lookup_rcu", "lookup_fast"}},
+		{"arm64", arm64Log, "arm64", false, true, []byte{0x82, 0x02, 0x40, 0xf9, 0x82, 0x04, 0x00, 0xb4,
+			0x44, 0x04, 0x40, 0xf9}, 8, []string{"__d_lookup_rcu", "lookup_fast"}},
+		// Explicitly specified arch forces x86 parsing of arm64 "Code:" line, which fails.
+		{"amd64", arm64Log, "amd64", false, false, nil, 0, nil},
+		{"", riscvLog, "riscv64", false, false, []byte{0x93, 0x8b, 0x26, 0x85, 0x83, 0xa7, 0x07, 0x00}, 4,
+			[]string{"sock_foo", "sock_bar"}},
+	}
+	for i, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{Arch: test.arch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Arch != test.wantArch {
+			t.Fatalf("#%v: got arch %q, want %q", i, rep.Arch, test.wantArch)
+		}
+		if rep.PageFault != test.pageFault || rep.HasESR != test.hasESR {
+			t.Fatalf("#%v: got page fault %v, ESR %v, want %v, %v", i,
+				rep.PageFault, rep.HasESR, test.pageFault, test.hasESR)
+		}
+		if !bytes.Equal(rep.CodeBytes, test.code) || test.code != nil && rep.CodeFault != test.fault {
+			t.Fatalf("#%v: got code %x (fault %v), want %x (fault %v)", i,
+				rep.CodeBytes, rep.CodeFault, test.code, test.fault)
+		}
+		var frames []string
+		for _, frame := range rep.Frames {
+			frames = append(frames, frame.Func)
+		}
+		if test.frames != nil && !reflect.DeepEqual(frames, test.frames) {
+			t.Fatalf("#%v: got frames %q, want %q", i, frames, test.frames)
+		}
+	}
+	if _, err := NewReporterOptions("linux", "", "", nil, nil, Options{Arch: "mips"}); err == nil {
+		t.Fatalf("no error for unknown arch")
+	}
+}
+
+func TestLinuxArchTruncatedHead(t *testing.T) {
+	tests := []struct {
+		log   string
+		title string
+	}{
+		{`
+[  122.222032] RAX: dffffc0000000000 RBX: 0000000000000000 RCX: ffffffff814ac5c5
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  122.230917] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, "truncated oops in sock_foo"},
+		{`
+[   40.120283] x29: ffff800012c4bb70 x28: ffff0000c5a1a000
+[   40.120283] pc : __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.131234] Call trace:
+[   40.131563]  __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.132021]  lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.137470] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, "truncated oops in __d_lookup_rcu"},
+	}
+	reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{TruncatedHeads: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || !rep.Corrupted {
+			t.Fatalf("#%v: got title %q (corrupted %v), want %q", i, rep.Title, rep.Corrupted, test.title)
+		}
+	}
+}
+
+func TestLinuxRiscv(t *testing.T) {
+	tests := []struct {
+		log    string
+		frames []string
+		addr   uint64
+		null   bool
+	}{
+		{`
+[   56.270367] Unable to handle kernel paging request at virtual address ffffffff00000008
+[   56.271581] Oops [#1]
+[   56.272017] epc : sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.272017]  ra : sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.275433] epc : ffffffff8000405c ra : ffffffff80004a5c sp : ffffffe0006b3d30
+[   56.275433] status: 0000000000000120 badaddr: ffffffff00000008 cause: 000000000000000d
+[   56.279245] Call Trace:
+[   56.279646] [<ffffffff8000405c>] sock_foo+0x24/0x30 net/core/sock.c:123
+[   56.280419] [<ffffffff80004a5c>] sock_bar+0x35/0x50 net/core/sock.c:136
+[   56.281032] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, []string{"sock_foo", "sock_bar"}, 0xffffffff00000008, false},
+		// Older kernels don't print "Call Trace:".
+		{`
+[   31.103401] Unable to handle kernel NULL pointer dereference at virtual address 0000000000000000
+[   31.104572] Oops [#1]
+[   31.106100] epc : __memset+0x60/0xfc arch/riscv/lib/memset.S:64
+[   31.106100]  ra : skb_put+0x35/0x50 net/core/skbuff.c:1935
+[   31.106100] epc : ffffffff831668cc ra : ffffffff8199d5b2 sp : ffffffe0006b3d30
+[   31.106100] status: 0000000000000120 badaddr: 0000000000000000 cause: 000000000000000f
+[   31.107793] [<ffffffff831668cc>] __memset+0x60/0xfc arch/riscv/lib/memset.S:64
+[   31.108364] [<ffffffff8199d5b2>] skb_put+0x35/0x50 net/core/skbuff.c:1935
+[   31.108364] ---[ end trace 1af0bf1ef0e5d2c4 ]---
+`, []string{"__memset", "skb_put"}, 0, true},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Arch != "riscv64" {
+			t.Fatalf("#%v: got arch %q", i, rep.Arch)
+		}
+		var frames []string
+		for _, frame := range rep.Frames {
+			frames = append(frames, frame.Func)
+		}
+		if !reflect.DeepEqual(frames, test.frames) {
+			t.Fatalf("#%v: got frames %q, want %q", i, frames, test.frames)
+		}
+		if !rep.HasFaultAddr || rep.FaultAddr != test.addr || rep.NullPtrDeref != test.null {
+			t.Fatalf("#%v: got fault addr 0x%x (%v), null %v, want 0x%x, %v", i,
+				rep.FaultAddr, rep.HasFaultAddr, rep.NullPtrDeref, test.addr, test.null)
+		}
+	}
+}
+
+func TestLinuxStackText(t *testing.T) {
+	tests := []struct {
+		log  string
+		text string
+	}{
+		// The stack is bounded by markers.
+		{`
+[  122.209611] BUG: kernel NULL pointer dereference, address: 0000000000000008
+[  122.209611] Oops: 0000 [#1] PREEMPT SMP KASAN
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.228321] Call Trace:
+[  122.228321]  <TASK>
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  122.230917]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  122.230917]  </TASK>
+[  122.230917] Modules linked in:
+[  122.230917]  do_vfs_ioctl+0x158/0x790 fs/ioctl.c:551
+`, ` <TASK>
+ sock_bar+0x35/0x50 net/core/sock.c:136
+ sock_ioctl+0xcf/0xe0 net/socket.c:496
+`},
+		// The stack end is detected heuristically: unrelated lines inside of the stack are kept,
+		// but trailing non-frame lines are not.
+		{`
+[  122.209611] BUG: kernel NULL pointer dereference, address: 0000000000000008
+[  122.209611] Oops: 0000 [#1] PREEMPT SMP KASAN
+[  122.222032] RIP: 0010:sock_foo+0x24/0x30 net/core/sock.c:123
+[  122.228321] Call Trace:
+[  122.230917]  sock_bar+0x35/0x50 net/core/sock.c:136
+[  122.230917] net_ratelimit: 2 callbacks suppressed
+[  122.230917]  sock_ioctl+0xcf/0xe0 net/socket.c:496
+[  122.230917] Dumping ftrace buffer:
+[  122.230917]    (ftrace buffer empty)
+`, ` sock_bar+0x35/0x50 net/core/sock.c:136
+net_ratelimit: 2 callbacks suppressed
+ sock_ioctl+0xcf/0xe0 net/socket.c:496
+`},
+		// arm64.
+		{`
+[   40.109352] Unable to handle kernel paging request at virtual address ffff800000000008
+[   40.117384] Internal error: Oops: 96000006 [#1] PREEMPT SMP
+[   40.120283] pc : __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.131234] Call trace:
+[   40.131563]  __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+[   40.132021]  lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+[   40.137008] Code: f9400282 b4000482 d1002042 f9400443 (f9400444)
+`, ` __d_lookup_rcu+0x38/0x1a8 fs/dcache.c:2345
+ lookup_fast+0x5c/0x1d0 fs/namei.c:1512
+`},
+		// KCSAN reports don't have a stack trace section.
+		{`
+[  112.398570] ==================================================================
+[  112.406031] BUG: KCSAN: assert: race in rcu_preempt_deferred_qs_irqrestore
+[  112.413236] 
+[  112.414861] assert no accesses to 0xffff8881f5c2f1c0 of 8 bytes by task 2934 on cpu 1:
+[  112.422673]  rcu_preempt_deferred_qs_irqrestore+0x2a4/0x6e0 kernel/rcu/tree_plugin.h:456
+[  112.451568] Reported by Kernel Concurrency Sanitizer on:
+[  112.479410] ==================================================================
+`, ``},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		text := rep.StackText()
+		if string(text) != test.text || test.text == "" && text != nil {
+			t.Fatalf("#%v: got stack text:\n%s\nwant:\n%s", i, text, test.text)
+		}
+	}
+}
+
+func TestLinuxStackTextSymbolized(t *testing.T) {
+	symbols := map[string][]symbolizer.Symbol{
+		"foo": {{Addr: 0x1000000, Size: 0x190}},
+	}
+	calls, closed := 0, 0
+	opts := Options{
+		Symbolizer: func() Symbolizer {
+			return fakeSymbolizer{&calls, &closed}
+		},
+	}
+	reporter, err := NewReporterOptions("linux", "", "/linux", symbols, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+[  772.919010] Call Trace:
+[  772.919010]  foo+0x101/0x190
+[  772.919010] Code: 48 89 fa <80> 3c 02
+`))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if text := string(rep.StackText()); text != " foo+0x101/0x190\n" {
+		t.Fatalf("bad stack text before symbolization:\n%s", text)
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	want := " inlined include/linux/foo.h:11 [inline]\n foo+0x101/0x190 include/linux/bar.h:555\n"
+	if text := string(rep.StackText()); text != want {
+		t.Fatalf("bad stack text after symbolization:\n%s\nwant:\n%s", text, want)
+	}
+}
+
+func TestLinuxMaintainerFiles(t *testing.T) {
+	kernelSrc, err := ioutil.TempDir("", "syz-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(kernelSrc)
+	// Fake get_maintainer.pl: the file is the last argument.
+	const script = `#!/bin/sh
+for file; do :; done
+case "$file" in
+net/*)
+	echo "David Miller <davem@davemloft.net>"
+	echo "netdev@vger.kernel.org"
+	;;
+fs/*)
+	echo "Al Viro <viro@zeniv.linux.org.uk>"
+	echo "linux-fsdevel@vger.kernel.org"
+	;;
+esac
+echo "linux-kernel@vger.kernel.org"
+`
+	if err := os.MkdirAll(filepath.Join(kernelSrc, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(kernelSrc, "scripts", "get_maintainer.pl"),
+		[]byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	const report = `BUG: KASAN: use-after-free in sock_poll+0x21/0x50
+Call Trace:
+ __dump_stack lib/dump_stack.c:17 [inline]
+ dump_stack+0x1b/0x20 lib/dump_stack.c:53
+ sock_poll+0x21/0x50 net/socket.c:1150
+ sock_poll+0x21/0x50 net/socket.c:1155
+ vfs_poll include/linux/poll.h:90 [inline]
+ do_select+0x3d2/0x870 fs/select.c:534
+ core_sys_select+0x2f3/0x490 fs/select.c:677
+`
+	tests := []struct {
+		files int
+		mtrs  []string
+	}{
+		{0, []string{"davem@davemloft.net", "linux-kernel@vger.kernel.org", "netdev@vger.kernel.org"}},
+		{1, []string{"davem@davemloft.net", "linux-kernel@vger.kernel.org", "netdev@vger.kernel.org"}},
+		// net/socket.c is mentioned twice, so the second file is fs/select.c.
+		{2, []string{"davem@davemloft.net", "viro@zeniv.linux.org.uk", "linux-fsdevel@vger.kernel.org",
+			"linux-kernel@vger.kernel.org", "netdev@vger.kernel.org"}},
+		{10, []string{"davem@davemloft.net", "viro@zeniv.linux.org.uk", "linux-fsdevel@vger.kernel.org",
+			"linux-kernel@vger.kernel.org", "netdev@vger.kernel.org"}},
+	}
+	for _, test := range tests {
+		opts := Options{
+			MaintainerFiles: test.files,
+			Symbolizer: func() Symbolizer {
+				return fakeSymbolizer{new(int), new(int)}
+			},
+		}
+		reporter, err := NewReporterOptions("linux", kernelSrc, "/linux",
+			map[string][]symbolizer.Symbol{}, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := &Report{Report: []byte(report)}
+		if err := reporter.Symbolize(rep); err != nil {
+			t.Fatal(err)
+		}
+		if rep.GuiltyFile != "net/socket.c" {
+			t.Fatalf("files %v: got guilty file %q", test.files, rep.GuiltyFile)
+		}
+		if !reflect.DeepEqual(rep.Maintainers, test.mtrs) {
+			t.Fatalf("files %v: got maintainers:\n%q\nwant:\n%q", test.files, rep.Maintainers, test.mtrs)
+		}
+	}
+}
+
+func TestLinuxFaultPC(t *testing.T) {
+	tests := []struct {
+		log    string
+		pc     StackFrame
+		guilty string
+	}{
+		{
+			log: `
+[  217.251715] general protection fault: 0000 [#1] SMP KASAN
+[  217.257286] CPU: 0 PID: 3 Comm: ksoftirqd/0 Not tainted 4.20.0-rc1+ #1
+[  217.264095] RIP: 0010:tcp_v4_rcv+0x1d4e/0x3530 net/ipv4/tcp_ipv4.c:1846
+[  217.270910] RSP: 0018:ffff8801d9e8f3a0 EFLAGS: 00010202
+[  217.276325] Call Trace:
+[  217.278924]  ip_local_deliver_finish+0x2e9/0xda0 net/ipv4/ip_input.c:215
+[  217.285802]  ip_local_deliver+0x1e9/0x750 net/ipv4/ip_input.c:256
+[  217.292183]  ip_rcv_finish+0x1f9/0x300 net/ipv4/ip_input.c:414
+`,
+			pc: StackFrame{
+				Func:   "tcp_v4_rcv",
+				Offset: 0x1d4e,
+				Size:   0x3530,
+				File:   "net/ipv4/tcp_ipv4.c",
+				Line:   1846,
+			},
+			guilty: "net/ipv4/tcp_ipv4.c",
+		},
+		{
+			log: `
+[   62.419507] general protection fault: 0000 [#1] SMP KASAN
+[   62.419507] CPU: 1 PID: 4316 Comm: syz-executor4 Not tainted 4.4.107-g610c835 #1
+[   62.419507] RIP: 0010:[<ffffffff8121b9a5>]  [<ffffffff8121b9a5>] __lock_acquire+0x6f5/0x4a80
+[   62.419507] RSP: 0018:ffff8801d3e77a00  EFLAGS: 00010006
+[   62.419507] Call Trace:
+[   62.419507]  [<ffffffff81221b5a>] lock_acquire+0x15a/0x3e0 kernel/locking/lockdep.c:3592
+[   62.419507]  [<ffffffff83a4cbd0>] _raw_spin_lock_irqsave+0x50/0x70
+[   62.419507]  [<ffffffff8157a0b6>] ep_poll_callback+0xd6/0x800 fs/eventpoll.c:1016
+`,
+			pc: StackFrame{
+				Func:   "__lock_acquire",
+				Offset: 0x6f5,
+				Size:   0x4a80,
+			},
+			guilty: "fs/eventpoll.c",
+		},
+		{
+			log: `
+[  108.251036] BUG: KASAN: use-after-free in memcpy+0x11/0x20 mm/kasan/kasan.c:303
+[  108.251060] Call Trace:
+[  108.251060]  memcpy+0x11/0x20 mm/kasan/kasan.c:303
+[  108.251060]  foo_ioctl+0x55/0x70 fs/foo.c:20
+`,
+			guilty: "fs/foo.c",
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		hasPC := test.pc.Func != ""
+		if rep.HasFaultPC != hasPC || rep.FaultPC != test.pc {
+			t.Fatalf("#%v: got fault pc %v/%+v, want %v/%+v", i, rep.HasFaultPC, rep.FaultPC, hasPC, test.pc)
+		}
+		if err := reporter.Symbolize(rep); err != nil {
+			t.Fatal(err)
+		}
+		if rep.GuiltyFile != test.guilty {
+			t.Fatalf("#%v: got guilty file %q, want %q", i, rep.GuiltyFile, test.guilty)
+		}
+	}
+}
+
+func TestLinuxPrintkReplay(t *testing.T) {
+	const log = `[   75.260000] some output
+** replaying previous printk message ** [   75.270413] BUG: KASAN: use-after-free in foo+0x12b/0x150 fs/foo.c:54
+** replaying previous printk message ** [   75.278577] Read of size 8 at addr ffff88809ad85f08 by task syz-executor.1/9152
+** replaying previous printk message ** [   75.294507] Call Trace:
+[   75.329671]  foo+0x12b/0x150 fs/foo.c:54
+[   75.347910]  bar+0x2a/0x190 fs/bar.c:1120
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if string(rep.Output) != log {
+		t.Fatalf("raw output is modified")
+	}
+	start := strings.Index(log, "** replaying")
+	if rep.StartPos != start {
+		t.Fatalf("got start pos %v, want %v", rep.StartPos, start)
+	}
+	if bytes.Contains(rep.Report, []byte("replaying")) {
+		t.Fatalf("report contains replay markers:\n%s", rep.Report)
+	}
+	if len(rep.Frames) != 2 || rep.Frames[1].Func != "bar" {
+		t.Fatalf("bad frames: %+v", rep.Frames)
+	}
+}
+
+func TestLinuxSuspiciousRCUSymbolize(t *testing.T) {
+	tests := []struct {
+		log        string
+		title      string
+		symbolized string
+	}{
+		{
+			log: `
+[  129.374986] =============================
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375017] 4.15.0-rc2+ #208 Not tainted
+[  129.375030] -----------------------------
+[  129.375045] ./include/linux/rcupdate.h:302 Illegal context switch in RCU read-side critical section!
+[  129.375057] other info that might help us debug this:
+[  129.375071] rcu_scheduler_active = 2, debug_locks = 1
+[  129.375086] 2 locks held by syz-executor1/10463:
+[  129.375196] stack backtrace:
+[  129.375234] Call Trace:
+[  129.375257]  __dump_stack lib/dump_stack.c:17 [inline]
+[  129.375257]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  129.375285]  lockdep_rcu_suspicious+0x123/0x170 kernel/locking/lockdep.c:4585
+[  129.375313]  rcu_preempt_sleep_check include/linux/rcupdate.h:301 [inline]
+[  129.375313]  ___might_sleep+0x385/0x470 kernel/sched/core.c:6079
+[  129.375342]  __might_sleep+0x95/0x190 kernel/sched/core.c:6067
+`,
+			title:      "suspicious RCU usage at ./include/linux/rcupdate.h:LINE",
+			symbolized: "suspicious RCU usage in rcu_preempt_sleep_check",
+		},
+		{
+			log: `
+[ 1722.511384] ===============================
+[ 1722.511384] [ INFO: suspicious RCU usage. ]
+[ 1722.511384] 4.9.0+ #16 Not tainted
+[ 1722.511384] -------------------------------
+[ 1722.511384] net/ipv6/ip6_flowlabel.c:544 suspicious rcu_dereference_check() usage!
+[ 1722.511384] rcu_scheduler_active = 1, debug_locks = 0
+[ 1722.511384] 1 lock held by syz-executor/28390:
+[ 1722.511384] stack backtrace:
+[ 1722.511384] Call Trace:
+[ 1722.511384]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[ 1722.511384]  lockdep_rcu_suspicious+0x123/0x170 kernel/locking/lockdep.c:4585
+[ 1722.511384]  ipv6_flowlabel_opt+0x1a98/0x2120 net/ipv6/ip6_flowlabel.c:544
+[ 1722.511384]  do_ipv6_setsockopt.isra.9+0x1bd6/0x3870 net/ipv6/ipv6_sockglue.c:792
+`,
+			title:      "suspicious RCU usage at net/ipv6/ip6_flowlabel.c:LINE",
+			symbolized: "suspicious RCU usage in ipv6_flowlabel_opt",
+		},
+		{
+			// Not symbolized, so the file can't be attributed to a function.
+			log: `
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375045] net/core/filter.c:1145 suspicious rcu_dereference_protected() usage!
+[  129.375234] Call Trace:
+[  129.375285]  lockdep_rcu_suspicious+0x123/0x170
+[  129.375313]  sk_detach_filter+0x1a1/0x250
+`,
+			title:      "suspicious RCU usage at net/core/filter.c:LINE",
+			symbolized: "suspicious RCU usage at net/core/filter.c:LINE",
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title {
+			t.Fatalf("#%v: got title %q, want %q", i, rep.Title, test.title)
+		}
+		if err := reporter.Symbolize(rep); err != nil {
+			t.Fatal(err)
+		}
+		if rep.Title != test.symbolized {
+			t.Fatalf("#%v: got symbolized title %q, want %q", i, rep.Title, test.symbolized)
+		}
+	}
+}
+
+func TestLinuxFullFuncNames(t *testing.T) {
+	tests := []struct {
+		log   string
+		title string
+		full  string
+	}{
+		{
+			log: `
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store.isra.0+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store.isra.0+0x4e4/0x520 include/net/ip6_fib.h:175
+`,
+			title: "KASAN: use-after-free Read in ip6_dst_store",
+			full:  "KASAN: use-after-free Read in ip6_dst_store.isra.0",
+		},
+		{
+			log: `
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar.cold+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar.cold+0xe4/0x110 net/core/dev.c:123
+`,
+			title: "WARNING in foo_bar",
+			full:  "WARNING in foo_bar.cold",
+		},
+		{
+			log: `
+[   52.916579] general protection fault: 0000 [#1] SMP KASAN
+[   52.930360] RIP: 0010:tcp_v4_rcv.constprop.0.cold.12+0x1d4e/0x3530 net/ipv4/tcp_ipv4.c:1846
+[   53.039941] Call Trace:
+[   53.042513]  ip_local_deliver_finish+0x2e9/0xda0 net/ipv4/ip_input.c:215
+`,
+			title: "general protection fault in tcp_v4_rcv",
+			full:  "general protection fault in tcp_v4_rcv.constprop.0.cold.12",
+		},
+	}
+	for _, full := range []bool{false, true} {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{FullFuncNames: full})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, test := range tests {
+			want := test.title
+			if full {
+				want = test.full
+			}
+			rep := reporter.Parse([]byte(test.log))
+			if rep == nil {
+				t.Fatalf("#%v: no report", i)
+			}
+			if rep.Title != want {
+				t.Fatalf("#%v/%v: got title %q, want %q", i, full, rep.Title, want)
+			}
+			if title := reporter.Title([]byte(test.log)); title != want {
+				t.Fatalf("#%v/%v: Title returned %q, want %q", i, full, title, want)
+			}
+		}
+	}
+}
+
+func TestLinuxTitleFuncSuffixes(t *testing.T) {
+	tests := []struct {
+		log    string
+		title  string
+		full   string
+		frames []string
+	}{
+		{
+			log: `
+[  374.860710] BUG: KASAN: use-after-free in ext4_mark_iloc_dirty.isra.0+0x2c/0x60 at addr ffff88000012c43a
+[  374.860710] Read of size 2 by task syz-executor/4217
+[  374.860710] Call Trace:
+[  374.860710]  ext4_mark_iloc_dirty.isra.0+0x2c/0x60 fs/ext4/inode.c:5839
+[  374.860710]  ext4_dirty_inode.constprop.1+0x9b/0xc0 fs/ext4/inode.c:6017
+`,
+			title:  "KASAN: use-after-free Read in ext4_mark_iloc_dirty",
+			full:   "KASAN: use-after-free Read in ext4_mark_iloc_dirty.isra.0",
+			frames: []string{"ext4_mark_iloc_dirty.isra.0", "ext4_dirty_inode.constprop.1"},
+		},
+		{
+			// KCSAN formats capture function names without the {{FUNC}} template.
+			log: `
+[   86.498962] BUG: KCSAN: data-race in ext4_mark_iloc_dirty.isra.0 / __ext4_journal_stop.cold.3
+[   86.498962] write to 0xffff8881026f3e40 of 4 bytes by task 4217 on cpu 1:
+[   86.498962]  ext4_mark_iloc_dirty.isra.0+0x2c/0x60 fs/ext4/inode.c:5839
+`,
+			title: "KCSAN: data-race in ext4_mark_iloc_dirty / __ext4_journal_stop",
+			full:  "KCSAN: data-race in ext4_mark_iloc_dirty.isra.0 / __ext4_journal_stop.cold.3",
+		},
+	}
+	for _, full := range []bool{false, true} {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{FullFuncNames: full})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, test := range tests {
+			rep := reporter.Parse([]byte(test.log))
+			if rep == nil {
+				t.Fatalf("#%v: no report", i)
+			}
+			want := test.title
+			if full {
+				want = test.full
+			}
+			if rep.Title != want {
+				t.Fatalf("#%v/%v: got title %q, want %q", i, full, rep.Title, want)
+			}
+			var funcs []string
+			for _, frame := range rep.Frames {
+				funcs = append(funcs, frame.Func)
+			}
+			if !reflect.DeepEqual(funcs, test.frames) {
+				t.Fatalf("#%v/%v: got frames %q, want %q", i, full, funcs, test.frames)
+			}
+		}
+	}
+}
+
+func TestLinuxExecFault(t *testing.T) {
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := `[  100.612089] BUG: unable to handle page fault for address: ffffffffa0000000
+[  100.619208] #PF: supervisor instruction fetch in kernel mode
+[  100.656513] RIP: 0010:0xffffffffa0000000
+[  100.660572] Code: Bad RIP value.
+[  100.683820] Call Trace:
+[  100.686408]  __sock_release+0xcd/0x280 net/socket.c:590
+[  100.691818]  sock_close+0x1b/0x30 net/socket.c:1268
+`
+	rep := reporter.Parse([]byte(exec))
+	if rep == nil || !rep.ExecFault {
+		t.Fatalf("exec fault is not detected: %+v", rep)
+	}
+	data := strings.Replace(exec, "RIP: 0010:0xffffffffa0000000", "RIP: 0010:__sock_release+0xcd/0x280", 1)
+	data = strings.Replace(data, "Code: Bad RIP value.", "Code: 48 89 fa 48 c1 ea 03 <80> 3c 02 00", 1)
+	if rep := reporter.Parse([]byte(data)); rep == nil || rep.ExecFault {
+		t.Fatalf("data fault is detected as exec fault: %+v", rep)
+	}
+}
+
+func TestLinuxUnreliableFrames(t *testing.T) {
+	report := []byte(`Call Trace:
+ dump_stack+0x194/0x257 lib/dump_stack.c:53
+ ? sctp_ulpevent_free+0x16/0x70 net/sctp/ulpevent.c:1033
+ [<ffffffff81b8d2d2>] ? skb_release_data+0x8a3/0x1c40 net/core/skbuff.c:568
+ ? __inet_lookup net/ipv4/inet_hashtables.c:300 [inline]
+ sctp_ulpevent_free+0x16/0x70 net/sctp/ulpevent.c:1033
+`)
+	want := []StackFrame{
+		{Func: "dump_stack", Offset: 0x194, Size: 0x257, File: "lib/dump_stack.c", Line: 53},
+		{Func: "sctp_ulpevent_free", Offset: 0x16, Size: 0x70, File: "net/sctp/ulpevent.c", Line: 1033,
+			Unreliable: true},
+		{Func: "skb_release_data", Offset: 0x8a3, Size: 0x1c40, File: "net/core/skbuff.c", Line: 568,
+			Unreliable: true},
+		{Func: "__inet_lookup", File: "net/ipv4/inet_hashtables.c", Line: 300, Inline: true, Unreliable: true},
+		{Func: "sctp_ulpevent_free", Offset: 0x16, Size: 0x70, File: "net/sctp/ulpevent.c", Line: 1033},
+	}
+	if frames := parseLinuxFrames("amd64", report); !reflect.DeepEqual(frames, want) {
+		t.Fatalf("got frames:\n%+v\nwant:\n%+v", frames, want)
+	}
+	// The guilty frame is the reliable one, even though an unreliable frame in the file is above it.
+	if idx := guiltyFrame(want, "net/sctp/ulpevent.c"); idx != 4 {
+		t.Fatalf("got guilty frame %v, want 4", idx)
+	}
+	if idx := guiltyFrame(want, "net/core/skbuff.c"); idx != 2 {
+		t.Fatalf("got guilty frame %v, want 2", idx)
+	}
+}
+
+func TestLinuxUserAccessFault(t *testing.T) {
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const log = `[  112.863452] Unable to handle kernel access to user memory outside uaccess routines at virtual address 0000000020000180
+[  112.885664] Internal error: Oops: 9600000f [#1] PREEMPT SMP
+[  112.902293] pc : sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.913068] Call trace:
+[  112.915521]  sock_foo+0x24/0x30 net/core/sock.c:123
+[  112.920643]  sock_bar+0x35/0x50 net/core/sock.c:136
+`
+	rep := reporter.Parse([]byte(log))
+	if rep == nil || !rep.UserAccessFault || rep.FaultAddr != 0x20000180 {
+		t.Fatalf("user access fault is not detected: %+v", rep)
+	}
+	paging := strings.Replace(log, "access to user memory outside uaccess routines", "paging request", 1)
+	if rep := reporter.Parse([]byte(paging)); rep == nil || rep.UserAccessFault {
+		t.Fatalf("paging request is detected as user access fault: %+v", rep)
+	}
+}
+
+func TestLinuxUnclassified(t *testing.T) {
+	const leak = `[ 1722.511384] unreferenced object 0xffff880039a55260 (size 64):
+[ 1722.511384]   comm "syz-executor3", pid 11746, jiffies 4298984475 (age 16.078s)
+[ 1722.511384]   hex dump (first 32 bytes):
+`
+	const warning = `[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+`
+	tests := []struct {
+		raw          bool
+		log          string
+		title        string
+		unclassified bool
+	}{
+		{false, leak, "unreferenced object ADDR (size 64):", true},
+		{true, leak, "unreferenced object 0xffff880039a55260 (size 64):", true},
+		{false, warning, "WARNING in foo_bar", false},
+		{true, warning, "WARNING in foo_bar", false},
+	}
+	for i, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{RawUnclassifiedTitles: test.raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil || rep.Title != test.title || rep.Unclassified != test.unclassified {
+			t.Fatalf("#%v: got report %+v, want title %q (unclassified %v)", i, rep, test.title, test.unclassified)
+		}
+		if title := reporter.Title([]byte(test.log)); title != test.title {
+			t.Fatalf("#%v: Title returned %q, want %q", i, title, test.title)
+		}
+	}
+}
+
+func TestLinuxKasanAllocFree(t *testing.T) {
+	tests := []struct {
+		log       string
+		title     string
+		allocFunc string
+		freeFunc  string
+	}{
+		{`
+[   96.462552] BUG: KASAN: use-after-free in selinux_tun_dev_open+0x1b2/0x1d0 security/selinux/hooks.c:5226
+[   96.462552] Read of size 4 at addr ffff8801d5961a40 by task syz-executor5/11514
+[   96.462552] Call Trace:
+[   96.462552]  selinux_tun_dev_open+0x1b2/0x1d0 security/selinux/hooks.c:5226
+[   96.462552]  security_tun_dev_open+0x48/0x80 security/security.c:1597
+[   96.462552]  __tun_chr_ioctl+0x1bd4/0x3d60 drivers/net/tun.c:2232
+[   96.462552] 
+[   96.462552] Allocated by task 11514:
+[   96.462552]  save_stack_trace+0x16/0x20
+[   96.462552]  save_stack+0x43/0xd0
+[   96.462552]  kasan_kmalloc+0xaa/0xd0
+[   96.462552]  kmem_cache_alloc_trace+0x101/0x6f0
+[   96.462552]  selinux_tun_dev_alloc_security+0x49/0x170
+[   96.462552]  security_tun_dev_alloc_security+0x6d/0xa0
+[   96.462552] 
+[   96.462552] Freed by task 11515:
+[   96.462552]  save_stack_trace+0x16/0x20
+[   96.462552]  save_stack+0x43/0xd0
+[   96.462552]  kasan_slab_free+0x6e/0xc0
+[   96.462552]  kfree+0xd3/0x260
+[   96.462552]  selinux_tun_dev_free_security.isra.3+0x15/0x20
+[   96.462552]  security_tun_dev_free_security+0x48/0x80
+[   96.462552] 
+[   96.462552] The buggy address belongs to the object at ffff8801d5961a40
+`, "KASAN: use-after-free Read in selinux_tun_dev_open",
+			"selinux_tun_dev_alloc_security", "selinux_tun_dev_free_security"},
+		// Symbolized stacks of newer kernels with inlined allocator frames.
+		{`
+[   45.123456] BUG: KASAN: slab-use-after-free in sock_foo+0x24/0x30 net/core/sock.c:123
+[   45.123456] Read of size 8 at addr ffff888012345678 by task syz-executor.0/12345
+[   45.123456] Call Trace:
+[   45.123456]  <TASK>
+[   45.123456]  sock_foo+0x24/0x30 net/core/sock.c:123
+[   45.123456]  sock_bar+0x35/0x50 net/core/sock.c:136
+[   45.123456]  </TASK>
+[   45.123456] 
+[   45.123456] Allocated by task 12345:
+[   45.123456]  kasan_save_stack+0x1b/0x40 mm/kasan/common.c:45
+[   45.123456]  kasan_set_track mm/kasan/common.c:52 [inline]
+[   45.123456]  ____kasan_kmalloc mm/kasan/common.c:374 [inline]
+[   45.123456]  __kasan_kmalloc+0x7f/0xa0 mm/kasan/common.c:383
+[   45.123456]  kmalloc include/linux/slab.h:600 [inline]
+[   45.123456]  sk_prot_alloc+0x110/0x290 net/core/sock.c:2080
+[   45.123456]  sk_alloc+0x36/0x770 net/core/sock.c:2133
+[   45.123456] 
+[   45.123456] The buggy address belongs to the object at ffff888012345600
+`, "KASAN: slab-use-after-free Read in sock_foo", "sk_prot_alloc", ""},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || rep.AllocFunc != test.allocFunc || rep.FreeFunc != test.freeFunc {
+			t.Fatalf("#%v: got %q allocated in %q freed in %q, want %q allocated in %q freed in %q",
+				i, rep.Title, rep.AllocFunc, rep.FreeFunc, test.title, test.allocFunc, test.freeFunc)
+		}
+		if !strings.HasPrefix(rep.AuxStacks[0].Title, "Allocated by task") {
+			t.Fatalf("#%v: bad aux stacks: %+v", i, rep.AuxStacks)
+		}
+	}
+}
+
+func TestLinuxParseBudget(t *testing.T) {
+	// Lazy multi-line format regexps have to scan all of the lines that can't match.
+	log := "[   42.000000] WARNING: possible circular locking dependency detected\n" +
+		strings.Repeat("[   42.000000] is trying to acquire lock: but there is no function\n", 20000)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep := reporter.Parse([]byte(log)); rep == nil || rep.CorruptedReason == budgetExceededReason {
+		t.Fatalf("bad report without budget: %+v", rep)
+	}
+	reporter, err = NewReporterOptions("linux", "", "", nil, nil, Options{ParseBudget: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil || !rep.Corrupted || rep.CorruptedReason != budgetExceededReason ||
+		rep.Title != "WARNING: possible circular locking dependency detected" {
+		t.Fatalf("bad report with exceeded budget: %+v", rep)
+	}
+	// The budget is exceeded before the oops is found.
+	noise := strings.Repeat("[   10.000000] random console noise\n", 1000)
+	if rep := reporter.Parse([]byte(noise + log)); rep != nil {
+		t.Fatalf("got report with budget exceeded before the oops: %+v", rep)
+	}
+}
+
+func TestLinuxOOM(t *testing.T) {
+	tests := []struct {
+		log      string
+		title    string
+		severity Severity
+	}{
+		{`
+[  123.140002] syz-executor0 invoked oom-killer: gfp_mask=0x14200ca(GFP_HIGHUSER_MOVABLE), nodemask=(null), order=0, oom_score_adj=1000
+[  123.151539] Mem-Info:
+[  123.154121] Out of memory: Killed process 4460 (syz-executor0) total-vm:48464kB, anon-rss:3088kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:112kB oom_score_adj:1000
+[  123.170001] oom_reaper: reaped process 4460 (syz-executor0), now anon-rss:0kB, file-rss:0kB, shmem-rss:0kB
+`, "out of memory: killed syz-executor", SeverityLow},
+		{`
+[  123.140002] syz-executor0 invoked oom-killer: gfp_mask=0x14200ca(GFP_HIGHUSER_MOVABLE), nodemask=(null), order=0, oom_score_adj=0
+[  123.151539] Kernel panic - not syncing: Out of memory and no killable processes...
+[  123.151539]
+[  123.160200] CPU: 1 PID: 4460 Comm: syz-executor0 Not tainted 4.15.0-rc8+ #264
+[  123.167457] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011
+[  123.176799] Call Trace:
+[  123.179377]  __dump_stack lib/dump_stack.c:17 [inline]
+[  123.184647]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  123.190008]  panic+0x1e4/0x41c kernel/panic.c:183
+[  123.194934]  out_of_memory+0xc6b/0x1410 mm/oom_kill.c:1080
+[  123.200634]  __alloc_pages_slowpath+0x1b2f/0x2d70 mm/page_alloc.c:3859
+[  123.207412]  __alloc_pages_nodemask+0x9fb/0xd80 mm/page_alloc.c:4271
+`, "out of memory", SeverityMedium},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || rep.Severity != test.severity {
+			t.Errorf("#%v: got %q with severity %v, want %q with severity %v",
+				i, rep.Title, rep.Severity, test.title, test.severity)
+		}
+	}
+}
+
+func TestLinuxTolerantHeaders(t *testing.T) {
+	tests := []struct {
+		log   string
+		title string
+	}{
+		{
+			// Case-mangled header.
+			`
+[   67.392145] bug: kasan: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`, "bug: kasan: use-after-free in ip6_dst_store include/net/ip6_fib.h:LINE",
+		},
+		{
+			// Non-ASCII bytes injected into the header.
+			"\n[   42.266927] WARN\xff\xfeING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:123 foo_bar+0xe4/0x110\n" +
+				"[   42.306981] Call Trace:\n" +
+				"[   42.309554]  foo_bar+0xe4/0x110 net/ipv4/tcp.c:123\n" +
+				"[   42.314898]  foo_ioctl+0x1bf/0x39f net/ipv4/tcp.c:200\n",
+			"WARNING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:LINE foo_bar",
+		},
+	}
+	strict, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tolerant, err := NewReporterOptions("linux", "", "", nil, nil, Options{TolerantHeaders: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		if strict.ContainsCrash([]byte(test.log)) {
+			t.Fatalf("#%v: strict reporter detected the mangled header", i)
+		}
+		if !tolerant.ContainsCrash([]byte(test.log)) {
+			t.Fatalf("#%v: tolerant reporter did not detect the mangled header", i)
+		}
+		rep := tolerant.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.Title != test.title || !rep.Unclassified {
+			t.Errorf("#%v: got title %q (unclassified %v), want unclassified %q",
+				i, rep.Title, rep.Unclassified, test.title)
+		}
+	}
+}
+
+func TestLinuxHardLockupStacks(t *testing.T) {
+	const log = `
+[  512.060104] NMI watchdog: Watchdog detected hard LOCKUP on cpu 1
+[  512.066564] CPU: 0 PID: 0 Comm: swapper/0 Not tainted 6.5.0-rc4+ #1
+[  512.072747] Call Trace:
+[  512.075311]  <IRQ>
+[  512.077451]  dump_stack_lvl+0xd9/0x150 lib/dump_stack.c:106
+[  512.083108]  watchdog_hardlockup_check+0x1a7/0x2e0 kernel/watchdog.c:168
+[  512.089845]  watchdog_buddy_check_hardlockup+0x8c/0xa0 kernel/watchdog_buddy.c:112
+[  512.097290]  watchdog_timer_fn+0x7b/0x640 kernel/watchdog.c:461
+[  512.103481]  </IRQ>
+[  512.105723] Sending NMI from CPU 0 to CPUs 1:
+[  512.110294] NMI backtrace for cpu 1
+[  512.114005] CPU: 1 PID: 5120 Comm: syz-executor.0 Not tainted 6.5.0-rc4+ #1
+[  512.121290] RIP: 0010:tipc_crypto_key_revoke+0x4c/0x130 net/tipc/crypto.c:1256
+[  512.128823] Call Trace:
+[  512.131405]  <NMI>
+[  512.133550]  </NMI>
+[  512.135798]  <TASK>
+[  512.138039]  tipc_crypto_key_revoke+0x4c/0x130 net/tipc/crypto.c:1256
+[  512.144712]  tipc_rcv+0x1b6/0x3b0 net/tipc/node.c:2093
+[  512.149957]  tipc_l2_rcv_msg+0x125/0x1f0 net/tipc/bearer.c:667
+[  512.156195]  </TASK>
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	// The main stack is the stuck CPU stack, not the stack of the detecting CPU.
+	if len(rep.Frames) == 0 || rep.Frames[0].Func != "tipc_crypto_key_revoke" {
+		t.Fatalf("bad frames: %+v", rep.Frames)
+	}
+	if want := "net/tipc/crypto.c"; rep.GuiltyFile != want {
+		t.Fatalf("got guilty file %q, want %q", rep.GuiltyFile, want)
+	}
+	if rep.GuiltyFrame != 0 {
+		t.Fatalf("got guilty frame %v, want 0", rep.GuiltyFrame)
+	}
+}
+
+func TestLinuxContextWindow(t *testing.T) {
+	const (
+		old = `[   41.000000] sctp: [Deprecated]: syz-executor0 (pid 4340) Use of int in maxseg socket option.
+[   41.500000] audit: type=1400 audit(1517447734.711:8): avc:  denied  { map } for  pid=4340
+`
+		context = `[   41.900000] FAULT_INJECTION: forcing a failure.
+[   41.910000] name failslab, interval 1, probability 0, space 0, times 0
+[   42.000000] syz-executor0: vmalloc: allocation failure
+[   42.100000] fault injection 1
+[   42.150000] fault injection 2
+[   42.200000] fault injection 3
+`
+		oops = `[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:123 foo_bar+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/ipv4/tcp.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/ipv4/tcp.c:200
+`
+	)
+	output := []byte(old + context + oops)
+	tests := []struct {
+		window   time.Duration
+		start    int
+		included string
+		excluded string
+	}{
+		// The 5 lines context.
+		{0, len(old) + len(context) + len("[   42.262162] ------------[ cut here ]------------\n"),
+			"fault injection 1", "name failslab"},
+		{400 * time.Millisecond, len(old), "FAULT_INJECTION", "audit"},
+		{10 * time.Second, 0, "sctp", ""},
+	}
+	for _, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{ContextWindow: test.window})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := reporter.Parse(output)
+		if rep == nil || rep.Title != "WARNING in foo_bar" {
+			t.Fatalf("window %v: bad report %+v", test.window, rep)
+		}
+		if rep.StartPos != test.start {
+			t.Errorf("window %v: got start pos %v, want %v", test.window, rep.StartPos, test.start)
+		}
+		if !bytes.Contains(rep.Report, []byte(test.included)) ||
+			test.excluded != "" && bytes.Contains(rep.Report, []byte(test.excluded)) {
+			t.Errorf("window %v: bad context, want %q, don't want %q:\n%s",
+				test.window, test.included, test.excluded, rep.Report)
+		}
+	}
+}
+
+func TestLinuxInfraLimits(t *testing.T) {
+	const log = `
+[  431.104180] BUG: MAX_LOCKDEP_ENTRIES too low!
+[  431.108822] turning off the locking correctness validator.
+[  431.114527] CPU: 0 PID: 18425 Comm: syz-executor3 Not tainted 4.15.0-rc8+ #264
+[  431.121969] Call Trace:
+[  431.124548]  __dump_stack lib/dump_stack.c:17 [inline]
+[  431.129819]  dump_stack+0x194/0x257 lib/dump_stack.c:53
+[  431.135178]  add_lock_to_list.isra.30+0x1ad/0x1f0 kernel/locking/lockdep.c:1194
+[  431.142737]  check_prev_add+0x2a9/0x1200 kernel/locking/lockdep.c:1939
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if !rep.InfraLimit || rep.Severity != SeverityInfo {
+		t.Fatalf("got infra limit %v with severity %v, want infra limit with severity %v",
+			rep.InfraLimit, rep.Severity, SeverityInfo)
+	}
+	suppressing, err := NewReporterOptions("linux", "", "", nil, nil, Options{SuppressInfraLimits: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suppressing.ContainsCrash([]byte(log)) {
+		t.Fatalf("infra limit report is not suppressed")
+	}
+	if found, suppressed, _ := suppressing.ContainsCrashDetailed([]byte(log)); found || suppressed != 1 {
+		t.Fatalf("got found %v, suppressed %v, want 1 suppressed line", found, suppressed)
+	}
+	// Other BUGs are still reported.
+	const other = `
+[   58.132412] BUG: using smp_processor_id() in preemptible [00000000] code: syz-executor0/4460
+`
+	if !suppressing.ContainsCrash([]byte(other)) {
+		t.Fatalf("other BUG is suppressed")
+	}
+}
+
+func TestLinuxInformational(t *testing.T) {
+	const (
+		info = `
+[  302.547116] clocksource: timekeeping watchdog on CPU1: Marking clocksource 'tsc' as unstable because the skew is too large:
+[  302.558960] clocksource:                       'kvm-clock' wd_now: 4659b3a0b0 wd_last: 45f7bd8a01 mask: ffffffffffffffff
+[  302.570479] clocksource:                       'tsc' cs_now: 8da6c35af0 cs_last: 8cd3a3a97a mask: ffffffffffffffff
+[  302.581767] tsc: Marking TSC unstable due to clocksource watchdog
+`
+		warning = `
+[  342.262162] ------------[ cut here ]------------
+[  342.266927] WARNING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:123 foo_bar+0xe4/0x110
+[  342.274727] Modules linked in:
+[  342.306981] Call Trace:
+[  342.309554]  foo_bar+0xe4/0x110 net/ipv4/tcp.c:123
+[  342.314898]  foo_ioctl+0x1bf/0x39f net/ipv4/tcp.c:200
+`
+	)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(info))
+	if rep == nil || !rep.Informational || rep.Severity != SeverityInfo {
+		t.Fatalf("bad informational report: %+v", rep)
+	}
+	// The informational message does not mask the following crash.
+	rep = reporter.Parse([]byte(info + warning))
+	if rep == nil || rep.Informational || rep.Title != "WARNING in foo_bar" {
+		t.Fatalf("bad report: %+v", rep)
+	}
+	if title := reporter.Title([]byte(info + warning)); title != rep.Title {
+		t.Fatalf("Title returned %q, want %q", title, rep.Title)
+	}
+	suppressing, err := NewReporterOptions("linux", "", "", nil, nil, Options{SuppressInformational: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suppressing.ContainsCrash([]byte(info)) {
+		t.Fatalf("informational message is not suppressed")
+	}
+	if rep := suppressing.Parse([]byte(info + warning)); rep == nil || rep.Title != "WARNING in foo_bar" {
+		t.Fatalf("bad report with suppressed informational messages: %+v", rep)
+	}
+}
+
+func TestLinuxSymbolizable(t *testing.T) {
+	tests := []struct {
+		log          string
+		symbolizable bool
+	}{
+		{
+			`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0
+`, true,
+		},
+		{
+			// Already symbolized.
+			`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`, false,
+		},
+		{
+			// The format does not have stack traces.
+			`
+[  123.154121] Out of memory: Killed process 4460 (syz-executor0) total-vm:48464kB, anon-rss:3088kB
+`, false,
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if got := rep.Symbolizable(); got != test.symbolizable {
+			t.Errorf("#%v: got symbolizable %v, want %v", i, got, test.symbolizable)
+		}
+	}
+	if (&Report{}).Symbolizable() {
+		t.Errorf("empty report is symbolizable")
+	}
+}
+
+func TestLinuxSourceLines(t *testing.T) {
+	kernelSrc, err := ioutil.TempDir("", "syz-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(kernelSrc)
+	files := map[string]string{
+		"net/socket.c":        "#include <linux/net.h>\n\nstatic int sock_poll(void)\n{\n\treturn sock->ops->poll();\n}\n",
+		"include/linux/bar.h": strings.Repeat("\n", 554) + "\tbar();   \n",
+		// Reports are symbolized with kernel sources, so maintainers are queried as well.
+		"scripts/get_maintainer.pl": "#!/bin/sh\necho linux-kernel@vger.kernel.org\n",
+	}
+	for name, data := range files {
+		file := filepath.Join(kernelSrc, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(data), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := Options{
+		Symbolizer: func() Symbolizer {
+			return fakeSymbolizer{new(int), new(int)}
+		},
+	}
+	reporter, err := NewReporterOptions("linux", kernelSrc, "/linux",
+		map[string][]symbolizer.Symbol{"foo": {{Addr: 0x1000000, Size: 0x190}}}, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reporter.Close()
+	const report = `BUG: KASAN: use-after-free in sock_poll+0x21/0x50
+Call Trace:
+ sock_poll+0x21/0x50 net/socket.c:5
+ sock_poll+0x21/0x50 net/socket.c:100
+ vfs_poll include/linux/poll.h:90 [inline]
+ do_select+0x3d2/0x870 ../fs/select.c:534
+`
+	rep := &Report{Report: []byte(report)}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	// The line is out of bounds, the file is missing or is outside of the source tree.
+	want := []string{"return sock->ops->poll();", "", "", ""}
+	if len(rep.Frames) != len(want) {
+		t.Fatalf("got %v frames, want %v", len(rep.Frames), len(want))
+	}
+	for i, frame := range rep.Frames {
+		if frame.SourceLine != want[i] {
+			t.Errorf("frame #%v %v:%v: got source line %q, want %q",
+				i, frame.File, frame.Line, frame.SourceLine, want[i])
+		}
+	}
+	// Frames symbolized separately get source lines as well.
+	frame := StackFrame{Func: "foo", Offset: 0x101, Size: 0x190}
+	if err := reporter.SymbolizeFrame(&frame); err != nil {
+		t.Fatal(err)
+	}
+	if frame.SourceLine != "bar();" {
+		t.Fatalf("bad source line of symbolized frame: %+v", frame)
+	}
+}
+
+func TestLinuxExcerpt(t *testing.T) {
+	tests := []struct {
+		log string
+		// dropped lines must not be present in the excerpt, kept lines must be present.
+		dropped []string
+		kept    []string
+	}{
+		{
+			`
+[   85.180000] syz-executor0: vmalloc: allocation failure: 0 bytes
+[   85.186292] ==================================================================
+[   85.186292] BUG: KASAN: use-after-free in sock_poll+0x21/0x50 net/socket.c:1150
+[   85.186292] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor0/4321
+[   85.186292] 
+[   85.186292] CPU: 0 PID: 4321 Comm: syz-executor0 Not tainted 4.15.0-rc5+ #1
+[   85.186292] Call Trace:
+[   85.186292]  __dump_stack lib/dump_stack.c:17 [inline]
+[   85.186292]  dump_stack+0x1b/0x20 lib/dump_stack.c:53
+[   85.186292]  sock_poll+0x21/0x50 net/socket.c:1150
+[   85.186292]  do_select+0x3d2/0x870 fs/select.c:534
+[   85.186292] 
+[   85.186292] Allocated by task 4321:
+[   85.186292]  kmem_cache_alloc+0x12e/0x760 mm/slab.c:3548
+[   85.186292]  sock_alloc_inode+0x70/0x300 net/socket.c:250
+[   85.186292] 
+[   85.186292] Freed by task 4321:
+[   85.186292]  kmem_cache_free+0x83/0x2a0 mm/slab.c:3756
+[   85.186292]  sock_destroy_inode+0x51/0x60 net/socket.c:280
+[   85.186292] 
+[   85.186292] The buggy address belongs to the object at ffff8801c6a1a040
+[   85.186292]  which belongs to the cache sock_inode_cache of size 992
+[   85.186292] The buggy address is located 64 bytes inside of
+[   85.186292]  992-byte region [ffff8801c6a1a040, ffff8801c6a1a420)
+[   85.186292] The buggy address belongs to the page:
+[   85.186292] page:ffffea00071a8680 count:1 mapcount:0 mapping:ffff8801c6a1a040 index:0x0
+[   85.186292] flags: 0x2fffc0000000100(slab)
+[   85.186292] raw: 02fffc0000000100 ffff8801c6a1a040 0000000000000000 0000000100000003
+[   85.186292] page dumped because: kasan: bad access detected
+[   85.186292] 
+[   85.186292] Memory state around the buggy address:
+[   85.186292]  ffff8801c6a19f80: fc fc fc fc fc fc fc fc fc fc fc fc fc fc fc fc
+[   85.186292] >ffff8801c6a1a080: fb fb fb fb fb fb fb fb fb fb fb fb fb fb fb fb
+[   85.186292]                    ^
+[   85.186292]  ffff8801c6a1a100: fb fb fb fb fb fb fb fb fb fb fb fb fb fb fb fb
+[   85.186292] ==================================================================
+`,
+			[]string{"vmalloc: allocation failure", "The buggy address", "page:", "flags:", "raw:",
+				"page dumped", "Memory state", "ffff8801c6a19f80:", "^"},
+			[]string{"BUG: KASAN: use-after-free", "Read of size 8", "CPU: 0 PID: 4321", "Call Trace:",
+				"sock_poll+0x21/0x50", "Allocated by task 4321:", "sock_alloc_inode", "Freed by task 4321:",
+				"sock_destroy_inode"},
+		},
+		{
+			`
+[   85.186292] general protection fault: 0000 [#1] PREEMPT SMP KASAN
+[   85.204858] CPU: 0 PID: 5092 Comm: syz-executor.3 Not tainted 6.2.0-rc7-syzkaller #0
+[   85.222440] RIP: 0010:tcp_v4_connect+0x4e4/0x1d90 net/ipv4/tcp_ipv4.c:312
+[   85.229399] Code: 48 89 fa 48 c1 ea 03 80 3c 02 00 0f 85 <80> 3c 02 00 0f 85 8b 13 00 00
+[   85.248294] RSP: 0018:ffffc90003e7f9b8 EFLAGS: 00010202
+[   85.253667] RAX: dffffc0000000000 RBX: 0000000000000000 RCX: 0000000000000000
+[   85.260936] R13: 0000000000000002 R14: ffffffff8a0b1c40 R15: 0000000000000010
+[   85.260936] FS:  00007f8c2a4d4700(0000) GS:ffff8880b9800000(0000) knlGS:0000000000000000
+[   85.268198] Call Trace:
+[   85.270780]  <TASK>
+[   85.273030]  tcp_v4_connect+0x4e4/0x1d90 net/ipv4/tcp_ipv4.c:312
+[   85.279382]  __inet_stream_connect+0x2a1/0xcd0 net/ipv4/af_inet.c:663
+[   85.297760]  </TASK>
+`,
+			[]string{"Code:", "RSP:", "RAX:", "R13:", "FS:"},
+			[]string{"general protection fault", "RIP: 0010:tcp_v4_connect", "Call Trace:", "<TASK>",
+				"__inet_stream_connect", "</TASK>"},
+		},
+		{
+			`
+[   76.649866] BUG: unable to handle kernel paging request at ffffffff8a1c2e5b
+[   76.649866] IP: ipmr_mfc_seq_stop+0xe4/0x140
+[   76.661705] CPU: 0 PID: 14413 Comm: syz-executor0 Not tainted 4.9.65-g8ae26d1 #98
+[   76.661725]  ffff8801ce46f9a0 ffffffff81d90469 ffff8801ce46fc80 0000000000000000
+[   76.661737]  ffff8801ccd7ad10 ffff8801ce46fb70 ffff8801ccd7ac00 ffff8801ce46fb98
+[   76.661751] Call Trace:
+[   76.661765]  [<ffffffff81d90469>] ipmr_mfc_seq_stop+0xe4/0x140
+[   76.661765]  [<ffffffff815e4ded>] seq_read+0xdd/0x1290
+`,
+			[]string{"ffff8801ce46f9a0 ffffffff81d90469"},
+			[]string{"BUG: unable to handle kernel paging request", "IP: ipmr_mfc_seq_stop", "Call Trace:",
+				"seq_read+0xdd/0x1290"},
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		excerpt := rep.Excerpt()
+		// The excerpt must be parseable with the same title.
+		rep1 := reporter.Parse(excerpt)
+		if rep1 == nil || rep1.Title != rep.Title {
+			t.Fatalf("#%v: excerpt is parsed as %+v, want title %q\nexcerpt:\n%s", i, rep1, rep.Title, excerpt)
+		}
+		for _, line := range strings.Split(string(excerpt), "\n") {
+			for _, dropped := range test.dropped {
+				if strings.HasPrefix(strings.TrimSpace(line), dropped) {
+					t.Errorf("#%v: excerpt contains %q:\n%s", i, dropped, excerpt)
+				}
+			}
+		}
+		for _, kept := range test.kept {
+			if !bytes.Contains(excerpt, []byte(kept)) {
+				t.Errorf("#%v: excerpt does not contain %q:\n%s", i, kept, excerpt)
+			}
+		}
+		// Excerpt does not share memory with the report.
+		if len(excerpt) != 0 {
+			excerpt[0] = 0
+			if rep.Report[0] == 0 {
+				t.Fatalf("#%v: excerpt shares memory with the report", i)
+			}
+		}
+	}
+}
+
+func TestLinuxPageOwner(t *testing.T) {
+	const log = `
+[  212.579138] BUG: Bad page state in process syz-executor.2  pfn:2b0ec
+[  212.585664] page:ffffea0000ac3b00 refcount:0 mapcount:0 mapping:0000000000000000 index:0x0 pfn:0x2b0ec
+[  212.595080] flags: 0xfff00000000200(slab|node=0|zone=1|lastcpupid=0x7ff)
+[  212.601770] raw: 00fff00000000200 dead000000000100 dead000000000122 0000000000000000
+[  212.609644] page dumped because: PAGE_FLAGS_CHECK_AT_FREE flag(s) set
+[  212.616223] page_owner tracks the page as allocated
+[  212.621231] page last allocated via order 0, migratetype Unmovable, gfp_mask 0x820c0(__GFP_ZERO|__GFP_NOMEMALLOC), pid 5093, tgid 5092 (syz-executor.2), ts 212231384926, free_ts 0
+[  212.637834]  post_alloc_hook+0x2d2/0x350 mm/page_alloc.c:1533
+[  212.643727]  prep_new_page mm/page_alloc.c:1540 [inline]
+[  212.643727]  get_page_from_freelist+0x10a6/0x31e0 mm/page_alloc.c:3311
+[  212.650629]  __alloc_pages+0x1d0/0x4a0 mm/page_alloc.c:4567
+[  212.656588]  alloc_pages_mpol+0x258/0x5f0 mm/mempolicy.c:2133
+[  212.662559]  skb_page_frag_refill+0x23c/0x3e0 net/core/sock.c:2878
+[  212.669276]  tun_build_skb drivers/net/tun.c:1610 [inline]
+[  212.669276]  tun_get_user+0x6f1/0x3a50 drivers/net/tun.c:1797
+[  212.675323]  tun_chr_write_iter+0xdc/0x200 drivers/net/tun.c:2039
+[  212.682031] page last free stack trace:
+[  212.686326]  free_unref_page_prepare mm/page_alloc.c:2346 [inline]
+[  212.686326]  free_unref_page+0x33/0x3b0 mm/page_alloc.c:2486
+[  212.692575]  vfs_write+0x650/0xd80 fs/read_write.c:586
+[  212.698178] Modules linked in:
+[  212.701475] CPU: 1 PID: 5093 Comm: syz-executor.2 Not tainted 6.6.0-rc5-syzkaller #0
+[  212.709324] Call Trace:
+[  212.711878]  <TASK>
+[  212.714119]  __dump_stack lib/dump_stack.c:88 [inline]
+[  212.714119]  dump_stack_lvl+0xd9/0x1b0 lib/dump_stack.c:106
+[  212.719797]  bad_page+0xd6/0x150 mm/page_alloc.c:494
+[  212.724955]  free_unref_page+0x33/0x3b0 mm/page_alloc.c:2486
+[  212.731103]  __put_page+0x4e/0x60 mm/swap.c:127
+[  212.736209]  skb_release_data+0x5a2/0x850 net/core/skbuff.c:1006
+[  212.742545]  tcp_recvmsg+0x1a6/0x5a0 net/ipv4/tcp.c:2571
+[  212.748062]  </TASK>
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	var stack *AuxStack
+	for i := range rep.AuxStacks {
+		if rep.AuxStacks[i].Title == "page last allocated via" {
+			stack = &rep.AuxStacks[i]
+		}
+	}
+	if stack == nil {
+		t.Fatalf("no page owner stack in %+v", rep.AuxStacks)
+	}
+	// The stack ends before the free stack.
+	var funcs []string
+	for _, frame := range stack.Frames {
+		funcs = append(funcs, frame.Func)
+	}
+	want := []string{"post_alloc_hook", "prep_new_page", "get_page_from_freelist", "__alloc_pages",
+		"alloc_pages_mpol", "skb_page_frag_refill", "tun_build_skb", "tun_get_user", "tun_chr_write_iter"}
+	if !reflect.DeepEqual(funcs, want) {
+		t.Fatalf("got page owner frames %q, want %q", funcs, want)
+	}
+	// The page allocator frames are skipped and net/core/sock.c is blacklisted,
+	// otherwise the guilty file would be mm/mempolicy.c (or mm/swap.c from the main stack).
+	if rep.GuiltyFile != "drivers/net/tun.c" {
+		t.Fatalf("got guilty file %q, want drivers/net/tun.c", rep.GuiltyFile)
+	}
+	rep.MatchedFormat = "bad-page-map"
+	if files := pageOwnerFiles(rep); len(files) == 0 || files[0] != "net/core/sock.c" {
+		t.Fatalf("bad page owner files: %q", files)
+	}
+	// The page allocation stack is not used for other reports.
+	rep.MatchedFormat = "kasan"
+	if files := pageOwnerFiles(rep); files != nil {
+		t.Fatalf("got page owner files %q for a KASAN report", files)
+	}
+}
+
+func TestLinuxSuspiciousRCUProtection(t *testing.T) {
+	tests := []struct {
+		log        string
+		protection string
+		lock       string
+	}{
+		{
+			log: `
+[  211.303113] WARNING: suspicious RCU usage
+[  211.303126] -----------------------------
+[  211.303133] ./include/linux/rcupdate.h:631 rcu_read_lock() used illegally while idle!
+[  211.303213] Call Trace:
+[  211.303243]  lockdep_rcu_suspicious+0x14a/0x153 kernel/locking/lockdep.c:4562
+[  211.303259]  trace_call_bpf+0x5b1/0x620 kernel/trace/bpf_trace.c:60
+`,
+			protection: "rcu_read_lock() used illegally while idle",
+			lock:       "rcu_read_lock",
+		},
+		{
+			log: `
+[  211.303113] WARNING: suspicious RCU usage
+[  211.303126] -----------------------------
+[  211.303133] ./include/linux/rcupdate.h:706 rcu_read_unlock_bh() used illegally while idle!
+[  211.303213] Call Trace:
+[  211.303243]  lockdep_rcu_suspicious+0x14a/0x153 kernel/locking/lockdep.c:4562
+`,
+			protection: "rcu_read_unlock_bh() used illegally while idle",
+			lock:       "rcu_read_lock_bh",
+		},
+		{
+			log: `
+[  211.303113] WARNING: suspicious RCU usage
+[  211.303126] -----------------------------
+[  211.303133] ./include/linux/srcu.h:282 srcu_read_unlock() used illegally while idle!
+[  211.303213] Call Trace:
+[  211.303243]  lockdep_rcu_suspicious+0x14a/0x153 kernel/locking/lockdep.c:4562
+`,
+			protection: "srcu_read_unlock() used illegally while idle",
+			lock:       "srcu_read_lock",
+		},
+		{
+			// Not an RCU read-side lock.
+			log: `
+[  211.303113] WARNING: suspicious RCU usage
+[  211.303126] -----------------------------
+[  211.303133] ./include/linux/foo.h:10 foo_unlock() used illegally while idle!
+[  211.303213] Call Trace:
+[  211.303243]  lockdep_rcu_suspicious+0x14a/0x153 kernel/locking/lockdep.c:4562
+`,
+			protection: "foo_unlock() used illegally while idle",
+			lock:       "",
+		},
+		{
+			log: `
+[ 1722.511384] [ INFO: suspicious RCU usage. ]
+[ 1722.511384] -------------------------------
+[ 1722.511384] net/ipv6/ip6_flowlabel.c:544 suspicious rcu_dereference_check() usage!
+[ 1722.511384] Call Trace:
+[ 1722.511384]  lockdep_rcu_suspicious+0x123/0x170 kernel/locking/lockdep.c:4585
+`,
+			protection: "suspicious rcu_dereference_check() usage",
+			lock:       "rcu_read_lock",
+		},
+		{
+			log: `
+[  305.115291] WARNING: suspicious RCU usage
+[  305.115313] net/ipv4/ipmr.c:1757 RCU-list traversed in non-reader section!!
+[  305.115392] Call Trace:
+[  305.115422]  lockdep_rcu_suspicious+0x153/0x15d kernel/locking/lockdep.c:5444
+`,
+			protection: "RCU-list traversed in non-reader section",
+			lock:       "rcu_read_lock",
+		},
+		{
+			log: `
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375045] ./include/linux/rcupdate.h:302 Illegal context switch in RCU-bh read-side critical section!
+[  129.375234] Call Trace:
+[  129.375285]  lockdep_rcu_suspicious+0x123/0x170 kernel/locking/lockdep.c:4585
+`,
+			protection: "Illegal context switch in RCU-bh read-side critical section",
+			lock:       "rcu_read_lock_bh",
+		},
+		{
+			// The protecting lock is specific to the subsystem.
+			log: `
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375045] net/core/filter.c:1145 suspicious rcu_dereference_protected() usage!
+[  129.375234] Call Trace:
+[  129.375285]  lockdep_rcu_suspicious+0x123/0x170
+`,
+			protection: "suspicious rcu_dereference_protected() usage",
+			lock:       "",
+		},
+		{
+			log: `
+[  129.375001] WARNING: suspicious RCU usage
+[  129.375234] Call Trace:
+`,
+			protection: "",
+			lock:       "",
+		},
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range tests {
+		rep := reporter.Parse([]byte(test.log))
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if rep.RCUProtection != test.protection || rep.RCULock != test.lock {
+			t.Fatalf("#%v: got protection %q, lock %q, want %q, %q",
+				i, rep.RCUProtection, rep.RCULock, test.protection, test.lock)
+		}
+	}
+}
+
+func TestLinuxWarningMessages(t *testing.T) {
+	tests := []struct {
+		log      string
+		title    string
+		messages string
+	}{
+		{
+			log: `
+[  140.614801] WARNING: CPU: 1 PID: 7733 at mm/vmalloc.c:1473 __vunmap+0x1ca/0x300 mm/vmalloc.c:1472()
+[  140.614801] Trying to vfree() bad address (ffff8800b3254fc0)
+[  140.614801] Kernel panic - not syncing: panic_on_warn set ...
+`,
+			title:    "WARNING in __vunmap",
+			messages: "WARNING in __vunmap: Trying to vfree() bad address (ADDR)",
+		},
+		{
+			log: `
+[  753.120788] WARNING: CPU: 0 PID: 0 at net/sched/sch_generic.c:316 dev_watchdog+0x648/0x770
+[  753.122260] NETDEV WATCHDOG: eth0 (e1000): transmit queue 0 timed out
+`,
+			title:    "WARNING in dev_watchdog",
+			messages: "WARNING in dev_watchdog: NETDEV WATCHDOG: eth0 (e1000): transmit queue NUM timed out",
+		},
+		{
+			log: `
+[  140.614801] WARNING: CPU: 2 PID: 24023 at kernel/locking/lockdep.c:3344 __lock_acquire+0x10e5/0x3690 kernel/locking/lockdep.c:3344
+[  140.614801] Kernel panic - not syncing: panic_on_warn set ...
+`,
+			title:    "WARNING in __lock_acquire",
+			messages: "WARNING in __lock_acquire",
+		},
+	}
+	for _, messages := range []bool{false, true} {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{WarningMessages: messages})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, test := range tests {
+			want := test.title
+			if messages {
+				want = test.messages
+			}
+			rep := reporter.Parse([]byte(test.log))
+			if rep == nil {
+				t.Fatalf("#%v: no report", i)
+			}
+			if rep.Title != want {
+				t.Fatalf("#%v: messages %v: got title %q, want %q", i, messages, rep.Title, want)
+			}
+			if title := reporter.Title([]byte(test.log)); title != want {
+				t.Fatalf("#%v: messages %v: got Title %q, want %q", i, messages, title, want)
+			}
+		}
+	}
+}
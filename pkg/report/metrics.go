@@ -0,0 +1,54 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"time"
+)
+
+// MetricsSink receives statistics about Parse/Symbolize calls (see SetMetricsSink).
+// Methods are called synchronously from Parse/ParseFrom/Symbolize, potentially
+// concurrently from several goroutines. So implementations must be thread-safe
+// and should be fast (e.g. just update counters); they must not call back into the package.
+type MetricsSink interface {
+	// Parsed is called after each parse of console output.
+	// crash says if a crash was found, corrupted says if the crash report is corrupted.
+	Parsed(crash, corrupted bool)
+	// Symbolized is called after each Symbolize call with its duration and result.
+	Symbolized(duration time.Duration, err error)
+}
+
+var metricsSink MetricsSink
+
+// SetMetricsSink sets sink that receives statistics from all reporters (nil disables metrics).
+// There is no overhead when no sink is set.
+// SetMetricsSink is not synchronized with reporters, so it must be called
+// before reporters are used (e.g. during program initialization).
+// Note: the reporter created with NewAutoReporter reports parses done by
+// reporters for individual OSes, so one Parse call can be counted several times.
+func SetMetricsSink(sink MetricsSink) {
+	metricsSink = sink
+}
+
+func metricsParsed(rep *Report) {
+	if metricsSink == nil {
+		return
+	}
+	metricsSink.Parsed(rep != nil, rep != nil && rep.Corrupted)
+}
+
+// metricsStart returns start time for metricsSymbolized (zero time if no sink is set).
+func metricsStart() time.Time {
+	if metricsSink == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func metricsSymbolized(start time.Time, err error) {
+	if metricsSink == nil || start.IsZero() {
+		return
+	}
+	metricsSink.Symbolized(time.Since(start), err)
+}
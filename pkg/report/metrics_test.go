@@ -0,0 +1,62 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSink struct {
+	mu         sync.Mutex
+	parses     int
+	crashes    int
+	corrupted  int
+	symbolized int
+}
+
+func (sink *testSink) Parsed(crash, corrupted bool) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.parses++
+	if crash {
+		sink.crashes++
+	}
+	if corrupted {
+		sink.corrupted++
+	}
+}
+
+func (sink *testSink) Symbolized(duration time.Duration, err error) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.symbolized++
+}
+
+func TestMetricsSink(t *testing.T) {
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := new(testSink)
+	SetMetricsSink(sink)
+	defer SetMetricsSink(nil)
+	rep := reporter.Parse([]byte(`
+[  772.918915] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[  772.919010] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+`))
+	if rep == nil {
+		t.Fatalf("no report")
+	}
+	if err := reporter.Symbolize(rep); err != nil {
+		t.Fatal(err)
+	}
+	if reporter.Parse([]byte("no crash here\n")) != nil {
+		t.Fatalf("unexpected report")
+	}
+	if sink.parses != 2 || sink.crashes != 1 || sink.corrupted != 1 || sink.symbolized != 1 {
+		t.Fatalf("bad metrics: %+v", sink)
+	}
+}
@@ -4,6 +4,7 @@
 package report
 
 import (
+	"context"
 	"regexp"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
@@ -17,7 +18,7 @@ type netbsd struct {
 }
 
 func ctorNetbsd(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
 	ctx := &netbsd{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
@@ -31,10 +32,34 @@ func (ctx *netbsd) ContainsCrash(output []byte) bool {
 	return false
 }
 
+func (ctx *netbsd) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	return false, 0, 0
+}
+
 func (ctx *netbsd) Parse(output []byte) *Report {
 	return nil
 }
 
+func (ctx *netbsd) ParseFrom(output []byte, startPos int) *Report {
+	return nil
+}
+
+func (ctx *netbsd) Title(output []byte) string {
+	return ""
+}
+
 func (ctx *netbsd) Symbolize(rep *Report) error {
 	return nil
 }
+
+func (ctx *netbsd) SymbolizeContext(c context.Context, rep *Report) error {
+	return nil
+}
+
+func (ctx *netbsd) SymbolizeFrame(frame *StackFrame) error {
+	return nil
+}
+
+func (ctx *netbsd) Close() error {
+	return nil
+}
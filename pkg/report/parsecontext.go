@@ -0,0 +1,158 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+)
+
+// ParseContext is console output prepared for a sequence of reporter calls on it
+// (e.g. ContainsCrash followed by Parse). Line boundaries are computed once,
+// and position of the first oops found by ContainsCrash is reused by Parse,
+// so a typical detect-then-parse flow scans the output once.
+// Results are the same as of the corresponding Reporter methods.
+// Reporters that don't support line sharing (currently all except linux,
+// and reporters with title or corruption options) simply fall back to the Reporter methods.
+// ParseContext is not safe for concurrent use.
+type ParseContext struct {
+	reporter Reporter
+	output   []byte
+	lines    *outputLines
+	// oopsLine is index of the first line with an oops header (-1 if there is none),
+	// valid only if oopsKnown is set.
+	oopsLine  int
+	oopsKnown bool
+	// State of Append: output[:scanned] is already fed to stream,
+	// owned is set once output is copied from the buffer passed to NewParseContext.
+	stream  *streamParser
+	reports []*Report
+	scanned int
+	owned   bool
+}
+
+// linesReporter is implemented by reporters that can work on precomputed lines.
+type linesReporter interface {
+	findOopsLine(lines *outputLines) int
+	parseLines(lines *outputLines, oopsLine int) *Report
+}
+
+// NewParseContext creates a parse context for output, output must not be modified while the context is used.
+func NewParseContext(reporter Reporter, output []byte) *ParseContext {
+	return &ParseContext{
+		reporter: reporter,
+		output:   output,
+	}
+}
+
+// ContainsCrash is Reporter.ContainsCrash for the output.
+func (pc *ParseContext) ContainsCrash() bool {
+	lr, ok := pc.reporter.(linesReporter)
+	if !ok {
+		return pc.reporter.ContainsCrash(pc.output)
+	}
+	return pc.findOops(lr) != -1
+}
+
+// Parse is Reporter.Parse for the output, it does not rescan the output before the oops
+// if ContainsCrash was called before.
+func (pc *ParseContext) Parse() *Report {
+	lr, ok := pc.reporter.(linesReporter)
+	if !ok {
+		return pc.reporter.Parse(pc.output)
+	}
+	return lr.parseLines(pc.lines, pc.findOops(lr))
+}
+
+// Append appends data to the output and returns crashes whose regions are completed by the appended data
+// (see ParseStream for what completes a region). Only the appended data (and the incomplete last line)
+// is scanned, so repeated Append calls on a growing output take linear time in total.
+// Output, StartPos and EndPos of the returned reports refer to the crash region rather than to the whole output.
+// The output passed to NewParseContext is not modified, ContainsCrash and Parse work on the whole
+// output including the appended data.
+func (pc *ParseContext) Append(data []byte) []*Report {
+	if !pc.owned {
+		pc.output = append([]byte{}, pc.output...)
+		pc.owned = true
+	}
+	pc.output = append(pc.output, data...)
+	pc.lines = nil
+	pc.oopsKnown = false
+	if pc.stream == nil {
+		pc.stream = newStreamParser(pc.reporter, nil, func(rep *Report) { pc.reports = append(pc.reports, rep) })
+	}
+	for {
+		next := bytes.IndexByte(pc.output[pc.scanned:], '\n')
+		if next == -1 {
+			break
+		}
+		pc.stream.line(pc.output[pc.scanned : pc.scanned+next+1])
+		pc.scanned += next + 1
+	}
+	return pc.takeReports()
+}
+
+// Flush returns the crash in the incomplete region of the output fed with Append (including
+// the unterminated last line), e.g. when the output is not going to grow anymore.
+// The region is dropped, so subsequent Append calls start a new region.
+func (pc *ParseContext) Flush() []*Report {
+	if pc.stream == nil {
+		return nil
+	}
+	if pc.scanned < len(pc.output) {
+		pc.stream.line(pc.output[pc.scanned:])
+		pc.scanned = len(pc.output)
+	}
+	pc.stream.flush()
+	return pc.takeReports()
+}
+
+func (pc *ParseContext) takeReports() []*Report {
+	reports := pc.reports
+	pc.reports = nil
+	return reports
+}
+
+func (pc *ParseContext) findOops(lr linesReporter) int {
+	if !pc.oopsKnown {
+		if pc.lines == nil {
+			pc.lines = newOutputLines(pc.output)
+		}
+		pc.oopsLine = lr.findOopsLine(pc.lines)
+		pc.oopsKnown = true
+	}
+	return pc.oopsLine
+}
+
+// outputLines is console output with precomputed line boundaries.
+type outputLines struct {
+	output []byte
+	// starts are start positions of lines, the last line may lack the trailing '\n'.
+	starts []int
+}
+
+func newOutputLines(output []byte) *outputLines {
+	lines := &outputLines{output: output}
+	for pos := 0; pos < len(output); {
+		lines.starts = append(lines.starts, pos)
+		next := bytes.IndexByte(output[pos:], '\n')
+		if next == -1 {
+			break
+		}
+		pos += next + 1
+	}
+	return lines
+}
+
+func (lines *outputLines) count() int {
+	return len(lines.starts)
+}
+
+// bounds returns bounds of line i without the trailing '\n'.
+func (lines *outputLines) bounds(i int) (int, int) {
+	start, end := lines.starts[i], len(lines.output)
+	if i+1 < len(lines.starts) {
+		end = lines.starts[i+1] - 1
+	}
+	return start, end
+}
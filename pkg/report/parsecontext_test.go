@@ -0,0 +1,120 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func BenchmarkLinuxContainsCrashParse(b *testing.B) {
+	benchmarkLinux(b, func(reporter Reporter, log []byte) {
+		if reporter.ContainsCrash(log) {
+			reporter.Parse(log)
+		}
+	})
+}
+
+func BenchmarkLinuxParseContext(b *testing.B) {
+	benchmarkLinux(b, func(reporter Reporter, log []byte) {
+		pc := NewParseContext(reporter, log)
+		if pc.ContainsCrash() {
+			pc.Parse()
+		}
+	})
+}
+
+func TestParseContextAppend(t *testing.T) {
+	const warning = `[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/core/dev.c:200
+[   42.320059] ---[ end trace 9d5a4b3c2a1f0e7d ]---
+`
+	const kasan = `[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166`
+	noise := strings.Repeat("[   10.000000] random console noise\n", 20)
+	log := noise + warning + noise + kasan
+	warningEnd := len(noise + warning)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range []int{1, 7, 100, len(log)} {
+		// The initial output has spare capacity that must not be overwritten by Append.
+		initial := make([]byte, len(noise), len(noise)+10)
+		copy(initial, noise)
+		pc := NewParseContext(reporter, initial)
+		var titles []string
+		for pos := len(noise); pos < len(log); pos += chunk {
+			end := pos + chunk
+			if end > len(log) {
+				end = len(log)
+			}
+			for _, rep := range pc.Append([]byte(log[pos:end])) {
+				if len(titles) == 0 && end < warningEnd {
+					t.Errorf("chunk %v: the first report is emitted before the end marker (%v bytes)", chunk, end)
+				}
+				if len(titles) == 0 && !strings.Contains(string(rep.Output), "cut here") {
+					t.Errorf("chunk %v: the first report lost preceding lines:\n%s", chunk, rep.Output)
+				}
+				titles = append(titles, rep.Title)
+			}
+			if end >= warningEnd && len(titles) == 0 {
+				t.Fatalf("chunk %v: the first report is not emitted after %v bytes", chunk, end)
+			}
+		}
+		// The last report is not terminated with the end marker.
+		if !reflect.DeepEqual(titles, []string{"WARNING in foo_bar"}) {
+			t.Fatalf("chunk %v: got reports %q before Flush", chunk, titles)
+		}
+		for _, rep := range pc.Flush() {
+			titles = append(titles, rep.Title)
+		}
+		want := []string{"WARNING in foo_bar", "KASAN: use-after-free Read in ip6_dst_store"}
+		if !reflect.DeepEqual(titles, want) {
+			t.Fatalf("chunk %v: got reports %q, want %q", chunk, titles, want)
+		}
+		if reports := pc.Flush(); len(reports) != 0 {
+			t.Fatalf("chunk %v: repeated Flush returned %v reports", chunk, len(reports))
+		}
+		if extra := initial[len(initial):cap(initial)]; strings.Trim(string(extra), "\x00") != "" {
+			t.Fatalf("chunk %v: the initial output is overwritten: %q", chunk, extra)
+		}
+		// The other methods work on the whole output.
+		if !pc.ContainsCrash() {
+			t.Fatalf("chunk %v: ContainsCrash is false", chunk)
+		}
+		if rep := pc.Parse(); rep == nil || rep.Title != want[0] || len(rep.Output) != len(log) {
+			t.Fatalf("chunk %v: bad Parse result: %+v", chunk, rep)
+		}
+	}
+	// A new unrelated oops completes the region of the previous one without an end marker.
+	const warningNoEnd = `[   68.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   68.306981] Call Trace:
+[   68.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+`
+	pc := NewParseContext(reporter, nil)
+	var titles []string
+	for _, data := range []string{kasan + "\n", "[   67.392150] =====================================\n", warningNoEnd} {
+		for _, rep := range pc.Append([]byte(data)) {
+			titles = append(titles, rep.Title)
+		}
+	}
+	if want := []string{"KASAN: use-after-free Read in ip6_dst_store"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("got reports %q before Flush, want %q", titles, want)
+	}
+	for _, rep := range pc.Flush() {
+		titles = append(titles, rep.Title)
+	}
+	want := []string{"KASAN: use-after-free Read in ip6_dst_store", "WARNING in foo_bar"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Fatalf("got reports %q, want %q", titles, want)
+	}
+}
@@ -7,9 +7,13 @@ package report
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
 )
@@ -18,28 +22,797 @@ type Reporter interface {
 	// ContainsCrash searches kernel console output for oops messages.
 	ContainsCrash(output []byte) bool
 
+	// ContainsCrashDetailed is like ContainsCrash, but also returns number of lines
+	// that matched an oops header but were dropped by built-in suppressions
+	// or by user-supplied ignores. This allows to distinguish "no crash"
+	// from "crash filtered out".
+	ContainsCrashDetailed(output []byte) (found bool, suppressed int, ignored int)
+
 	// Parse extracts information about oops from console output.
 	// Returns nil if no oops found.
 	Parse(output []byte) *Report
 
+	// ParseFrom is like Parse, but starts matching at startPos in output.
+	// Content before startPos is ignored, but StartPos/EndPos in the returned
+	// report are still relative to the whole output.
+	ParseFrom(output []byte, startPos int) *Report
+
+	// Title returns the same title as Parse, or empty string if no oops found.
+	// It skips extraction of all other report information, so it is cheaper than Parse.
+	Title(output []byte) string
+
 	// Symbolize symbolizes rep.Report and fills in Maintainers.
+	// It's the batch equivalent of SymbolizeFrame for all frames in the report.
 	Symbolize(rep *Report) error
+
+	// SymbolizeContext is like Symbolize, but aborts symbolization when c is cancelled.
+	// In such case it returns c.Err() and rep.Report is partially symbolized.
+	SymbolizeContext(c context.Context, rep *Report) error
+
+	// SymbolizeFrame resolves source location of a single frame in place.
+	// It allows to symbolize frames lazily, instead of the whole report at once.
+	SymbolizeFrame(frame *StackFrame) error
+
+	// Close shuts down helper processes kept by the reporter (e.g. the symbolizer
+	// used by SymbolizeFrame) and drops caches. The reporter must not be used after Close.
+	Close() error
 }
 
 type Report struct {
 	// Title contains a representative description of the first oops.
+	// Symbolize can refine it for some crash formats (e.g. suspicious RCU usage
+	// is attributed to a function instead of a source file).
 	Title string
 	// Report contains whole oops text.
 	Report []byte
 	// Output contains whole raw console output as passed to Reporter.Parse.
 	Output []byte
-	// StartPos/EndPos denote region of output with oops message(s).
+	// StartPos/EndPos denote region of output with oops message(s)
+	// (StartPos includes the preceding context with Options.ContextWindow).
 	StartPos int
 	EndPos   int
 	// Corrupted indicates whether the report is truncated of corrupted in some other way.
 	Corrupted bool
-	// Maintainers is list of maintainer emails.
+	// CorruptedReason contains reason why the report is marked as corrupted.
+	CorruptedReason string
+	// OopsCount is the largest oops counter ("[#N]") found in the report, 0 if there is none.
+	OopsCount int
+	// Recursive is set if the kernel oopsed again while handling the first oops.
+	Recursive bool
+	// Fatal is set if the kernel reported that it can't recover after the oops
+	// ("Fixing recursive fault but reboot is needed!"), i.e. the task oopsed while exiting
+	// after a previous oops (such reports are also Recursive). Currently set only for linux.
+	Fatal bool
+	// Syscall is the name of the syscall that led to the crash (e.g. "ioctl"),
+	// detected by syscall entry frames in the stack. Empty if there are no such frames.
+	Syscall string
+	// HeldLocks are locks listed in the "Showing all locks held in the system:" section
+	// (printed for lockups and hung tasks), nil if there is no such section.
+	HeldLocks []HeldLock
+	// Comm is the name of the task that crashed (from the "CPU: N PID: N Comm: ..." line).
+	Comm string
+	// Taint contains taint flags of the kernel (e.g. "GW"), empty if the kernel is not tainted.
+	Taint string
+	// Arch is the architecture the report was parsed for (Options.Arch or detected one).
+	// Currently set only for linux.
+	Arch string
+	// KernelVersion is the kernel release as printed in the "Comm: ..." line (e.g. "4.15.0-rc4+").
+	KernelVersion string
+	// PageFault is set if the report contains x86 "Oops: CODE [#N]" line;
+	// then the Fault* fields contain decoded bits of the page fault error code.
+	// The fields are not set for other architectures since the code has different format there.
+	PageFault bool
+	// FaultProtection is set for a protection violation, unset for a not-present page.
+	FaultProtection bool
+	// FaultWrite is set for a write access, unset for a read access.
+	FaultWrite bool
+	// FaultUser is set if the access was from user mode.
+	FaultUser bool
+	// FaultReserved is set if a reserved bit was set in a page table entry.
+	FaultReserved bool
+	// FaultInstruction is set for an instruction fetch.
+	FaultInstruction bool
+	// NonCanonical is set for general protection faults on a non-canonical address (x86),
+	// NonCanonicalAddr is the address.
+	NonCanonical     bool
+	NonCanonicalAddr uint64
+	// FaultAddr is the address that the kernel failed to access, as printed in
+	// "BUG: unable to handle kernel paging request at ..." line and alike (set if HasFaultAddr).
+	FaultAddr    uint64
+	HasFaultAddr bool
+	// ExecFault is set if the kernel tried to execute non-executable memory
+	// (NX-protected page, user memory with SMEP enabled or a bad RIP value).
+	ExecFault bool
+	// UserAccessFault is set if the kernel accessed user memory outside of uaccess routines
+	// (arm64 PAN or riscv SUM violation, the equivalent of x86 SMAP).
+	UserAccessFault bool
+	// InfraLimit is set if the report is not a kernel bug, but exhaustion of debugging
+	// infrastructure limits (e.g. "BUG: MAX_LOCKDEP_ENTRIES too low!"). Such reports
+	// get SeverityInfo by default and can be suppressed with Options.SuppressInfraLimits.
+	InfraLimit bool
+	// Informational is set if the report is an informational kernel message that can trip
+	// crash detection rather than a crash (e.g. clocksource watchdog marking TSC unstable).
+	// Such reports get SeverityInfo by default and can be suppressed with Options.SuppressInformational.
+	// They are reported only if there are no other crashes following them in the output.
+	Informational bool
+	// RCUProtection is the specific RCU checking message of a suspicious RCU usage report
+	// without the trailing "!" (e.g. "suspicious rcu_dereference_check() usage" or
+	// "rcu_read_lock() used illegally while idle"). Currently set only for linux.
+	RCUProtection string
+	// RCULock is the RCU read-side lock that should have been held according to RCUProtection
+	// (e.g. "rcu_read_lock_bh"), or that was used illegally (e.g. held across a context switch).
+	// Empty if the message does not identify the lock (e.g. rcu_dereference_protected()
+	// requires a subsystem-specific lock).
+	RCULock string
+	// NullPtrDeref is set if the crash looks like a NULL pointer dereference: FaultAddr
+	// is within the first page, or KASAN attributed a non-canonical address GPF to a NULL address
+	// (otherwise the non-canonical address is a wild pointer).
+	NullPtrDeref bool
+	// FaultPC is the faulting function parsed from "RIP: 0010:func+0x10/0x20" line (x86, set if HasFaultPC).
+	// File and Line are set if the line is symbolized. FaultPC is preferred for GuiltyFile
+	// since it's the exact faulting location even if the stack trace is messy.
+	FaultPC    StackFrame
+	HasFaultPC bool
+	// ESR is the arm64 exception syndrome register value printed by die() (set if HasESR).
+	// On 32-bit arm the die() line contains fault status register value, which is also stored here.
+	ESR    uint64
+	HasESR bool
+	// CodeBytes are the instruction bytes around the faulting instruction from "Code:" line,
+	// nil if there is no such line or it can't be parsed.
+	CodeBytes []byte
+	// CodeFault is index of the faulting instruction start in CodeBytes (marked as <XX>
+	// in the "Code:" line), -1 if the line has no marker. Valid only if CodeBytes is not nil.
+	CodeFault int
+	// Boot is index of the boot in the console output that the crash happened in
+	// (boots are separated by "Linux version ..." banners on linux). Set only by ParseAll.
+	Boot int
+	// MatchedFormat is the name of the crash format that produced Title (see Options.DisableFormats).
+	// Empty if no specific format matched and the title is the bare oops header line.
+	MatchedFormat string
+	// Confidence reflects how reliably Title was derived (see Confidence* constants).
+	Confidence float64
+	// Unclassified is set if the oops header was detected, but none of the crash formats
+	// for it matched, so the title is derived from the bare header line (see Options.RawUnclassifiedTitles).
+	// Currently set only for linux.
+	Unclassified bool
+	// ExecutorCrash is set if the crash is a failure of syzkaller's own processes
+	// (e.g. syz-fuzzer panicking due to executor failure) rather than a kernel bug.
+	ExecutorCrash bool
+	// Severity grades the crash (see Options.SeverityFunc).
+	Severity Severity
+	// Maintainers is list of maintainer emails (individuals first, then mailing lists,
+	// each group sorted lexicographically).
 	Maintainers []string
+	// Frames is the main stack trace of the oops (refined by Reporter.Symbolize).
+	Frames []StackFrame
+	// AuxStacks are additional stack traces found in the report (e.g. backtraces of other CPUs).
+	AuxStacks []AuxStack
+	// AllocFunc and FreeFunc are the functions that allocated and freed the accessed object
+	// according to KASAN "Allocated by task N:"/"Freed by task N:" stacks (these stacks are
+	// also present in AuxStacks). KASAN and allocator functions are skipped, compiler suffixes
+	// are stripped. Empty if the report does not have such stacks.
+	AllocFunc string
+	FreeFunc  string
+	// GuiltyFile is the source file selected as the cause of the crash (filled by Reporter.Symbolize).
+	GuiltyFile string
+	// GuiltyFrame is index into Frames of the frame selected as guilty, or -1.
+	// It is consistent with GuiltyFile (if set, the frame is in GuiltyFile).
+	GuiltyFrame int
+	// stackText extracts the main stack trace from Report text for the given Arch (see StackText).
+	// Nil if the reporter does not support this or the crash format does not have stacks.
+	stackText func(arch string, report []byte) []byte
+	// excerpt builds Excerpt from Report text, nil if the reporter does not support this.
+	excerpt func(report []byte) []byte
+	// stuckStack is the part of Report with the stack of the stuck CPU if the main stack
+	// (Frames) was taken from it, e.g. for RCU stalls and hard lockups (linux only).
+	stuckStack []byte
+}
+
+// StackText returns text of the main stack trace in Report without the stack header line
+// (e.g. for diffing stacks of two crashes). The text is extracted from the current Report,
+// so it works both before and after Symbolize.
+// Returns nil if there is no stack trace or the crash format does not have stacks.
+// Currently supported only for linux.
+func (rep *Report) StackText() []byte {
+	if rep.stackText == nil {
+		return nil
+	}
+	return rep.stackText(rep.Arch, rep.Report)
+}
+
+// Excerpt returns a trimmed copy of Report text suitable for sharing: the oops header lines,
+// the main stack and auxiliary stacks without memory/register dumps and console output
+// preceding the oops. The excerpt is a valid report on its own: parsing it yields the same title
+// as parsing Report (if trimming changes the title, the whole Report is returned).
+// Works both before and after Symbolize. Currently supported only for linux,
+// other reporters return the whole Report.
+func (rep *Report) Excerpt() []byte {
+	if rep.excerpt == nil {
+		return append([]byte{}, rep.Report...)
+	}
+	return rep.excerpt(rep.Report)
+}
+
+// FrameSignature returns names of the top n functions of the main stack trace (Frames)
+// for clustering of similar crashes. Frames of crash reporting machinery (e.g. dump_stack,
+// KASAN/UBSAN runtime) and unreliable frames are skipped, compiler suffixes (e.g. ".isra.7") are stripped.
+// Returns fewer than n names if the stack is shorter. Inlined frames are present only
+// in symbolized reports, so signatures should be compared across reports in the same state.
+func (rep *Report) FrameSignature(n int) []string {
+	var sig []string
+	for _, idx := range rep.signatureFrames() {
+		if len(sig) >= n {
+			break
+		}
+		sig = append(sig, baseFuncName(rep.Frames[idx].Func))
+	}
+	return sig
+}
+
+// signatureFrames returns indices into Frames of the frames that constitute FrameSignature.
+func (rep *Report) signatureFrames() []int {
+	var res []int
+	for i, frame := range rep.Frames {
+		if frame.Unreliable {
+			continue
+		}
+		fn := baseFuncName(frame.Func)
+		if fn == "" || noiseFrameRe.MatchString(fn) {
+			continue
+		}
+		res = append(res, i)
+	}
+	return res
+}
+
+// FrameDiff is a single entry of an alignment of two stacks produced by DiffStacks.
+type FrameDiff struct {
+	// Func is the function name (as in FrameSignature).
+	Func string
+	// A and B are indices into Frames of the first and the second report respectively,
+	// -1 if the frame is not present in the corresponding stack.
+	A int
+	B int
+	// Match is set if the frame is present in both stacks (A and B are both valid).
+	Match bool
+}
+
+// DiffStacks aligns main stack traces (Frames) of reports a and b to understand
+// whether the crashes are the same bug. Frames are compared by function names in the same
+// way as FrameSignature does (i.e. noise and unreliable frames are skipped), and aligned
+// using the longest common subsequence, so a stack that is shifted by a few frames
+// (e.g. due to an additional frame on top) still matches in the rest.
+// The result is in the stack order (top frame first), frames present in only one of
+// the stacks have the other index set to -1. Use FirstDivergence to find where the stacks diverge.
+// Inlined frames are present only in symbolized reports, so both reports should be symbolized.
+func DiffStacks(a, b *Report) []FrameDiff {
+	fa, fb := a.signatureFrames(), b.signatureFrames()
+	fn := func(rep *Report, idx int) string {
+		return baseFuncName(rep.Frames[idx].Func)
+	}
+	// lcs[i][j] is the length of the longest common subsequence of fa[i:] and fb[j:].
+	lcs := make([][]int, len(fa)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(fb)+1)
+	}
+	for i := len(fa) - 1; i >= 0; i-- {
+		for j := len(fb) - 1; j >= 0; j-- {
+			switch {
+			case fn(a, fa[i]) == fn(b, fb[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var diff []FrameDiff
+	i, j := 0, 0
+	for i < len(fa) || j < len(fb) {
+		switch {
+		case i < len(fa) && j < len(fb) && fn(a, fa[i]) == fn(b, fb[j]):
+			diff = append(diff, FrameDiff{Func: fn(a, fa[i]), A: fa[i], B: fb[j], Match: true})
+			i++
+			j++
+		case j == len(fb) || i < len(fa) && lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, FrameDiff{Func: fn(a, fa[i]), A: fa[i], B: -1})
+			i++
+		default:
+			diff = append(diff, FrameDiff{Func: fn(b, fb[j]), A: -1, B: fb[j]})
+			j++
+		}
+	}
+	return diff
+}
+
+// FirstDivergence returns index into diff (as returned by DiffStacks) of the first frame
+// that is not present in both stacks, or -1 if the stacks are identical.
+func FirstDivergence(diff []FrameDiff) int {
+	for i, frame := range diff {
+		if !frame.Match {
+			return i
+		}
+	}
+	return -1
+}
+
+var noiseFrameRe = regexp.MustCompile(`^(?:_*(?:dump_stack(?:_lvl)?|show_stack|panic|warn|` +
+	`warn_slowpath_(?:fmt|null)|report_bug|handle_bug|fixup_bug|do_error_trap|do_invalid_op|invalid_op|` +
+	`exc_invalid_op|asm_exc_invalid_op|check_memory_region|print_address_description|print_report|` +
+	`debug_print_object|lockdep_rcu_suspicious)|_*(?:kasan|asan|kmsan|msan|ubsan)_.*)$`)
+
+// PendingAddresses returns addresses of stack frames in Report that are printed as raw PCs
+// (e.g. "[<ffffffff8575b41c>] foo+0x1bc/0x3c0" or " ? 0xffffffff8575b41c") and are not symbolized yet,
+// in order of the first occurrence and without duplicates. This allows to fetch symbols
+// for all of them at once before calling Reporter.Symbolize. The report is not modified.
+// Returns nil if there are no such frames.
+func (rep *Report) PendingAddresses() []uint64 {
+	var addrs []uint64
+	seen := make(map[uint64]bool)
+	for _, line := range bytes.Split(rep.Report, []byte{'\n'}) {
+		if symbolizedFrameRe.Match(line) {
+			continue
+		}
+		for _, match := range rawPCRe.FindAllSubmatch(line, -1) {
+			addr, err := strconv.ParseUint(string(match[1])+string(match[2]), 16, 64)
+			if err != nil || addr == 0 || seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// Symbolizable says if Reporter.Symbolize can do anything for the report, i.e. the crash format
+// has stack traces and the report contains frames with offsets ("func+0xOFF/0xSIZE")
+// that are not symbolized yet or raw PCs (see PendingAddresses). This allows to skip the cost
+// of symbolization for reports that don't need it. Availability of symbols is not checked.
+// Currently supported only for linux.
+func (rep *Report) Symbolizable() bool {
+	if rep.stackText == nil {
+		return false
+	}
+	unsymbolized := func(frames []StackFrame) bool {
+		for _, frame := range frames {
+			if frame.Size != 0 && frame.File == "" {
+				return true
+			}
+		}
+		return false
+	}
+	if unsymbolized(rep.Frames) {
+		return true
+	}
+	for _, stack := range rep.AuxStacks {
+		if unsymbolized(stack.Frames) {
+			return true
+		}
+	}
+	return len(rep.PendingAddresses()) != 0
+}
+
+var (
+	rawPCRe           = regexp.MustCompile(`\[<([0-9a-f]{8,16})>\]|^ +(?:\? )?0x([0-9a-f]{8,16})$`)
+	symbolizedFrameRe = regexp.MustCompile(` [^ ]+\.[a-zA-Z]+:[0-9]+\b`)
+)
+
+// Equal says if rep and other describe the same crash, it's intended for tests and deduplication.
+// It compares only Title, Severity, GuiltyFile and Report text normalized with normalizeReport.
+// All other fields (raw Output, StartPos/EndPos, Frames, Maintainers, etc) are ignored.
+// Two nil reports are equal.
+func (rep *Report) Equal(other *Report) bool {
+	if rep == nil || other == nil {
+		return rep == other
+	}
+	return rep.Title == other.Title &&
+		rep.Severity == other.Severity &&
+		rep.GuiltyFile == other.GuiltyFile &&
+		bytes.Equal(normalizeReport(rep.Report), normalizeReport(other.Report))
+}
+
+// normalizeReport removes volatile data from report text: carriage returns,
+// trailing whitespace, addresses (replaced with ADDR) and large numbers (replaced with NUM).
+func normalizeReport(report []byte) []byte {
+	var res []byte
+	for _, line := range bytes.Split(report, []byte{'\n'}) {
+		line = bytes.TrimRight(line, " \t\r")
+		line = addrRe.ReplaceAll(line, []byte("${1}ADDR"))
+		line = decNumRe.ReplaceAll(line, []byte("${1}NUM"))
+		res = append(res, line...)
+		res = append(res, '\n')
+	}
+	return res
+}
+
+// Anonymize returns Report text with kernel pointers (64-bit values with 0xffff prefix,
+// e.g. "ffff8801c6a1a080" or "0xffffffff82d1b1d9") replaced with pseudo-identifiers "ptr1", "ptr2", etc.
+// The same pointer value is replaced with the same identifier throughout the text
+// (regardless of 0x prefix), so aliasing between pointers is preserved.
+// Identifiers are assigned in order of the first occurrence.
+func (rep *Report) Anonymize() []byte {
+	ptrs := make(map[string]string)
+	return kernelPtrRe.ReplaceAllFunc(rep.Report, func(match []byte) []byte {
+		ptr := string(bytes.TrimPrefix(match, []byte("0x")))
+		id := ptrs[ptr]
+		if id == "" {
+			id = fmt.Sprintf("ptr%v", len(ptrs)+1)
+			ptrs[ptr] = id
+		}
+		return []byte(id)
+	})
+}
+
+// Summary returns a one-line human-readable description of the report for CLI tools, e.g.
+// "[high] KASAN: use-after-free Read in foo (mm/slab.c) [corrupted]".
+// The guilty file is present only if it is known (the report is symbolized)
+// and the corrupted marker only if the report is corrupted.
+// Summary does not modify the report.
+func (rep *Report) Summary() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "[%v] %v", rep.Severity, rep.Title)
+	if rep.GuiltyFile != "" {
+		fmt.Fprintf(buf, " (%v)", rep.GuiltyFile)
+	}
+	if rep.Corrupted {
+		buf.WriteString(" [corrupted]")
+	}
+	return buf.String()
+}
+
+// Validate checks the report for internal inconsistencies (e.g. produced by a buggy crash format)
+// and returns a descriptive error for the first one found. It's intended for sanity checks
+// before reports are persisted.
+func (rep *Report) Validate() error {
+	if rep.Title == "" && !rep.Corrupted {
+		return fmt.Errorf("empty title, but the report is not corrupted")
+	}
+	if rep.Corrupted != (rep.CorruptedReason != "") {
+		return fmt.Errorf("corrupted=%v, but corrupted reason is %q", rep.Corrupted, rep.CorruptedReason)
+	}
+	if rep.StartPos < 0 || rep.StartPos > len(rep.Output) {
+		return fmt.Errorf("start pos %v is out of output range [0, %v]", rep.StartPos, len(rep.Output))
+	}
+	if rep.EndPos < 0 || rep.EndPos > len(rep.Output) {
+		return fmt.Errorf("end pos %v is out of output range [0, %v]", rep.EndPos, len(rep.Output))
+	}
+	if rep.EndPos < rep.StartPos {
+		return fmt.Errorf("end pos %v is before start pos %v", rep.EndPos, rep.StartPos)
+	}
+	return nil
+}
+
+var kernelPtrRe = regexp.MustCompile(`\b(?:0x)?ffff[0-9a-f]{12}\b`)
+
+// Scale of Report.Confidence values.
+const (
+	// ConfidenceFormat: a specific crash format matched with all capture groups non-empty.
+	ConfidenceFormat = 1.0
+	// ConfidencePartialFormat: a specific crash format matched, but some capture groups are empty.
+	ConfidencePartialFormat = 0.75
+	// ConfidenceHeader: no format matched, the title is the bare oops header line.
+	ConfidenceHeader = 0.5
+	// ConfidenceCorrupted: the report is corrupted, so the title is unreliable.
+	ConfidenceCorrupted = 0.25
+)
+
+// AuxStack is an additional stack trace found in the report.
+type AuxStack struct {
+	// Title is the line that introduces the stack (e.g. "NMI backtrace for cpu 1").
+	Title  string
+	Frames []StackFrame
+}
+
+// HeldLock describes a lock held by a task.
+type HeldLock struct {
+	// Task and PID of the task that holds the lock (e.g. "syz-executor0" and 4321).
+	Task string
+	PID  int
+	// Lock is the lock name (e.g. "&mm->mmap_sem").
+	Lock string
+	// Func is the function that acquired the lock.
+	Func string
+}
+
+// StackFrame describes a single frame of a stack trace in the report.
+type StackFrame struct {
+	// Func is the function name as printed in the report (e.g. "do_ipv6_setsockopt.isra.7").
+	Func string
+	// Offset and Size come from "func+0xOFF/0xSIZE" notation (0 if not present).
+	Offset uint64
+	Size   uint64
+	// File and Line denote source location (present only in symbolized reports).
+	File string
+	Line int
+	// SourceLine is text of the File:Line source line (with surrounding whitespace trimmed),
+	// it's set on symbolization if kernel sources are available.
+	SourceLine string
+	// Inline is set for frames that were inlined into the next frame.
+	Inline bool
+	// Unreliable is set for frames printed with "? " marker, i.e. addresses found on the stack
+	// that are not necessarily return addresses of the current call chain.
+	Unreliable bool
+	// Repeat is the number of identical consecutive frames collapsed into this frame
+	// (see Options.CollapseFrames), 0 if frames were not collapsed.
+	Repeat int
+}
+
+// collapseFrames collapses runs of identical consecutive frames into a single frame.
+func collapseFrames(frames []StackFrame) []StackFrame {
+	var res []StackFrame
+	for _, frame := range frames {
+		frame.Repeat = 0
+		if last := len(res) - 1; last >= 0 {
+			prev := res[last]
+			prev.Repeat = 0
+			if prev == frame {
+				res[last].Repeat++
+				continue
+			}
+		}
+		frame.Repeat = 1
+		res = append(res, frame)
+	}
+	return res
+}
+
+// Severity grades how serious a crash is (see Report.Severity).
+type Severity int
+
+const (
+	// SeverityUnknown is used for reports that don't come from a known crash format.
+	SeverityUnknown Severity = iota
+	SeverityInfo
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// OopsInfo describes the crash format that produced a report.
+type OopsInfo struct {
+	OS string
+	// Header is the oops header that matched (e.g. "BUG:" or "WARNING:").
+	Header string
+	// Format is the name of the matched format (see Options.DisableFormats),
+	// empty if the title is the bare oops header line.
+	Format string
+	// Executor is set for syzkaller executor failures (see Report.ExecutorCrash).
+	Executor bool
+	// InfraLimit is set for exhaustion of debugging infrastructure limits (see Report.InfraLimit).
+	InfraLimit bool
+	// Informational is set for informational messages (see Report.Informational).
+	Informational bool
+}
+
+// SeverityFunc returns severity of a crash with the given format and title.
+type SeverityFunc func(format OopsInfo, title string) Severity
+
+// DefaultSeverity is the severity mapping used when Options.SeverityFunc is not set.
+// Custom mappings can fall back to it for crashes they don't care about.
+func DefaultSeverity(format OopsInfo, title string) Severity {
+	if format.Executor || format.InfraLimit || format.Informational {
+		return SeverityInfo
+	}
+	for _, sev := range defaultSeverities {
+		if strings.HasPrefix(title, sev.prefix) {
+			return sev.severity
+		}
+	}
+	return SeverityMedium
+}
+
+var defaultSeverities = []struct {
+	prefix   string
+	severity Severity
+}{
+	{"KASAN:", SeverityHigh},
+	{"KMSAN:", SeverityHigh},
+	{"BUG: unable to handle kernel", SeverityHigh},
+	{"unable to handle kernel", SeverityHigh},
+	{"general protection fault", SeverityHigh},
+	{"kernel BUG", SeverityHigh},
+	{"Fatal trap", SeverityHigh},
+	{"WARNING", SeverityLow},
+	{"UBSAN:", SeverityLow},
+	{"memory leak", SeverityLow},
+	{"suspicious RCU usage", SeverityLow},
+	{"stack trace", SeverityLow},
+	{"out of memory: killed", SeverityLow},
+}
+
+// severity returns severity of a crash according to opts.SeverityFunc or DefaultSeverity.
+func (opts *Options) severity(os string, oops *oops, format oopsFormat, title string) Severity {
+	info := OopsInfo{
+		OS:            os,
+		Header:        string(oops.header),
+		Format:        format.name,
+		Executor:      format.executor,
+		InfraLimit:    format.infra,
+		Informational: format.informational,
+	}
+	if opts.SeverityFunc != nil {
+		return opts.SeverityFunc(info, title)
+	}
+	return DefaultSeverity(info, title)
+}
+
+// Options control optional reporter behavior. Zero value means default behavior.
+// Options that affect oops parsing (e.g. Arch or WarningMessages) are currently used only for linux,
+// unless stated otherwise; the rest (e.g. Redactors or title prefixes) apply to all OSes.
+type Options struct {
+	// MaxFrames limits number of frames that Symbolize resolves (DefaultMaxFrames if 0).
+	// Reports with deeper stacks are marked as corrupted with "stack too deep" reason.
+	MaxFrames int
+	// Redactors are regexps for sensitive data (e.g. user paths) in console output.
+	// Parse replaces all matches in Output/Report with '*' (preserving lengths and new lines,
+	// so that positions stay valid) before any processing, so titles don't contain them either.
+	Redactors []*regexp.Regexp
+	// CollapseFrames makes Symbolize collapse runs of identical consecutive frames
+	// (e.g. due to recursion) in Frames/AuxStacks into a single frame with Repeat count.
+	// The report text is not affected.
+	CollapseFrames bool
+	// DisabledFormats are names of crash formats that are not used (see DisableFormats).
+	DisabledFormats []string
+	// StackTraceDumps enables reporting of "stack trace:" dumps printed by tracing
+	// infrastructure without any oops as low-severity crashes. By default they are ignored.
+	StackTraceDumps bool
+	// Symbolizer creates symbolizers used by Symbolize (an addr2line-based symbolizer.Symbolizer
+	// if nil). A new symbolizer is created for each Symbolize call and closed afterwards.
+	Symbolizer func() Symbolizer
+	// MaxLineLen limits length of the line prefix that is matched against oops headers,
+	// suppressions and ignores (DefaultMaxLineLen if 0, no limit if negative).
+	// Lines are matched many times, so this protects from very long lines
+	// (e.g. serialized logs without newlines). Oops headers beyond the limit are not detected,
+	// and the rest of long console lines is dropped from Report.
+	MaxLineLen int
+	// SeverityFunc overrides the default severity mapping (DefaultSeverity) if set.
+	SeverityFunc SeverityFunc
+	// Arch is the kernel architecture ("amd64", "386", "arm64", "arm" or "riscv64"),
+	// it affects parsing of registers, stack traces, "Code:" lines and fault codes.
+	// If empty, the architecture is detected from the output of each report (x86 if not detected).
+	Arch string
+	// MaintainerFiles is the number of top guilty file candidates (distinct files from the stack,
+	// in the order of guilty file selection) whose maintainers are merged into Report.Maintainers
+	// (1 if 0, i.e. only GuiltyFile). Larger values improve coverage of cross-subsystem bugs.
+	MaintainerFiles int
+	// FullFuncNames makes titles contain function names with compiler-generated suffixes
+	// (e.g. "foo.isra.0" or "foo.cold"). By default titles contain base function names,
+	// which are stable across kernel builds and thus better for deduplication.
+	FullFuncNames bool
+	// WarningMessages makes WARNING titles include the message printed on the line after
+	// the WARNING line (e.g. "WARNING in __vunmap: Trying to vfree() bad address (ADDR)"),
+	// which distinguishes different WARNINGs in the same function. The messages often contain
+	// device and interface names, so a single bug can get several titles; by default
+	// the message is not included.
+	WarningMessages bool
+	// TitleOS and TitleSeverity make reporters prefix titles with the OS name and/or
+	// the crash severity for namespacing, e.g. "linux/high/KASAN: use-after-free Read in foo".
+	// The prefix is added to the final title (after all normalization), Title and Symbolize
+	// keep it as well. Options.SeverityFunc receives the title without the prefix.
+	TitleOS       bool
+	TitleSeverity bool
+	// DropCorrupted makes Parse and ParseFrom return nil for corrupted reports
+	// (and Title return an empty string), as if there were no crash.
+	// ContainsCrash is not affected. By default corrupted reports are returned with Corrupted set.
+	DropCorrupted bool
+	// RawUnclassifiedTitles makes titles of unclassified reports (see Report.Unclassified)
+	// the raw oops header line, without the usual normalization (e.g. of addresses and numbers)
+	// that is meant for titles produced by crash formats.
+	RawUnclassifiedTitles bool
+	// TolerantHeaders makes oops header matching case-insensitive and tolerant to non-ASCII bytes
+	// injected between header characters (e.g. by flaky serial links or embedded consoles).
+	// Crash formats are still matched strictly, so such reports usually get the raw header line
+	// with non-ASCII bytes removed as the title (see Report.Unclassified).
+	// By default headers are matched strictly.
+	TolerantHeaders bool
+	// ParseBudget limits wall-clock time of a single Parse/ParseFrom call (no limit if 0).
+	// This protects from adversarial logs that make matching of crash formats slow.
+	// The budget is checked between regexp evaluations, so a single evaluation can overrun it.
+	// If the budget is exceeded after an oops header is found, a best-effort report
+	// is returned corrupted with "budget exceeded" reason; if it's exceeded before, Parse returns nil.
+	ParseBudget time.Duration
+	// ContextWindow makes Report include console output printed within the given time
+	// before the oops (according to console timestamps) instead of the 5 preceding lines,
+	// StartPos is moved back to the first such line. Lines without timestamps fall back
+	// to the 5 lines context.
+	ContextWindow time.Duration
+	// SuppressInfraLimits makes reporters ignore reports about exhaustion of debugging
+	// infrastructure limits (see Report.InfraLimit) as if they were not crashes.
+	// By default they are reported with SeverityInfo.
+	SuppressInfraLimits bool
+	// SuppressInformational makes reporters ignore informational messages (see Report.Informational)
+	// as if they were not crashes. By default they are reported with SeverityInfo.
+	SuppressInformational bool
+	// TruncatedHeads makes Parse and ParseFrom recover oopses whose beginning (with the oops header)
+	// is lost, e.g. due to console ring buffer overflow: if output starts with a stack trace or
+	// a register dump and contains an oops end marker, a corrupted "truncated oops in FUNC" report
+	// is returned (see Report.Corrupted). ContainsCrash does not detect such oopses, so by default
+	// Parse returns nil for them as for any output without a crash.
+	TruncatedHeads bool
+	// OnlyFamilies restricts crash detection to the given bug families (see CrashFamilies),
+	// e.g. []string{"KASAN"} makes reporters ignore everything else as if it was not a crash.
+	// Oopses without formats of the families are not matched at all, so parsing is faster.
+	// Lines that match an oops header of a family, but no known format are still reported
+	// (see Report.Unclassified). Reporter constructors fail on unknown family names.
+	// Used by linux and freebsd.
+	OnlyFamilies []string
+}
+
+// parseBudget tracks Options.ParseBudget of a single Parse call, nil means no limit.
+type parseBudget struct {
+	deadline time.Time
+}
+
+func newParseBudget(budget time.Duration) *parseBudget {
+	if budget <= 0 {
+		return nil
+	}
+	return &parseBudget{time.Now().Add(budget)}
+}
+
+func (budget *parseBudget) exceeded() bool {
+	return budget != nil && time.Now().After(budget.deadline)
+}
+
+// budgetExceededReason is CorruptedReason of reports with exceeded Options.ParseBudget.
+const budgetExceededReason = "budget exceeded"
+
+// DisableFormats adds formats with the given names to opts.DisabledFormats.
+// Disabled formats are not used to extract report titles, so a crash that would match
+// a disabled format gets a more generic title from another format of the same oops
+// (or just the oops header line). If all formats of an oops are disabled,
+// the oops is not detected at all.
+// Reporter constructors fail if a name does not refer to a known format.
+func (opts *Options) DisableFormats(names ...string) {
+	opts.DisabledFormats = append(opts.DisabledFormats, names...)
+}
+
+// Symbolizer resolves program counters in a binary to source code frames
+// (e.g. with addr2line or llvm-symbolizer). Inlined frames go first.
+type Symbolizer interface {
+	Symbolize(bin string, pc uint64) ([]symbolizer.Frame, error)
+	// Close releases resources. It can be called concurrently with Symbolize
+	// to abort it, subsequent Symbolize calls must fail.
+	Close()
+}
+
+func (opts *Options) newSymbolizer() Symbolizer {
+	if opts.Symbolizer != nil {
+		return opts.Symbolizer()
+	}
+	return symbolizer.NewSymbolizer()
+}
+
+// DefaultMaxFrames is the default value of Options.MaxFrames.
+const DefaultMaxFrames = 1000
+
+// DefaultMaxLineLen is the default value of Options.MaxLineLen.
+// Kernel never prints lines longer than 1024 bytes, but console output can be interleaved
+// with other output on the same line.
+const DefaultMaxLineLen = 4 << 10
+
+func (opts *Options) maxLineLen() int {
+	if opts.MaxLineLen == 0 {
+		return DefaultMaxLineLen
+	}
+	return opts.MaxLineLen
 }
 
 // NewReporter creates reporter for the specified OS:
@@ -49,23 +822,213 @@ type Report struct {
 // ignores: optional list of regexps to ignore (must match first line of crash message)
 func NewReporter(os, kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
 	ignores []*regexp.Regexp) (Reporter, error) {
-	type fn func(string, string, map[string][]symbolizer.Symbol, []*regexp.Regexp) (Reporter, error)
-	ctors := map[string]fn{
-		"akaros":  ctorAkaros,
-		"linux":   ctorLinux,
-		"freebsd": ctorFreebsd,
-		"netbsd":  ctorNetbsd,
-		"fuchsia": ctorFuchsia,
-		"windows": ctorWindows,
-	}
-	ctor := ctors[os]
+	return NewReporterOptions(os, kernelSrc, kernelObj, symbols, ignores, Options{})
+}
+
+// NewReporterOptions is like NewReporter, but additionally accepts reporter options.
+func NewReporterOptions(os, kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
+	return NewReporterFromConfig(ReporterConfig{
+		OS:        os,
+		KernelSrc: kernelSrc,
+		KernelObj: kernelObj,
+		Symbols:   symbols,
+		Ignores:   ignores,
+		Options:   opts,
+	})
+}
+
+// ReporterConfig contains everything needed to create a reporter (see NewReporterFromConfig).
+// Zero values of all fields except OS mean default behavior.
+type ReporterConfig struct {
+	// OS is the target OS (e.g. "linux"), it's the only required field.
+	OS string
+	// KernelSrc is path to kernel sources directory (used to find maintainers).
+	KernelSrc string
+	// KernelObj is path to kernel build directory, KernelSrc if empty (in-tree build).
+	// Reports are symbolized only if the kernel object file is found there.
+	KernelObj string
+	// Symbols are kernel symbols (result of pkg/symbolizer.ReadSymbols on kernel object file),
+	// they are read from the kernel object file if nil.
+	Symbols map[string][]symbolizer.Symbol
+	// Ignores is an optional list of regexps to ignore (must match first line of crash message),
+	// see also ValidateIgnores.
+	Ignores []*regexp.Regexp
+	// Options control optional reporter behavior (see Options for description of each option).
+	Options
+}
+
+// NewReporterFromConfig creates reporter described by cfg.
+// It's the preferred way to create reporters with non-default settings,
+// NewReporter and NewReporterOptions are equivalent shortcuts.
+func NewReporterFromConfig(cfg ReporterConfig) (Reporter, error) {
+	ctor := ctors[cfg.OS]
 	if ctor == nil {
-		return nil, fmt.Errorf("unknown os: %v", os)
+		return nil, fmt.Errorf("unknown os: %v", cfg.OS)
 	}
+	kernelObj := cfg.KernelObj
 	if kernelObj == "" {
-		kernelObj = kernelSrc // assume in-tree build
+		kernelObj = cfg.KernelSrc // assume in-tree build
+	}
+	opts := cfg.Options
+	if opts.MaxFrames == 0 {
+		opts.MaxFrames = DefaultMaxFrames
+	}
+	reporter, err := ctor(cfg.KernelSrc, kernelObj, cfg.Symbols, cfg.Ignores, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TitleOS || opts.TitleSeverity {
+		reporter = &titlePrefixer{Reporter: reporter, os: cfg.OS, opts: opts}
+	}
+	if opts.DropCorrupted {
+		reporter = &corruptedDropper{reporter}
+	}
+	return reporter, nil
+}
+
+type ctorFunc func(string, string, map[string][]symbolizer.Symbol, []*regexp.Regexp, Options) (Reporter, error)
+
+var ctors = map[string]ctorFunc{
+	"akaros":  ctorAkaros,
+	"linux":   ctorLinux,
+	"freebsd": ctorFreebsd,
+	"netbsd":  ctorNetbsd,
+	"fuchsia": ctorFuchsia,
+	"windows": ctorWindows,
+}
+
+// parsingOSes lists OSes with implemented crash parsing, in order of preference.
+var parsingOSes = []string{"linux", "freebsd"}
+
+// corruptedDropper implements Options.DropCorrupted for the wrapped reporter.
+type corruptedDropper struct {
+	Reporter
+}
+
+func (ctx *corruptedDropper) Parse(output []byte) *Report {
+	return ctx.ParseFrom(output, 0)
+}
+
+func (ctx *corruptedDropper) ParseFrom(output []byte, startPos int) *Report {
+	rep := ctx.Reporter.ParseFrom(output, startPos)
+	if rep != nil && rep.Corrupted {
+		return nil
+	}
+	return rep
+}
+
+// Title needs a full parse to check corruption, so it is not cheaper than Parse.
+func (ctx *corruptedDropper) Title(output []byte) string {
+	rep := ctx.Parse(output)
+	if rep == nil {
+		return ""
+	}
+	return rep.Title
+}
+
+// titlePrefixer adds Options.TitleOS/TitleSeverity prefix to titles of the wrapped reporter.
+type titlePrefixer struct {
+	Reporter
+	os   string
+	opts Options
+}
+
+func (ctx *titlePrefixer) prefix(severity Severity) string {
+	prefix := ""
+	if ctx.opts.TitleOS {
+		prefix += ctx.os + "/"
+	}
+	if ctx.opts.TitleSeverity {
+		prefix += severity.String() + "/"
+	}
+	return prefix
+}
+
+func (ctx *titlePrefixer) Parse(output []byte) *Report {
+	return ctx.ParseFrom(output, 0)
+}
+
+func (ctx *titlePrefixer) ParseFrom(output []byte, startPos int) *Report {
+	rep := ctx.Reporter.ParseFrom(output, startPos)
+	if rep != nil {
+		rep.Title = ctx.prefix(rep.Severity) + rep.Title
+	}
+	return rep
+}
+
+// Title needs a full parse to get the severity, so it is not cheaper than Parse with TitleSeverity.
+func (ctx *titlePrefixer) Title(output []byte) string {
+	if ctx.opts.TitleSeverity {
+		rep := ctx.Parse(output)
+		if rep == nil {
+			return ""
+		}
+		return rep.Title
+	}
+	title := ctx.Reporter.Title(output)
+	if title == "" {
+		return ""
+	}
+	return ctx.prefix(0) + title
+}
+
+func (ctx *titlePrefixer) Symbolize(rep *Report) error {
+	return ctx.SymbolizeContext(context.Background(), rep)
+}
+
+// SymbolizeContext strips the prefix for the duration of symbolization,
+// since the wrapped reporter can refine the title.
+func (ctx *titlePrefixer) SymbolizeContext(c context.Context, rep *Report) error {
+	prefix := ctx.prefix(rep.Severity)
+	rep.Title = strings.TrimPrefix(rep.Title, prefix)
+	err := ctx.Reporter.SymbolizeContext(c, rep)
+	rep.Title = prefix + rep.Title
+	return err
+}
+
+// ValidateIgnores compiles user-supplied ignore regexps for the specified OS.
+// The regexps can use the same templates as the built-in oops formats
+// (e.g. {{ADDR}}, {{FUNC}}). Returns an error that mentions the offending regexp
+// if any of them fails to compile.
+func ValidateIgnores(os string, ignores []string) ([]*regexp.Regexp, error) {
+	if ctors[os] == nil {
+		return nil, fmt.Errorf("unknown os: %v", os)
+	}
+	var res []*regexp.Regexp
+	for i, ignore := range ignores {
+		re, err := compileTemplate(ignore)
+		if err != nil {
+			return nil, fmt.Errorf("bad ignore #%v %q: %v", i, ignore, err)
+		}
+		res = append(res, re)
 	}
-	return ctor(kernelSrc, kernelObj, symbols, ignores)
+	return res, nil
+}
+
+// MatchCount dry-runs ignores against a corpus of console outputs.
+// For each ignore it returns number of logs where the ignore drops at least one crash.
+func MatchCount(os string, ignores []*regexp.Regexp, logs [][]byte) ([]int, error) {
+	supported := false
+	for _, os1 := range parsingOSes {
+		supported = supported || os == os1
+	}
+	if !supported {
+		return nil, fmt.Errorf("crash parsing is not supported for os: %v", os)
+	}
+	counts := make([]int, len(ignores))
+	for i, ignore := range ignores {
+		reporter, err := NewReporter(os, "", "", nil, []*regexp.Regexp{ignore})
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if _, _, ignored := reporter.ContainsCrashDetailed(log); ignored != 0 {
+				counts[i]++
+			}
+		}
+	}
+	return counts, nil
 }
 
 type oops struct {
@@ -75,22 +1038,201 @@ type oops struct {
 }
 
 type oopsFormat struct {
+	// name uniquely identifies the format within the OS (used by Options.DisableFormats).
+	name         string
 	title        *regexp.Regexp
 	report       *regexp.Regexp
 	fmt          string
 	noStackTrace bool
 	corrupted    bool
+	executor     bool
+	// message says that the line following the title line can contain a descriptive
+	// message (e.g. WARN format string) that is appended to the title.
+	message bool
+	// hungTask says that the report can contain several blocked tasks,
+	// and the title is extracted from the most representative one.
+	hungTask bool
+	// execFault says that the crash is an attempt to execute non-executable memory
+	// (see Report.ExecFault).
+	execFault bool
+	// userAccessFault says that the crash is an access to user memory outside of uaccess routines
+	// (see Report.UserAccessFault).
+	userAccessFault bool
+	// infra says that the crash is exhaustion of kernel debugging infrastructure limits
+	// rather than a kernel bug (see Report.InfraLimit).
+	infra bool
+	// informational says that the report is an informational message rather than a crash
+	// (see Report.Informational). Such oopses don't mask crashes that follow them in the output.
+	informational bool
+}
+
+// executorOops matches failures of syzkaller's own processes that end up in console output.
+// syz-fuzzer panics when executor fails, so we get e.g.
+// "panic: executor 0: failed: pthread_create failed (errno 11)".
+var executorOops = &oops{
+	[]byte("panic: executor"),
+	[]oopsFormat{
+		{
+			name:         "executor",
+			title:        compile("panic: executor [0-9]+: (.*)"),
+			fmt:          "executor crash: %[1]v",
+			noStackTrace: true,
+			executor:     true,
+		},
+	},
+	[]*regexp.Regexp{},
+}
+
+// disableFormats returns oopses without the formats with the given names.
+// Oopses that are left without formats are removed. Unchanged oopses are not copied.
+func disableFormats(oopses []*oops, names []string) ([]*oops, error) {
+	if len(names) == 0 {
+		return oopses, nil
+	}
+	disabled := make(map[string]bool)
+	for _, name := range names {
+		disabled[name] = true
+	}
+	var res []*oops
+	for _, oops1 := range oopses {
+		var formats []oopsFormat
+		for _, f := range oops1.formats {
+			if disabled[f.name] {
+				delete(disabled, f.name)
+				continue
+			}
+			formats = append(formats, f)
+		}
+		switch {
+		case len(formats) == len(oops1.formats):
+			res = append(res, oops1)
+		case len(formats) != 0:
+			res = append(res, &oops{oops1.header, formats, oops1.suppressions})
+		}
+	}
+	for _, name := range names {
+		if disabled[name] {
+			return nil, fmt.Errorf("unknown crash format %q", name)
+		}
+	}
+	return res, nil
+}
+
+// crashFamilies maps bug family names (see Options.OnlyFamilies) to title prefixes of the family.
+var crashFamilies = map[string][]string{
+	"KASAN":   {"KASAN:"},
+	"KCSAN":   {"KCSAN:"},
+	"UBSAN":   {"UBSAN:"},
+	"WARNING": {"WARNING"},
+	"lockdep": {"possible deadlock", "inconsistent lock state", "BUG: bad unlock balance",
+		"BUG: held lock freed", "BUG: still has locks held", "WARNING: lock held"},
+	"memleak": {"memory leak"},
+	"hang": {"INFO: rcu detected stall", "INFO: task hung", "BUG: soft lockup", "BUG: hard lockup",
+		"BUG: workqueue lockup"},
+}
+
+// CrashFamilies returns sorted names of known bug families (see Options.OnlyFamilies).
+func CrashFamilies() []string {
+	var families []string
+	for family := range crashFamilies {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	return families
+}
+
+// onlyFamilies returns oopses restricted to formats of the given bug families (see Options.OnlyFamilies).
+// Lines matching titles of the other formats of the remaining oopses are suppressed,
+// so that they are not reported with the bare header line as the title.
+func onlyFamilies(oopses []*oops, families []string) ([]*oops, error) {
+	if len(families) == 0 {
+		return oopses, nil
+	}
+	var prefixes []string
+	for _, family := range families {
+		familyPrefixes, ok := crashFamilies[family]
+		if !ok {
+			return nil, fmt.Errorf("unknown crash family %q", family)
+		}
+		prefixes = append(prefixes, familyPrefixes...)
+	}
+	inFamily := func(f *oopsFormat) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(f.fmt, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	var res []*oops
+	for _, oops1 := range oopses {
+		var formats []oopsFormat
+		suppressions := oops1.suppressions
+		for i := range oops1.formats {
+			if f := &oops1.formats[i]; inFamily(f) {
+				formats = append(formats, *f)
+			} else {
+				suppressions = append(suppressions[:len(suppressions):len(suppressions)], f.title)
+			}
+		}
+		switch {
+		case len(formats) == len(oops1.formats):
+			res = append(res, oops1)
+		case len(formats) != 0:
+			res = append(res, &oops{oops1.header, formats, suppressions})
+		}
+	}
+	return res, nil
+}
+
+// suppressFormats returns oopses where lines that match formats selected by suppress are suppressed
+// (e.g. formats of infrastructure limits exhaustion). Unchanged oopses are not copied.
+func suppressFormats(oopses []*oops, suppress func(f *oopsFormat) bool) []*oops {
+	var res []*oops
+	for _, oops1 := range oopses {
+		suppressions := oops1.suppressions
+		for i := range oops1.formats {
+			if f := &oops1.formats[i]; suppress(f) {
+				suppressions = append(suppressions[:len(suppressions):len(suppressions)], f.title)
+			}
+		}
+		if len(suppressions) != len(oops1.suppressions) {
+			oops1 = &oops{oops1.header, oops1.formats, suppressions}
+		}
+		res = append(res, oops1)
+	}
+	return res
+}
+
+// isInformational says if all formats of the oops are informational (see oopsFormat.informational).
+func isInformational(oops *oops) bool {
+	for _, f := range oops.formats {
+		if !f.informational {
+			return false
+		}
+	}
+	return true
 }
 
 func compile(re string) *regexp.Regexp {
+	compiled, err := compileTemplate(re)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+func compileTemplate(re string) (*regexp.Regexp, error) {
 	re = strings.Replace(re, "{{ADDR}}", "0x[0-9a-f]+", -1)
 	re = strings.Replace(re, "{{PC}}", "\\[\\<[0-9a-f]+\\>\\]", -1)
-	re = strings.Replace(re, "{{FUNC}}", "([a-zA-Z0-9_]+)(?:\\.|\\+)", -1)
+	// Function names are captured with compiler-generated suffixes (e.g. "foo.isra.0", "foo.cold"),
+	// the group is named, so that titles can use the base name (see titleArgs).
+	re = strings.Replace(re, "{{FUNC}}", "(?P<FUNC>[a-zA-Z0-9_]+(?:\\.[a-zA-Z0-9_]+)*)(?:\\.|\\+)", -1)
 	re = strings.Replace(re, "{{SRC}}", "([a-zA-Z0-9-_/.]+\\.[a-z]+:[0-9]+)", -1)
-	return regexp.MustCompile(re)
+	return regexp.Compile(re)
 }
 
-func containsCrash(output []byte, oopses []*oops, ignores []*regexp.Regexp) bool {
+func containsCrash(output []byte, oopses []*oops, ignores []*regexp.Regexp, maxLineLen int, tolerant bool) bool {
 	for pos := 0; pos < len(output); {
 		next := bytes.IndexByte(output[pos:], '\n')
 		if next != -1 {
@@ -99,7 +1241,7 @@ func containsCrash(output []byte, oopses []*oops, ignores []*regexp.Regexp) bool
 			next = len(output)
 		}
 		for _, oops := range oopses {
-			match := matchOops(output[pos:next], oops, ignores)
+			match := matchOops(capLine(output[pos:next], maxLineLen), oops, ignores, tolerant)
 			if match == -1 {
 				continue
 			}
@@ -110,27 +1252,146 @@ func containsCrash(output []byte, oopses []*oops, ignores []*regexp.Regexp) bool
 	return false
 }
 
-func matchOops(line []byte, oops *oops, ignores []*regexp.Regexp) int {
-	match := bytes.Index(line, oops.header)
+func containsCrashDetailed(output []byte, oopses []*oops, ignores []*regexp.Regexp, maxLineLen int,
+	tolerant bool) (found bool, suppressed int, ignored int) {
+	for pos := 0; pos < len(output); {
+		next := bytes.IndexByte(output[pos:], '\n')
+		if next != -1 {
+			next += pos
+		} else {
+			next = len(output)
+		}
+		lineFound, lineSuppressed, lineIgnored := false, false, false
+		for _, oops := range oopses {
+			line := capLine(output[pos:next], maxLineLen)
+			match, supp, ign := matchOopsDetailed(line, oops, ignores, tolerant)
+			if match != -1 {
+				lineFound = true
+				break
+			}
+			lineSuppressed = lineSuppressed || supp
+			lineIgnored = lineIgnored || ign
+		}
+		if lineFound {
+			found = true
+		} else if lineSuppressed {
+			suppressed++
+		} else if lineIgnored {
+			ignored++
+		}
+		pos = next + 1
+	}
+	return
+}
+
+// capLine returns the prefix of line that is used for matching (see Options.MaxLineLen).
+func capLine(line []byte, maxLineLen int) []byte {
+	if maxLineLen > 0 && len(line) > maxLineLen {
+		return line[:maxLineLen]
+	}
+	return line
+}
+
+func matchOops(line []byte, oops *oops, ignores []*regexp.Regexp, tolerant bool) int {
+	match, _, _ := matchOopsDetailed(line, oops, ignores, tolerant)
+	return match
+}
+
+// matchOopsDetailed returns position of oops header in line (or -1),
+// and whether a matching header was dropped by suppressions or ignores.
+func matchOopsDetailed(line []byte, oops *oops, ignores []*regexp.Regexp, tolerant bool) (int, bool, bool) {
+	match := findHeader(line, oops.header, tolerant)
 	if match == -1 {
-		return -1
+		return -1, false, false
 	}
 	for _, supp := range oops.suppressions {
 		if supp.Match(line) {
-			return -1
+			return -1, true, false
 		}
 	}
 	for _, ignore := range ignores {
 		if ignore.Match(line) {
+			return -1, false, true
+		}
+	}
+	return match, false, false
+}
+
+// findHeader returns position of header in line, or -1. If tolerant is set (see Options.TolerantHeaders),
+// the header is matched case-insensitively and non-ASCII bytes between header characters are skipped.
+func findHeader(line, header []byte, tolerant bool) int {
+	if !tolerant {
+		return bytes.Index(line, header)
+	}
+	lower := func(c byte) byte {
+		if c >= 'A' && c <= 'Z' {
+			return c - 'A' + 'a'
+		}
+		return c
+	}
+next:
+	for start := 0; start+len(header) <= len(line); start++ {
+		pos := start
+		for i, c := range header {
+			for i != 0 && pos < len(line) && line[pos] >= 0x80 {
+				pos++
+			}
+			if pos == len(line) || lower(line[pos]) != lower(c) {
+				continue next
+			}
+			pos++
+		}
+		return start
+	}
+	return -1
+}
+
+// stripNonASCII removes non-ASCII bytes (as skipped by tolerant findHeader) from s.
+func stripNonASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 0x80 {
 			return -1
 		}
+		return r
+	}, s)
+}
+
+// titleArgs converts values of capture groups of re to title format arguments.
+// Function names captured with {{FUNC}} are reduced to the base name unless fullFuncs is set.
+func titleArgs(re *regexp.Regexp, groups []string, fullFuncs bool) []interface{} {
+	names := re.SubexpNames()
+	var args []interface{}
+	for i, group := range groups {
+		if !fullFuncs && names[i+1] == "FUNC" {
+			group = baseFuncName(group)
+		}
+		args = append(args, group)
 	}
-	return match
+	return args
 }
 
-func extractDescription(output []byte, oops *oops) (desc string, report []byte, format oopsFormat) {
+// baseFuncName strips compiler-generated suffixes (e.g. ".isra.0", ".constprop.3", ".cold")
+// from the function name.
+func baseFuncName(fn string) string {
+	if pos := strings.IndexByte(fn, '.'); pos > 0 {
+		return fn[:pos]
+	}
+	return fn
+}
+
+// maxDescLen limits length of titles derived from raw lines, since corrupted/intermixed lines can be very long.
+const maxDescLen = 180
+
+// extractDescription returns title of the oops and the report text starting from the oops.
+// desc is empty if no format matches and the oops header is not present in output.
+// If budget is exceeded, the remaining formats are not matched.
+func extractDescription(output []byte, oops *oops, fullFuncs, tolerant bool, budget *parseBudget) (desc string,
+	report []byte, format oopsFormat, confidence float64) {
 	startPos := -1
 	for _, f := range oops.formats {
+		if budget.exceeded() {
+			break
+		}
 		match := f.title.FindSubmatchIndex(output)
 		if match == nil {
 			continue
@@ -139,18 +1400,26 @@ func extractDescription(output []byte, oops *oops) (desc string, report []byte,
 			continue
 		}
 		startPos = match[0]
-		var args []interface{}
+		confidence = ConfidenceFormat
+		var groups []string
 		for i := 2; i < len(match); i += 2 {
-			args = append(args, string(output[match[i]:match[i+1]]))
+			if match[i] == match[i+1] {
+				confidence = ConfidencePartialFormat
+			}
+			if match[i] == -1 {
+				groups = append(groups, "")
+				continue
+			}
+			groups = append(groups, string(output[match[i]:match[i+1]]))
 		}
-		desc = fmt.Sprintf(f.fmt, args...)
+		desc = fmt.Sprintf(f.fmt, titleArgs(f.title, groups, fullFuncs)...)
 		report = output[startPos:]
 		format = f
 	}
 	if len(desc) == 0 {
-		pos := bytes.Index(output, oops.header)
+		pos := findHeader(output, oops.header, tolerant)
 		if pos == -1 {
-			panic("non matching oops")
+			return
 		}
 		end := bytes.IndexByte(output[pos:], '\n')
 		if end == -1 {
@@ -159,19 +1428,50 @@ func extractDescription(output []byte, oops *oops) (desc string, report []byte,
 			end += pos
 		}
 		desc = string(output[pos:end])
+		if tolerant {
+			desc = stripNonASCII(desc)
+		}
 		report = output[pos:]
+		confidence = ConfidenceHeader
 	}
 	if len(desc) > 0 && desc[len(desc)-1] == '\r' {
 		desc = desc[:len(desc)-1]
 	}
-	// Corrupted/intermixed lines can be very long.
-	const maxDescLen = 180
 	if len(desc) > maxDescLen {
 		desc = desc[:maxDescLen]
 	}
 	return
 }
 
+// redact returns copy of output with all matches of redactors replaced with '*'.
+func redact(output []byte, redactors []*regexp.Regexp) []byte {
+	if len(redactors) == 0 {
+		return output
+	}
+	redacted := append([]byte{}, output...)
+	for _, re := range redactors {
+		for _, match := range re.FindAllIndex(redacted, -1) {
+			for i := match[0]; i < match[1]; i++ {
+				if redacted[i] != '\n' {
+					redacted[i] = '*'
+				}
+			}
+		}
+	}
+	return redacted
+}
+
+// clampPos clamps pos to [0, size] range.
+func clampPos(pos, size int) int {
+	if pos < 0 {
+		return 0
+	}
+	if pos > size {
+		return size
+	}
+	return pos
+}
+
 // replace replaces [start:end] in where with what, inplace.
 func replace(where []byte, start, end int, what []byte) []byte {
 	if len(what) >= end-start {
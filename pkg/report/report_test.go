@@ -4,7 +4,10 @@
 package report
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -46,6 +49,31 @@ type ParseTest struct {
 	Corrupted bool
 }
 
+// testTitle checks that Title returns the same title as Parse for all tests with various options.
+func testTitle(t *testing.T, os string, tests []ParseTest) {
+	for _, opts := range []Options{
+		{},
+		{TruncatedHeads: true},
+		{RawUnclassifiedTitles: true},
+		{SuppressInformational: true},
+		{TruncatedHeads: true, RawUnclassifiedTitles: true, SuppressInformational: true},
+	} {
+		reporter, err := NewReporterOptions(os, "", "", nil, nil, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, test := range tests {
+			title := ""
+			if rep := reporter.Parse([]byte(test.Log)); rep != nil {
+				title = rep.Title
+			}
+			if got := reporter.Title([]byte(test.Log)); got != title {
+				t.Fatalf("%+v: Title returned %q, Parse returned %q for:\n%v", opts, got, title, test.Log)
+			}
+		}
+	}
+}
+
 func testParse(t *testing.T, os string, tests []ParseTest) {
 	reporter, err := NewReporter(os, "", "", nil, nil)
 	if err != nil {
@@ -72,6 +100,10 @@ func testParse(t *testing.T, os string, tests []ParseTest) {
 		if rep != nil && rep.Title == "" {
 			t.Fatalf("found crash, but title is empty '%v' in:\n%v", test.Desc, test.Log)
 		}
+		if rep != nil && rep.Corrupted != (rep.CorruptedReason != "") {
+			t.Fatalf("corrupted=%v, but corrupted reason is '%v' in:\n%v",
+				rep.Corrupted, rep.CorruptedReason, test.Log)
+		}
 		title, corrupted := "", false
 		if rep != nil {
 			title = rep.Title
@@ -86,6 +118,18 @@ func testParse(t *testing.T, os string, tests []ParseTest) {
 		if title != test.Desc {
 			t.Fatalf("extracted bad crash message:\n%+q\nwant:\n%+q", title, test.Desc)
 		}
+		if title1 := reporter.Title([]byte(test.Log)); title1 != title {
+			t.Fatalf("Title returned %+q, but Parse returned %+q in:\n%v", title1, title, test.Log)
+		}
+		pc := NewParseContext(reporter, []byte(test.Log))
+		if pc.ContainsCrash() != containsCrash {
+			t.Fatalf("ParseContext.ContainsCrash returned %v in:\n%v", !containsCrash, test.Log)
+		}
+		if rep1 := pc.Parse(); (rep1 == nil) != (rep == nil) || rep != nil && (rep1.Title != rep.Title ||
+			rep1.StartPos != rep.StartPos || rep1.EndPos != rep.EndPos || rep1.Corrupted != rep.Corrupted ||
+			!bytes.Equal(rep1.Report, rep.Report)) {
+			t.Fatalf("ParseContext.Parse returned %+v, but Parse returned %+v in:\n%v", rep1, rep, test.Log)
+		}
 		if corrupted && !test.Corrupted {
 			t.Fatalf("incorrectly marked report as corrupted: '%v'\n%v", title, test.Log)
 		}
@@ -94,3 +138,662 @@ func testParse(t *testing.T, os string, tests []ParseTest) {
 		}
 	}
 }
+
+func TestValidateIgnores(t *testing.T) {
+	if _, err := ValidateIgnores("foobar", nil); err == nil {
+		t.Fatalf("no error for unknown os")
+	}
+	_, err := ValidateIgnores("linux", []string{"BUG: foo", "WARNING: (bar"})
+	if err == nil || !strings.Contains(err.Error(), "WARNING: (bar") {
+		t.Fatalf("bad error for malformed regexp: %v", err)
+	}
+	ignores, err := ValidateIgnores("linux", []string{"BUG: bug1", "WARNING in {{FUNC}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ignores) != 2 || !ignores[1].MatchString("WARNING in foo+0x10") {
+		t.Fatalf("bad compiled ignores: %v", ignores)
+	}
+	logs := [][]byte{
+		[]byte("[    0.000000] BUG: bug1\n"),
+		[]byte("[    0.000000] BUG: bug1\n[    0.000000] BUG: bug2\n"),
+		[]byte("[    0.000000] nothing\n"),
+	}
+	counts, err := MatchCount("linux", ignores, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0] != 2 || counts[1] != 0 {
+		t.Fatalf("bad match counts: %v", counts)
+	}
+	if _, err := MatchCount("akaros", ignores, logs); err == nil {
+		t.Fatalf("no error for os without parsing support")
+	}
+}
+
+func TestFormatNames(t *testing.T) {
+	for os, oopses := range map[string][]*oops{
+		"linux":           linuxOopses,
+		"linux-truncated": {linuxTruncatedOops},
+		"freebsd":         freebsdOopses,
+	} {
+		names := make(map[string]bool)
+		for _, oops := range oopses {
+			for _, f := range oops.formats {
+				if f.name == "" || names[f.name] {
+					t.Errorf("%v: empty or duplicate format name %q (%v)", os, f.name, f.title)
+				}
+				names[f.name] = true
+			}
+		}
+	}
+}
+
+func TestReportEqual(t *testing.T) {
+	rep := &Report{
+		Title:      "KASAN: use-after-free Read in foo",
+		Report:     []byte("BUG: KASAN: use-after-free in foo+0x10/0x20\nRead at addr ffff8801c6a1a080 by task syz/12345\n"),
+		GuiltyFile: "net/foo.c",
+		Severity:   SeverityHigh,
+		StartPos:   10,
+	}
+	same := &Report{
+		Title:      "KASAN: use-after-free Read in foo",
+		Report:     []byte("BUG: KASAN: use-after-free in foo+0x10/0x20  \r\nRead at addr ffff8801d5b6c8a0 by task syz/54321\r\n"),
+		GuiltyFile: "net/foo.c",
+		Severity:   SeverityHigh,
+		Output:     []byte("whatever"),
+		StartPos:   20,
+		EndPos:     30,
+	}
+	if !rep.Equal(same) || !same.Equal(rep) {
+		t.Fatalf("reports are not equal")
+	}
+	for i, mutate := range []func(rep *Report){
+		func(rep *Report) { rep.Title = "KASAN: use-after-free Write in foo" },
+		func(rep *Report) { rep.GuiltyFile = "net/bar.c" },
+		func(rep *Report) { rep.Severity = SeverityMedium },
+		func(rep *Report) { rep.Report = []byte("BUG: KASAN: use-after-free in bar+0x10/0x20\n") },
+	} {
+		other := *same
+		mutate(&other)
+		if rep.Equal(&other) {
+			t.Fatalf("#%v: reports are equal", i)
+		}
+	}
+	var nilRep *Report
+	if !nilRep.Equal(nil) || nilRep.Equal(rep) || rep.Equal(nil) {
+		t.Fatalf("bad nil report comparison")
+	}
+}
+
+func TestReportAnonymize(t *testing.T) {
+	rep := &Report{
+		Report: []byte(`BUG: KASAN: use-after-free in foo+0x10/0x20
+Read of size 8 at addr ffff8801c6a1a080 by task syz-executor0/4321
+The buggy address belongs to the object at ffff8801c6a1a000
+RIP: 0010:[<ffffffff82d1b1d9>] foo+0x10/0x20
+RDX: 0xffff8801c6a1a080 RSI: 0000000000000010 RDI: ffffffff82d1b1d9
+not a pointer: ffff8801c6a1a0801 xffff8801c6a1a080
+`),
+	}
+	want := `BUG: KASAN: use-after-free in foo+0x10/0x20
+Read of size 8 at addr ptr1 by task syz-executor0/4321
+The buggy address belongs to the object at ptr2
+RIP: 0010:[<ptr3>] foo+0x10/0x20
+RDX: ptr1 RSI: 0000000000000010 RDI: ptr3
+not a pointer: ffff8801c6a1a0801 xffff8801c6a1a080
+`
+	if got := string(rep.Anonymize()); got != want {
+		t.Fatalf("bad anonymized report:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReportSummary(t *testing.T) {
+	tests := []struct {
+		rep  *Report
+		want string
+	}{
+		{
+			&Report{
+				Title:      "KASAN: use-after-free Read in foo",
+				Severity:   SeverityHigh,
+				GuiltyFile: "mm/slab.c",
+				Corrupted:  true,
+			},
+			"[high] KASAN: use-after-free Read in foo (mm/slab.c) [corrupted]",
+		},
+		{
+			&Report{
+				Title:    "WARNING in bar",
+				Severity: SeverityLow,
+			},
+			"[low] WARNING in bar",
+		},
+		{
+			&Report{
+				Title:      "lost connection to test machine",
+				GuiltyFile: "net/socket.c",
+			},
+			"[unknown] lost connection to test machine (net/socket.c)",
+		},
+	}
+	for i, test := range tests {
+		if got := test.rep.Summary(); got != test.want {
+			t.Errorf("#%v: got %q, want %q", i, got, test.want)
+		}
+	}
+}
+
+func TestReportValidate(t *testing.T) {
+	output := []byte("some output\nBUG: foo\n")
+	valid := Report{
+		Title:    "BUG: foo",
+		Output:   output,
+		StartPos: 12,
+		EndPos:   20,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid report: %v", err)
+	}
+	tests := []struct {
+		name   string
+		modify func(rep *Report)
+	}{
+		{"empty title", func(rep *Report) { rep.Title = "" }},
+		{"no corrupted reason", func(rep *Report) { rep.Corrupted = true }},
+		{"corrupted reason", func(rep *Report) { rep.CorruptedReason = "no stack trace" }},
+		{"negative start", func(rep *Report) { rep.StartPos = -1 }},
+		{"start out of range", func(rep *Report) { rep.StartPos, rep.EndPos = 22, 22 }},
+		{"negative end", func(rep *Report) { rep.StartPos, rep.EndPos = 0, -1 }},
+		{"end out of range", func(rep *Report) { rep.EndPos = 22 }},
+		{"end before start", func(rep *Report) { rep.EndPos = 11 }},
+	}
+	for _, test := range tests {
+		rep := valid
+		test.modify(&rep)
+		if err := rep.Validate(); err == nil {
+			t.Errorf("%v: no error", test.name)
+		}
+	}
+	// Corrupted reports may have empty title.
+	corrupted := valid
+	corrupted.Title, corrupted.Corrupted, corrupted.CorruptedReason = "", true, "corrupted format"
+	if err := corrupted.Validate(); err != nil {
+		t.Fatalf("corrupted report: %v", err)
+	}
+}
+
+func TestReportFrameSignature(t *testing.T) {
+	rep := &Report{
+		Frames: []StackFrame{
+			{Func: "__dump_stack", File: "lib/dump_stack.c", Inline: true},
+			{Func: "dump_stack_lvl", Offset: 0x1b1, Size: 0x28e},
+			{Func: "print_address_description.constprop.0"},
+			{Func: "kasan_report"},
+			{Func: "__asan_load8"},
+			{Func: "ip6_dst_store", Offset: 0x4e4, Size: 0x520},
+			{Func: "ip6_sk_dst_store_flow.isra.7"},
+			{Func: "udpv6_sendmsg"},
+			{Func: "inet_sendmsg"},
+		},
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, nil},
+		{1, []string{"ip6_dst_store"}},
+		{3, []string{"ip6_dst_store", "ip6_sk_dst_store_flow", "udpv6_sendmsg"}},
+		{10, []string{"ip6_dst_store", "ip6_sk_dst_store_flow", "udpv6_sendmsg", "inet_sendmsg"}},
+	}
+	for _, test := range tests {
+		if got := rep.FrameSignature(test.n); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("n=%v: got %q, want %q", test.n, got, test.want)
+		}
+	}
+	if got := (&Report{}).FrameSignature(5); len(got) != 0 {
+		t.Errorf("got %q for empty stack", got)
+	}
+}
+
+func TestDiffStacks(t *testing.T) {
+	stack := func(funcs ...string) *Report {
+		rep := &Report{}
+		for _, fn := range funcs {
+			rep.Frames = append(rep.Frames, StackFrame{Func: fn})
+		}
+		return rep
+	}
+	base := stack("dump_stack", "kasan_report", "ip6_dst_store", "ip6_sk_dst_store_flow.isra.7",
+		"udpv6_sendmsg", "inet_sendmsg")
+	tests := []struct {
+		name  string
+		other *Report
+		want  []FrameDiff
+		first int
+	}{
+		{
+			name:  "identical",
+			other: stack("ip6_dst_store", "ip6_sk_dst_store_flow", "udpv6_sendmsg", "inet_sendmsg"),
+			want: []FrameDiff{
+				{Func: "ip6_dst_store", A: 2, B: 0, Match: true},
+				{Func: "ip6_sk_dst_store_flow", A: 3, B: 1, Match: true},
+				{Func: "udpv6_sendmsg", A: 4, B: 2, Match: true},
+				{Func: "inet_sendmsg", A: 5, B: 3, Match: true},
+			},
+			first: -1,
+		},
+		{
+			name: "shifted",
+			other: stack("__ip6_dst_store", "ip6_dst_store", "ip6_sk_dst_store_flow",
+				"udpv6_sendmsg", "inet_sendmsg"),
+			want: []FrameDiff{
+				{Func: "__ip6_dst_store", A: -1, B: 0},
+				{Func: "ip6_dst_store", A: 2, B: 1, Match: true},
+				{Func: "ip6_sk_dst_store_flow", A: 3, B: 2, Match: true},
+				{Func: "udpv6_sendmsg", A: 4, B: 3, Match: true},
+				{Func: "inet_sendmsg", A: 5, B: 4, Match: true},
+			},
+			first: 0,
+		},
+		{
+			name:  "divergent",
+			other: stack("ip6_dst_store", "ip6_sk_dst_store_flow", "rawv6_sendmsg", "sock_sendmsg"),
+			want: []FrameDiff{
+				{Func: "ip6_dst_store", A: 2, B: 0, Match: true},
+				{Func: "ip6_sk_dst_store_flow", A: 3, B: 1, Match: true},
+				{Func: "udpv6_sendmsg", A: 4, B: -1},
+				{Func: "inet_sendmsg", A: 5, B: -1},
+				{Func: "rawv6_sendmsg", A: -1, B: 2},
+				{Func: "sock_sendmsg", A: -1, B: 3},
+			},
+			first: 2,
+		},
+	}
+	for _, test := range tests {
+		diff := DiffStacks(base, test.other)
+		if !reflect.DeepEqual(diff, test.want) {
+			t.Errorf("%v: got diff:\n%+v\nwant:\n%+v", test.name, diff, test.want)
+		}
+		if first := FirstDivergence(diff); first != test.first {
+			t.Errorf("%v: got first divergence %v, want %v", test.name, first, test.first)
+		}
+	}
+	if diff := DiffStacks(&Report{}, &Report{}); len(diff) != 0 || FirstDivergence(diff) != -1 {
+		t.Errorf("got %+v for empty stacks", diff)
+	}
+}
+
+func TestReportPendingAddresses(t *testing.T) {
+	report := []byte(`RIP: 0010:[<ffffffff8575b41c>]  [<ffffffff8575b41c>] snd_hrtimer_callback+0x1bc/0x3c0
+Call Trace:
+ <IRQ>
+ [<ffffffff81b8b0a5>] __run_hrtimer kernel/time/hrtimer.c:1213 [inline]
+ [<ffffffff81b8b0a5>] __hrtimer_run_queues+0x325/0xe70 kernel/time/hrtimer.c:1277
+ [<ffffffff81b8d2d2>] hrtimer_interrupt+0x1b2/0x5c0
+ ? 0xffffffffa0001240
+ [<ffffffff8575b41c>] snd_hrtimer_callback+0x1bc/0x3c0
+ do_syscall_64+0x1a/0x20
+`)
+	rep := &Report{Report: report}
+	want := []uint64{0xffffffff8575b41c, 0xffffffff81b8d2d2, 0xffffffffa0001240}
+	if got := rep.PendingAddresses(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got addresses %x, want %x", got, want)
+	}
+	if string(rep.Report) != string(report) {
+		t.Fatalf("report is modified:\n%s", rep.Report)
+	}
+	rep = &Report{Report: []byte(" __sys_sendmsg+0xe5/0x210 net/socket.c:2049\n")}
+	if got := rep.PendingAddresses(); got != nil {
+		t.Fatalf("got addresses %x for a symbolized report", got)
+	}
+}
+
+func TestTitlePrefix(t *testing.T) {
+	output := []byte(`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`)
+	const title = "KASAN: use-after-free Read in ip6_dst_store"
+	tests := []struct {
+		opts  Options
+		title string
+	}{
+		{Options{}, title},
+		{Options{TitleOS: true}, "linux/" + title},
+		{Options{TitleSeverity: true}, "high/" + title},
+		{Options{TitleOS: true, TitleSeverity: true}, "linux/high/" + title},
+	}
+	for _, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rep := reporter.Parse(output)
+		if rep == nil || rep.Title != test.title {
+			t.Errorf("%+v: got report %+v, want title %q", test.opts, rep, test.title)
+			continue
+		}
+		if got := reporter.Title(output); got != test.title {
+			t.Errorf("%+v: Title returned %q, want %q", test.opts, got, test.title)
+		}
+		if err := reporter.Symbolize(rep); err != nil {
+			t.Fatal(err)
+		}
+		if rep.Title != test.title {
+			t.Errorf("%+v: got title %q after Symbolize, want %q", test.opts, rep.Title, test.title)
+		}
+		if got := reporter.Title([]byte("no crash")); got != "" {
+			t.Errorf("%+v: got title %q for output without crashes", test.opts, got)
+		}
+		// Reporters that don't parse crashes must work with the prefix as well.
+		netbsd, err := NewReporterOptions("netbsd", "", "", nil, nil, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep := netbsd.Parse(output); rep != nil {
+			t.Errorf("%+v: got netbsd report %+v", test.opts, rep)
+		}
+		if got := netbsd.Title(output); got != "" {
+			t.Errorf("%+v: got netbsd title %q", test.opts, got)
+		}
+	}
+}
+
+func TestDropCorrupted(t *testing.T) {
+	corrupted := []byte(`
+[ 1722.511384] general protection fault: 0000 [#1] SMP KASAN
+[ 1722.511384] Modules linked in:
+`)
+	good := []byte(`
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep := reporter.Parse(corrupted); rep == nil || !rep.Corrupted {
+		t.Fatalf("corrupted report is not returned by default: %+v", rep)
+	}
+	strict, err := NewReporterOptions("linux", "", "", nil, nil, Options{DropCorrupted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep := strict.Parse(corrupted); rep != nil {
+		t.Fatalf("corrupted report is returned: %+v", rep)
+	}
+	if title := strict.Title(corrupted); title != "" {
+		t.Fatalf("got title %q for corrupted report", title)
+	}
+	if !strict.ContainsCrash(corrupted) {
+		t.Fatalf("ContainsCrash does not detect the crash")
+	}
+	const title = "KASAN: use-after-free Read in ip6_dst_store"
+	if rep := strict.Parse(good); rep == nil || rep.Title != title {
+		t.Fatalf("got report %+v, want title %q", rep, title)
+	}
+	if got := strict.Title(good); got != title {
+		t.Fatalf("got title %q, want %q", got, title)
+	}
+	netbsd, err := NewReporterOptions("netbsd", "", "", nil, nil, Options{DropCorrupted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep := netbsd.Parse(good); rep != nil {
+		t.Fatalf("got netbsd report %+v", rep)
+	}
+	if reps := ParseAll(netbsd, good); len(reps) != 0 {
+		t.Fatalf("got %v netbsd reports", len(reps))
+	}
+}
+
+func TestSeverityFunc(t *testing.T) {
+	logs := map[string]string{
+		"kasan": `
+[   12.345678] ==================================================================
+[   12.345678] BUG: KASAN: use-after-free in foo+0x10/0x20
+[   12.345678] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor0/4321
+`,
+		"ubsan": `
+[   12.345678] ================================================================================
+[   12.345678] UBSAN: Undefined behaviour in net/ipv4/tcp.c:123:4
+[   12.345678] signed integer overflow:
+`,
+		"executor": `
+panic: executor 0: failed: pthread_create failed (errno 11)
+`,
+		"soft-lockup": `
+[   12.345678] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [syz-executor7:16813]
+`,
+		"hard-lockup": `
+[   12.345678] NMI watchdog: Watchdog detected hard LOCKUP on cpu 1
+[   12.345678] RIP: 0010:snd_timer_interrupt+0x3a/0x7f0 sound/core/timer.c:750
+`,
+	}
+	tests := []struct {
+		fn   SeverityFunc
+		want map[string]Severity
+	}{
+		{
+			nil,
+			map[string]Severity{
+				"kasan":       SeverityHigh,
+				"ubsan":       SeverityLow,
+				"executor":    SeverityInfo,
+				"soft-lockup": SeverityMedium,
+				"hard-lockup": SeverityMedium,
+			},
+		},
+		{
+			func(format OopsInfo, title string) Severity {
+				if format.Header == "UBSAN:" {
+					return SeverityInfo
+				}
+				return DefaultSeverity(format, title)
+			},
+			map[string]Severity{
+				"kasan":    SeverityHigh,
+				"ubsan":    SeverityInfo,
+				"executor": SeverityInfo,
+			},
+		},
+		{
+			func(format OopsInfo, title string) Severity {
+				return SeverityMedium
+			},
+			map[string]Severity{
+				"kasan":    SeverityMedium,
+				"ubsan":    SeverityMedium,
+				"executor": SeverityMedium,
+			},
+		},
+		{
+			// Hard and soft lockups have different title families.
+			func(format OopsInfo, title string) Severity {
+				if strings.HasPrefix(title, "BUG: hard lockup") {
+					return SeverityHigh
+				}
+				if strings.HasPrefix(title, "BUG: soft lockup") {
+					return SeverityLow
+				}
+				return DefaultSeverity(format, title)
+			},
+			map[string]Severity{
+				"soft-lockup": SeverityLow,
+				"hard-lockup": SeverityHigh,
+			},
+		},
+	}
+	for i, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{SeverityFunc: test.fn})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, want := range test.want {
+			rep := reporter.Parse([]byte(logs[name]))
+			if rep == nil {
+				t.Fatalf("#%v: %v: no report", i, name)
+			}
+			if rep.Severity != want {
+				t.Errorf("#%v: %v: got severity %v, want %v (title %q)",
+					i, name, rep.Severity, want, rep.Title)
+			}
+		}
+	}
+	// Reports graded differently by custom mappings are different for Equal.
+	defaultReporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customReporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{SeverityFunc: tests[2].fn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := []byte(logs["kasan"])
+	rep := defaultReporter.Parse(log)
+	if !rep.Equal(defaultReporter.Parse(log)) || rep.Equal(customReporter.Parse(log)) {
+		t.Fatalf("bad equality of reports with custom severity")
+	}
+}
+
+func TestNewReporterFromConfig(t *testing.T) {
+	const log = `
+[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/ipv4/tcp.c:123 foo_bar+0xe4/0x110
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/ipv4/tcp.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/ipv4/tcp.c:200
+`
+	reporter, err := NewReporterFromConfig(ReporterConfig{
+		OS: "linux",
+		Options: Options{
+			TitleOS: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := reporter.Parse([]byte(log))
+	if rep == nil || rep.Title != "linux/WARNING in foo_bar" {
+		t.Fatalf("bad report: %+v", rep)
+	}
+	// Ignores and embedded options can be set directly.
+	cfg := ReporterConfig{OS: "linux", Ignores: []*regexp.Regexp{regexp.MustCompile("foo_bar")}}
+	cfg.Arch = "arm64"
+	if reporter, err = NewReporterFromConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if reporter.ContainsCrash([]byte(log)) {
+		t.Fatalf("ignored crash is detected")
+	}
+	// The config is validated the same way as with NewReporterOptions.
+	if _, err := NewReporterFromConfig(ReporterConfig{OS: "unknown"}); err == nil {
+		t.Fatalf("created reporter for unknown OS")
+	}
+	cfg.Arch = "unknown"
+	if _, err := NewReporterFromConfig(cfg); err == nil {
+		t.Fatalf("created reporter for unknown arch")
+	}
+}
+
+func TestOnlyFamilies(t *testing.T) {
+	logs := map[string]string{
+		"KASAN: use-after-free Read in foo": `
+[   12.345678] BUG: KASAN: use-after-free in foo+0x10/0x20
+[   12.345678] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor0/4321
+[   12.345678] Call Trace:
+[   12.345678]  foo+0x10/0x20 net/core/foo.c:10
+[   12.345678]  bar+0x10/0x20 net/core/bar.c:20
+`,
+		"BUG: soft lockup": `
+[   12.345678] watchdog: BUG: soft lockup - CPU#1 stuck for 22s! [syz-executor7:16813]
+`,
+		"WARNING in foo": `
+[   12.345678] WARNING: CPU: 1 PID: 4321 at net/core/foo.c:10 foo+0x10/0x20
+[   12.345678] Call Trace:
+[   12.345678]  foo+0x10/0x20 net/core/foo.c:10
+[   12.345678]  bar+0x10/0x20 net/core/bar.c:20
+`,
+		"general protection fault in foo": `
+[   12.345678] general protection fault: 0000 [#1] SMP KASAN
+[   12.345678] RIP: 0010:foo+0x10/0x20 net/core/foo.c:10
+`,
+		"KASAN: use-after-free Read in baz": `
+[   12.345678] BUG: sleeping function called from invalid context at net/core/foo.c:10
+[   12.345678] BUG: KASAN: use-after-free in baz+0x10/0x20
+[   12.345678] Read of size 4 at addr ffff8801c6a1a080 by task syz-executor0/4321
+`,
+	}
+	tests := []struct {
+		families []string
+		titles   []string
+	}{
+		{
+			nil,
+			[]string{"KASAN: use-after-free Read in foo", "BUG: soft lockup", "WARNING in foo",
+				"general protection fault in foo",
+				"BUG: sleeping function called from invalid context at net/core/foo.c:LINE"},
+		},
+		{
+			// Only KASAN reports are detected, the BUG line preceding the KASAN report is ignored.
+			[]string{"KASAN"},
+			[]string{"KASAN: use-after-free Read in foo", "", "", "", "KASAN: use-after-free Read in baz"},
+		},
+		{
+			[]string{"KASAN", "hang"},
+			[]string{"KASAN: use-after-free Read in foo", "BUG: soft lockup", "", "",
+				"KASAN: use-after-free Read in baz"},
+		},
+	}
+	names := []string{"KASAN: use-after-free Read in foo", "BUG: soft lockup", "WARNING in foo",
+		"general protection fault in foo", "KASAN: use-after-free Read in baz"}
+	for i, test := range tests {
+		reporter, err := NewReporterOptions("linux", "", "", nil, nil, Options{OnlyFamilies: test.families})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, name := range names {
+			log := []byte(logs[name])
+			title := ""
+			if rep := reporter.Parse(log); rep != nil {
+				title = rep.Title
+			}
+			if title != test.titles[j] {
+				t.Errorf("#%v: %q: got title %q, want %q", i, name, title, test.titles[j])
+			}
+			if reporter.ContainsCrash(log) != (test.titles[j] != "") {
+				t.Errorf("#%v: %q: ContainsCrash is inconsistent with Parse", i, name)
+			}
+		}
+	}
+	for _, family := range CrashFamilies() {
+		if _, err := NewReporterOptions("linux", "", "", nil, nil,
+			Options{OnlyFamilies: []string{family}}); err != nil {
+			t.Errorf("family %q: %v", family, err)
+		}
+	}
+	if _, err := NewReporterOptions("linux", "", "", nil, nil,
+		Options{OnlyFamilies: []string{"KASAN", "foo"}}); err == nil {
+		t.Fatalf("no error for unknown family")
+	}
+}
+
+func TestContainsCrashDetailedNoParsing(t *testing.T) {
+	// OSes without crash parsing must be usable with the detailed API as well.
+	reporter, err := NewReporter("netbsd", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, suppressed, ignored := reporter.ContainsCrashDetailed([]byte("panic: foo\n")); found ||
+		suppressed != 0 || ignored != 0 {
+		t.Fatalf("got found %v, suppressed %v, ignored %v", found, suppressed, ignored)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+)
+
+const (
+	// streamPrefixLines is the number of lines preceding an oops that are kept
+	// for the report (Parse prepends up to 5 preceding lines to the report).
+	streamPrefixLines = 5
+	// streamMaxRegion limits size of a buffered oops region, if a region grows larger
+	// (e.g. the end marker is lost), it is parsed and emitted at this point.
+	streamMaxRegion = 1 << 20
+)
+
+// ParseStream reads console output from r and calls emit for each crash as soon as
+// the crash region is complete: when the oops end marker is seen (e.g. "---[ end trace ... ]---"
+// or "Kernel Offset:" on linux), when an oops that is not a consequence of the crash in the region
+// starts (e.g. a WARNING after a KASAN report), when a printk suppression notice is seen,
+// when the region exceeds 1MB, or when r is exhausted.
+// The markers are specific to the OS of the reporter, for OSes without known markers
+// regions are completed only by the size limit and the end of r.
+// Only the current region is buffered (along with a few lines preceding the oops),
+// so memory consumption is bounded regardless of length of the output.
+// Lines split across reads are handled. Output, StartPos and EndPos of the emitted reports
+// refer to the region rather than to the whole output.
+// The returned error is the reading error, io.EOF is not considered an error.
+func ParseStream(reporter Reporter, r io.Reader, emit func(*Report)) error {
+	return ParseStreamTerminated(reporter, r, nil, emit)
+}
+
+// ParseStreamTerminated is ParseStream, but a crash region is completed by a line matching
+// any of terminators (e.g. a custom marker printed by a test harness after a crash) instead of
+// the OS oops end markers. The line that starts the region does not complete it.
+// If terminators are empty, the OS end markers are used.
+func ParseStreamTerminated(reporter Reporter, r io.Reader, terminators []*regexp.Regexp,
+	emit func(*Report)) error {
+	sp := newStreamParser(reporter, terminators, emit)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) != 0 {
+			sp.line(line)
+		}
+		if err != nil {
+			sp.flush()
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// regionMarker is implemented by reporters that know how crash regions are delimited
+// in console output of their OS (see ParseStream).
+type regionMarker interface {
+	// regionEnd returns whether line completes the current region.
+	regionEnd(line []byte) bool
+	// outputLost returns whether lots of output can be lost before line,
+	// so the line is not merged into the current region.
+	outputLost(line []byte) bool
+	// newCrash returns whether line starts a crash that is not a consequence of the crash
+	// in region, so region is complete before the line.
+	newCrash(region, line []byte) bool
+}
+
+// noRegionMarker is used for reporters without known region markers.
+type noRegionMarker struct{}
+
+func (noRegionMarker) regionEnd(line []byte) bool        { return false }
+func (noRegionMarker) outputLost(line []byte) bool       { return false }
+func (noRegionMarker) newCrash(region, line []byte) bool { return false }
+
+// regionMarkerOf returns region markers of reporter, looking through reporter wrappers.
+func regionMarkerOf(reporter Reporter) regionMarker {
+	switch r := reporter.(type) {
+	case regionMarker:
+		return r
+	case *corruptedDropper:
+		return regionMarkerOf(r.Reporter)
+	case *titlePrefixer:
+		return regionMarkerOf(r.Reporter)
+	}
+	return noRegionMarker{}
+}
+
+// streamParser splits console output fed line-by-line into crash regions (see ParseStream).
+type streamParser struct {
+	reporter Reporter
+	markers  regionMarker
+	emit     func(*Report)
+	// terminators override the region end markers if not empty.
+	terminators []*regexp.Regexp
+	prefix      [][]byte
+	region      []byte
+	// offset is the number of bytes fed so far, regionPos is offset of the current region.
+	offset    int
+	regionPos int
+}
+
+func newStreamParser(reporter Reporter, terminators []*regexp.Regexp, emit func(*Report)) *streamParser {
+	return &streamParser{
+		reporter:    reporter,
+		markers:     regionMarkerOf(reporter),
+		emit:        emit,
+		terminators: terminators,
+	}
+}
+
+// line processes the next line of output (including the trailing '\n', if any),
+// the line must not be modified afterwards.
+func (sp *streamParser) line(line []byte) {
+	// Oopses after a suppression notice (lots of output can be lost there)
+	// and unrelated oopses are not merged into the current region.
+	if sp.region != nil && (sp.markers.outputLost(line) || sp.markers.newCrash(sp.region, line)) {
+		sp.flush()
+	}
+	opened := false
+	switch {
+	case sp.region != nil:
+		sp.region = append(sp.region, line...)
+	case sp.reporter.ContainsCrash(line):
+		opened = true
+		sp.regionPos = sp.offset
+		for _, ln := range sp.prefix {
+			sp.regionPos -= len(ln)
+			sp.region = append(sp.region, ln...)
+		}
+		sp.prefix = nil
+		sp.region = append(sp.region, line...)
+	default:
+		sp.prefix = append(sp.prefix, line)
+		if len(sp.prefix) > streamPrefixLines {
+			sp.prefix = sp.prefix[1:]
+		}
+	}
+	sp.offset += len(line)
+	if sp.region != nil && (!opened && sp.terminated(line) || len(sp.region) > streamMaxRegion) {
+		sp.flush()
+	}
+}
+
+// terminated returns whether line completes the current region.
+func (sp *streamParser) terminated(line []byte) bool {
+	if len(sp.terminators) == 0 {
+		return sp.markers.regionEnd(line)
+	}
+	return matchAny(sp.terminators, line)
+}
+
+// flush parses and emits the current region, if any.
+func (sp *streamParser) flush() {
+	if sp.region == nil {
+		return
+	}
+	if rep := sp.reporter.Parse(sp.region); rep != nil {
+		sp.emit(rep)
+	}
+	sp.region = nil
+}
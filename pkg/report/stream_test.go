@@ -0,0 +1,202 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// chunkReader returns data in chunks of the given sizes (cycled),
+// read is the number of bytes returned so far.
+type chunkReader struct {
+	data   string
+	chunks []int
+	read   int
+	n      int
+	err    error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.read == len(r.data) {
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, io.EOF
+	}
+	size := r.chunks[r.n%len(r.chunks)]
+	r.n++
+	if size > len(p) {
+		size = len(p)
+	}
+	if size > len(r.data)-r.read {
+		size = len(r.data) - r.read
+	}
+	copy(p, r.data[r.read:r.read+size])
+	r.read += size
+	return size, nil
+}
+
+func TestParseStream(t *testing.T) {
+	const warning = `[   42.262162] ------------[ cut here ]------------
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.314898]  foo_ioctl+0x1bf/0x39f net/core/dev.c:200
+[   42.320059] ---[ end trace 9d5a4b3c2a1f0e7d ]---
+`
+	const kasan = `[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+`
+	noise := strings.Repeat("[   10.000000] random console noise\n", 20)
+	log := noise + warning + noise + kasan
+	secondStart := len(noise + warning + noise)
+	want := []string{
+		"WARNING in foo_bar",
+		"KASAN: use-after-free Read in ip6_dst_store",
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunks := range [][]int{{1}, {7}, {3, 100, 1, 17}} {
+		r := &chunkReader{data: log, chunks: chunks}
+		var titles []string
+		if err := ParseStream(reporter, r, func(rep *Report) {
+			if len(titles) == 0 && r.read >= secondStart {
+				t.Errorf("chunks %v: the first report is emitted after %v bytes", chunks, r.read)
+			}
+			if !strings.Contains(string(rep.Output), "cut here") && len(titles) == 0 {
+				t.Errorf("chunks %v: the first report lost preceding lines:\n%s", chunks, rep.Output)
+			}
+			titles = append(titles, rep.Title)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(titles, want) {
+			t.Fatalf("chunks %v: got reports %q, want %q", chunks, titles, want)
+		}
+	}
+	// The read error is returned, but the buffered report is still emitted.
+	readErr := errors.New("read error")
+	var titles []string
+	err = ParseStream(reporter, &chunkReader{data: noise + kasan, chunks: []int{5}, err: readErr},
+		func(rep *Report) { titles = append(titles, rep.Title) })
+	if err != readErr {
+		t.Fatalf("got error %v, want %v", err, readErr)
+	}
+	if !reflect.DeepEqual(titles, want[1:]) {
+		t.Fatalf("got reports %q, want %q", titles, want[1:])
+	}
+}
+
+func TestParseStreamTerminated(t *testing.T) {
+	// Without the end trace line the two WARNINGs would form a single region.
+	const log = `[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.320059] ### harness: crash done ###
+[   67.392145] WARNING: CPU: 0 PID: 4496 at net/ipv4/tcp.c:456 tcp_baz+0x1a/0x30
+[   67.392150] Call Trace:
+[   67.392150]  tcp_baz+0x1a/0x30 net/ipv4/tcp.c:456
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		terminators []*regexp.Regexp
+		want        []string
+	}{
+		{nil, []string{"WARNING in foo_bar"}},
+		{
+			[]*regexp.Regexp{regexp.MustCompile(`### harness: crash done ###`)},
+			[]string{"WARNING in foo_bar", "WARNING in tcp_baz"},
+		},
+		{
+			// The line that starts the region does not complete it.
+			[]*regexp.Regexp{regexp.MustCompile(`WARNING: `)},
+			[]string{"WARNING in foo_bar"},
+		},
+	}
+	for i, test := range tests {
+		var titles []string
+		if err := ParseStreamTerminated(reporter, strings.NewReader(log), test.terminators,
+			func(rep *Report) {
+				if !strings.Contains(string(rep.Report), "Call Trace:") {
+					t.Errorf("#%v: the report is cut:\n%s", i, rep.Report)
+				}
+				titles = append(titles, rep.Title)
+			}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(titles, test.want) {
+			t.Fatalf("#%v: got reports %q, want %q", i, titles, test.want)
+		}
+	}
+}
+
+func TestParseStreamBackToBack(t *testing.T) {
+	// Neither of the crashes has an end trace line.
+	const log = `[   67.392145] ==================================================================
+[   67.392145] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150] Read of size 8 at addr ffff8801c6a1a080 by task syz-executor3/4496
+[   67.392150] Call Trace:
+[   67.392150]  ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+[   67.392150]  ip6_sk_dst_store_flow+0x7d/0xa0 net/ipv6/ip6_output.c:1166
+[   67.392150] ==================================================================
+[   68.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   68.274727] Modules linked in:
+[   68.306981] Call Trace:
+[   68.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   68.320059] Kernel panic - not syncing: panic_on_warn set ...
+[   68.320060] Kernel Offset: disabled
+[   69.000000] BUG: unable to handle kernel paging request at ffff88002bde1e40
+[   69.000000] IP: [<ffffffff82d4e304>] __memset+0x24/0x30
+[   69.000000] Oops: 0002 [#1] SMP DEBUG_PAGEALLOC KASAN
+[   69.000000] Call Trace:
+[   69.000000]  [<ffffffff81583d3f>] mm_release+0x1af/0x2f0 kernel/fork.c:1086
+`
+	want := []string{
+		"KASAN: use-after-free Read in ip6_dst_store",
+		"WARNING in foo_bar",
+		"BUG: unable to handle kernel paging request in __memset",
+	}
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	if err := ParseStream(reporter, strings.NewReader(log), func(rep *Report) {
+		titles = append(titles, rep.Title)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Fatalf("got reports %q, want %q", titles, want)
+	}
+	// Linux end markers don't complete regions of other OSes.
+	freebsd, err := NewReporter("freebsd", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reports []*Report
+	if err := ParseStream(freebsd, strings.NewReader("panic: ffs_write: type 0xfffff80036993340 10 (0,3)\n"+
+		"---[ end trace 9d5a4b3c2a1f0e7d ]---\nmore output\n"), func(rep *Report) {
+		reports = append(reports, rep)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || !strings.Contains(string(reports[0].Output), "more output") {
+		t.Fatalf("bad freebsd reports: %+v", reports)
+	}
+}
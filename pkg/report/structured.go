@@ -0,0 +1,125 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParsedOops is a structured representation of the whole parsed crash for consumers
+// that want all extracted information at once (see ParseStructured).
+// Most fields are copied from Report (which is referenced for the rest of the information),
+// registers, modules and PID are additionally extracted from the report text.
+type ParsedOops struct {
+	Title    string
+	Severity Severity
+	// Corrupted and CorruptedReason are the same as in Report.
+	Corrupted       bool
+	CorruptedReason string
+	// Frames and AuxStacks are the main and additional stack traces (see Report.Frames).
+	Frames    []StackFrame
+	AuxStacks []AuxStack
+	// Registers maps register names to values from the first register dump in the report,
+	// values are as printed (e.g. "RIP": "0010:foo+0x10/0x20", "RAX": "0000000000000001").
+	// Nil if the report does not contain registers.
+	Registers map[string]string
+	// Modules are loaded modules from "Modules linked in:" line (with flags, e.g. "foo(O)").
+	Modules []string
+	// RCUProtection and RCULock are the same as in Report.
+	RCUProtection string
+	RCULock       string
+	// Comm and PID are the current task from "CPU: 0 PID: 123 Comm: foo" line (PID is 0 if not present).
+	Comm string
+	PID  int
+	// Report is the report the structured representation is built from.
+	Report *Report
+}
+
+// ParseStructured parses output with reporter and returns the structured representation
+// of the crash, or nil if there is no crash. Registers are extracted only for linux.
+func ParseStructured(reporter Reporter, output []byte) *ParsedOops {
+	rep := reporter.Parse(output)
+	if rep == nil {
+		return nil
+	}
+	return &ParsedOops{
+		Title:           rep.Title,
+		Severity:        rep.Severity,
+		Corrupted:       rep.Corrupted,
+		CorruptedReason: rep.CorruptedReason,
+		Frames:          rep.Frames,
+		AuxStacks:       rep.AuxStacks,
+		Registers:       extractRegisters(rep.Arch, rep.Report),
+		Modules:         extractModules(rep.Report),
+		RCUProtection:   rep.RCUProtection,
+		RCULock:         rep.RCULock,
+		Comm:            rep.Comm,
+		PID:             extractPID(rep.Report),
+		Report:          rep,
+	}
+}
+
+// extractRegisters returns registers from the first register dump in report.
+// Register lines are recognized as for the architecture of the report,
+// so reports without Arch (non-linux) don't have registers.
+func extractRegisters(arch string, report []byte) map[string]string {
+	if arch == "" {
+		return nil
+	}
+	regs := linuxArch(arch).regs
+	var res map[string]string
+	dump := false
+	for _, line := range bytes.Split(report, []byte{'\n'}) {
+		if !regs.Match(line) {
+			if dump {
+				// The first dump is over.
+				break
+			}
+			continue
+		}
+		// Register lines are interleaved with lines like "Code: ..." that don't contain registers.
+		for _, match := range registerRe.FindAllSubmatch(line, -1) {
+			dump = true
+			name := string(match[1])
+			if res == nil {
+				res = make(map[string]string)
+			}
+			if _, ok := res[name]; !ok {
+				res[name] = string(match[2])
+			}
+		}
+	}
+	return res
+}
+
+func extractModules(report []byte) []string {
+	match := modulesRe.FindSubmatch(report)
+	if match == nil {
+		return nil
+	}
+	modules := string(match[1])
+	if pos := strings.Index(modules, "[last unloaded:"); pos != -1 {
+		modules = modules[:pos]
+	}
+	return strings.Fields(modules)
+}
+
+func extractPID(report []byte) int {
+	match := pidRe.FindSubmatch(report)
+	if match == nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(string(match[1]))
+	return pid
+}
+
+var (
+	registerRe = regexp.MustCompile(`(?:^|\s)([A-Z][A-Z0-9]{1,5}|[a-z][a-z0-9]{0,5}) ?: ?` +
+		`([0-9a-f]{4}:[^ \r]+|[^ \r]+)`)
+	modulesRe = regexp.MustCompile(`(?m)^Modules linked in:(.*)$`)
+	pidRe     = regexp.MustCompile(`CPU: [0-9]+ (?:UID: [0-9]+ )?PID: ([0-9]+) Comm: `)
+)
@@ -0,0 +1,80 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructured(t *testing.T) {
+	const log = `
+[   85.186292] general protection fault, probably for non-canonical address 0xdffffc0000000002: 0000 [#1] PREEMPT SMP KASAN
+[   85.197335] KASAN: null-ptr-deref in range [0x0000000000000010-0x0000000000000017]
+[   85.204858] CPU: 0 PID: 5092 Comm: syz-executor.3 Not tainted 6.2.0-rc7-syzkaller #0
+[   85.213093] Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 01/21/2023
+[   85.222440] RIP: 0010:tcp_v4_connect+0x4e4/0x1d90 net/ipv4/tcp_ipv4.c:312
+[   85.229399] Code: 48 89 fa 48 c1 ea 03 80 3c 02 00 0f 85 <80> 3c 02 00 0f 85 8b 13 00 00
+[   85.248294] RSP: 0018:ffffc90003e7f9b8 EFLAGS: 00010202
+[   85.253667] RAX: dffffc0000000000 RBX: 0000000000000000 RCX: 0000000000000000
+[   85.260936] RDX: 0000000000000002 RSI: ffffffff8a0b1c40 RDI: 0000000000000010
+[   85.268198] Call Trace:
+[   85.270780]  <TASK>
+[   85.273030]  tcp_v4_connect+0x4e4/0x1d90 net/ipv4/tcp_ipv4.c:312
+[   85.279382]  __inet_stream_connect+0x2a1/0xcd0 net/ipv4/af_inet.c:663
+[   85.285926]  inet_stream_connect+0x55/0xa0 net/ipv4/af_inet.c:727
+[   85.292197]  __sys_connect+0x161/0x190 net/socket.c:1996
+[   85.297760]  </TASK>
+[   85.300000] Modules linked in: vhost_net(O) tun [last unloaded: dummy]
+[   85.305844] ---[ end trace 0000000000000000 ]---
+`
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oops := ParseStructured(reporter, []byte(log))
+	if oops == nil {
+		t.Fatalf("no crash")
+	}
+	if want := "general protection fault in tcp_v4_connect"; oops.Title != want {
+		t.Errorf("got title %q, want %q", oops.Title, want)
+	}
+	if oops.Severity != SeverityHigh || oops.Corrupted {
+		t.Errorf("got severity %v, corrupted %v (%v)", oops.Severity, oops.Corrupted, oops.CorruptedReason)
+	}
+	if oops.Comm != "syz-executor.3" || oops.PID != 5092 {
+		t.Errorf("got task %q/%v", oops.Comm, oops.PID)
+	}
+	if want := []string{"vhost_net(O)", "tun"}; !reflect.DeepEqual(oops.Modules, want) {
+		t.Errorf("got modules %q, want %q", oops.Modules, want)
+	}
+	wantRegs := map[string]string{
+		"RIP":    "0010:tcp_v4_connect+0x4e4/0x1d90",
+		"RSP":    "0018:ffffc90003e7f9b8",
+		"EFLAGS": "00010202",
+		"RAX":    "dffffc0000000000",
+		"RBX":    "0000000000000000",
+		"RCX":    "0000000000000000",
+		"RDX":    "0000000000000002",
+		"RSI":    "ffffffff8a0b1c40",
+		"RDI":    "0000000000000010",
+	}
+	if !reflect.DeepEqual(oops.Registers, wantRegs) {
+		t.Errorf("got registers %v, want %v", oops.Registers, wantRegs)
+	}
+	var funcs []string
+	for _, frame := range oops.Frames {
+		funcs = append(funcs, frame.Func)
+	}
+	wantFuncs := []string{"tcp_v4_connect", "__inet_stream_connect", "inet_stream_connect", "__sys_connect"}
+	if !reflect.DeepEqual(funcs, wantFuncs) {
+		t.Errorf("got frames %q, want %q", funcs, wantFuncs)
+	}
+	if oops.Report == nil || oops.Report.Title != oops.Title {
+		t.Errorf("bad referenced report: %+v", oops.Report)
+	}
+	if oops := ParseStructured(reporter, []byte("no crashes here\n")); oops != nil {
+		t.Errorf("got crash for output without crashes: %+v", oops)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// ParseTerminated returns the first crash in output, whose region is detected as in ParseStreamTerminated:
+// the region ends at the first line after the oops header that matches any of terminators
+// (e.g. a custom "end of crash" marker printed by a test harness), the matching line is included.
+// Terminators override the OS end markers (e.g. "---[ end trace ... ]---" on linux), so the region
+// extends past them. If terminators are empty, the OS end markers are used.
+// Regions are limited to 1MB as in ParseStream.
+// As in ParseAll, StartPos and EndPos are relative to the whole output,
+// but Output of the report is truncated at the end of the region.
+func ParseTerminated(reporter Reporter, output []byte, terminators []*regexp.Regexp) *Report {
+	start, end := -1, -1
+	var sp *streamParser
+	sp = newStreamParser(reporter, terminators, func(rep *Report) {
+		if start == -1 {
+			start, end = sp.regionPos, sp.regionPos+len(sp.region)
+		}
+	})
+	for pos := 0; pos < len(output) && start == -1; {
+		next := bytes.IndexByte(output[pos:], '\n') + 1
+		if next == 0 {
+			next = len(output) - pos
+		}
+		sp.line(output[pos : pos+next])
+		pos += next
+	}
+	sp.flush()
+	if start == -1 {
+		return nil
+	}
+	return reporter.ParseFrom(output[:end], start)
+}
+
+func matchAny(res []*regexp.Regexp, line []byte) bool {
+	for _, re := range res {
+		if re.Match(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestParseTerminated(t *testing.T) {
+	const crash = `[   42.262162] random console noise
+[   42.266927] WARNING: CPU: 1 PID: 4340 at net/core/dev.c:123 foo_bar+0xe4/0x110
+[   42.274727] Modules linked in:
+[   42.306981] Call Trace:
+[   42.309554]  foo_bar+0xe4/0x110 net/core/dev.c:123
+[   42.320059] ---[ end trace 9d5a4b3c2a1f0e7d ]---
+[   42.320060] harness: collecting crash info
+[   42.320061] ### harness: crash done ###
+`
+	// Output after the marker belongs to the harness and must not get into the report
+	// unless the region extends past it.
+	const tail = `[   43.000000] harness cleanup: unmounting /dev/sda1
+[   44.000000] BUG: KASAN: use-after-free in ip6_dst_store+0x4e4/0x520 include/net/ip6_fib.h:175
+`
+	const noise = "[   10.000000] random console noise\n"
+	output := []byte(noise + crash + tail)
+	reporter, err := NewReporter("linux", "", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terminators := []*regexp.Regexp{
+		regexp.MustCompile(`=== END OF CRASH ===`),
+		regexp.MustCompile(`### harness: crash done ###`),
+	}
+	tests := []struct {
+		terminators []*regexp.Regexp
+		end         int
+	}{
+		// The end trace line does not complete the region with custom terminators.
+		{terminators, len(noise + crash)},
+		// Without terminators the OS end markers apply.
+		{nil, bytes.Index(output, []byte("[   42.320060]"))},
+		// Terminators that don't match don't bound the region, but the next unrelated oops does.
+		{terminators[:1], bytes.Index(output, []byte("[   44.000000]"))},
+	}
+	for i, test := range tests {
+		rep := ParseTerminated(reporter, output, test.terminators)
+		if rep == nil {
+			t.Fatalf("#%v: no report", i)
+		}
+		if want := "WARNING in foo_bar"; rep.Title != want {
+			t.Fatalf("#%v: got title %q, want %q", i, rep.Title, want)
+		}
+		if !bytes.Equal(rep.Output, output[:test.end]) {
+			t.Fatalf("#%v: got output:\n%s\nwant:\n%s", i, rep.Output, output[:test.end])
+		}
+		if start := bytes.Index(output, []byte("[   42.266927]")); rep.StartPos != start ||
+			rep.EndPos <= start || rep.EndPos > test.end {
+			t.Fatalf("#%v: bad report region %v-%v", i, rep.StartPos, rep.EndPos)
+		}
+		cleanup := []byte("harness cleanup")
+		if bytes.Contains(rep.Report, cleanup) != bytes.Contains(output[:test.end], cleanup) ||
+			!bytes.Contains(rep.Report, []byte("foo_bar")) {
+			t.Fatalf("#%v: bad report:\n%s", i, rep.Report)
+		}
+	}
+	if rep := ParseTerminated(reporter, []byte("no crash\n"), terminators); rep != nil {
+		t.Fatalf("got report %q for output without crash", rep.Title)
+	}
+}
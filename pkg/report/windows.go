@@ -4,6 +4,7 @@
 package report
 
 import (
+	"context"
 	"regexp"
 
 	"github.com/google/syzkaller/pkg/symbolizer"
@@ -17,7 +18,7 @@ type windows struct {
 }
 
 func ctorWindows(kernelSrc, kernelObj string, symbols map[string][]symbolizer.Symbol,
-	ignores []*regexp.Regexp) (Reporter, error) {
+	ignores []*regexp.Regexp, opts Options) (Reporter, error) {
 	ctx := &windows{
 		kernelSrc: kernelSrc,
 		kernelObj: kernelObj,
@@ -31,10 +32,34 @@ func (ctx *windows) ContainsCrash(output []byte) bool {
 	panic("not implemented")
 }
 
+func (ctx *windows) ContainsCrashDetailed(output []byte) (bool, int, int) {
+	panic("not implemented")
+}
+
 func (ctx *windows) Parse(output []byte) *Report {
 	panic("not implemented")
 }
 
+func (ctx *windows) ParseFrom(output []byte, startPos int) *Report {
+	panic("not implemented")
+}
+
+func (ctx *windows) Title(output []byte) string {
+	panic("not implemented")
+}
+
 func (ctx *windows) Symbolize(rep *Report) error {
 	panic("not implemented")
 }
+
+func (ctx *windows) SymbolizeContext(c context.Context, rep *Report) error {
+	panic("not implemented")
+}
+
+func (ctx *windows) SymbolizeFrame(frame *StackFrame) error {
+	panic("not implemented")
+}
+
+func (ctx *windows) Close() error {
+	return nil
+}
@@ -12,12 +12,15 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/google/syzkaller/pkg/osutil"
 )
 
 type Symbolizer struct {
+	mu       sync.Mutex
 	subprocs map[string]*subprocess
+	closed   bool
 }
 
 type Frame struct {
@@ -52,16 +55,28 @@ func (s *Symbolizer) SymbolizeArray(bin string, pcs []uint64) ([]Frame, error) {
 	return symbolize(sub.input, sub.scanner, pcs)
 }
 
+// Close kills all addr2line subprocesses.
+// It can be called concurrently with Symbolize to abort it,
+// subsequent Symbolize calls fail.
 func (s *Symbolizer) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
 	for _, sub := range s.subprocs {
 		sub.stdin.Close()
 		sub.stdout.Close()
 		sub.cmd.Process.Kill()
 		sub.cmd.Wait()
 	}
+	s.subprocs = nil
 }
 
 func (s *Symbolizer) getSubprocess(bin string) (*subprocess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("symbolizer is closed")
+	}
 	if sub := s.subprocs[bin]; sub != nil {
 		return sub, nil
 	}